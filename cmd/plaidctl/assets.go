@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func newAssetReportCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "asset-report",
+		Short: "creates and retrieves Asset Reports",
+	}
+
+	var days int
+	create := &cobra.Command{
+		Use:   "create ACCESS_TOKEN...",
+		Short: "creates an Asset Report from one or more Items",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			resp, err := client.CreateAssetReport(args, days)
+			if err != nil {
+				return err
+			}
+
+			r := result{value: resp, columns: []string{"ASSET_REPORT_ID", "ASSET_REPORT_TOKEN"}}
+			r.add(resp.AssetReportID, resp.AssetReportToken)
+			return a.print(r)
+		},
+	}
+	create.Flags().IntVar(&days, "days", 30, "number of days of history to include")
+
+	get := &cobra.Command{
+		Use:   "get ASSET_REPORT_TOKEN",
+		Short: "prints the accounts in an Asset Report",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			resp, err := client.GetAssetReport(args[0])
+			if err != nil {
+				return err
+			}
+
+			r := result{
+				value:   resp,
+				columns: []string{"ITEM_ID", "INSTITUTION", "ACCOUNT_ID", "NAME", "TYPE", "CURRENT", "CURRENCY", "DAYS_AVAILABLE"},
+			}
+			for _, item := range resp.Report.Items {
+				for _, account := range item.Accounts {
+					r.add(item.ItemID, item.InstitutionName, account.AccountID, account.Name, account.Type,
+						formatAmount(account.Balances.Current), string(account.Balances.Currency()),
+						strconv.Itoa(account.DaysAvailable))
+				}
+			}
+			return a.print(r)
+		},
+	}
+
+	cmd.AddCommand(create, get)
+	return cmd
+}
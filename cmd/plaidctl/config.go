@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// config is the config file, which holds a profile per set of credentials.
+type config struct {
+	DefaultProfile string             `yaml:"default_profile"`
+	Profiles       map[string]profile `yaml:"profiles"`
+}
+
+type profile struct {
+	Name        string `yaml:"-"`
+	Environment string `yaml:"environment"`
+	ClientID    string `yaml:"client_id"`
+	Secret      string `yaml:"secret"`
+	// APIVersion defaults to the latest version.
+	APIVersion string `yaml:"api_version"`
+}
+
+// loadConfig reads the config file at path. A missing file is only an error
+// when required is set, as the default path need not exist.
+func loadConfig(path string, required bool) (config, error) {
+	var c config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// configFile returns the config file to read, and whether it was chosen by
+// the user rather than defaulted.
+func (a *app) configFile() (string, bool) {
+	if a.configPath != "" {
+		return a.configPath, true
+	}
+	if path := a.getenv("PLAIDCTL_CONFIG"); path != "" {
+		return path, true
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(dir, "plaidctl", "config.yml"), false
+}
+
+// resolveProfile picks the profile to use and applies the overrides from the
+// flags and environment variables to it.
+func (a *app) resolveProfile() (profile, error) {
+	var c config
+	if path, required := a.configFile(); path != "" {
+		var err error
+		if c, err = loadConfig(path, required); err != nil {
+			return profile{}, err
+		}
+	}
+
+	environment := firstNonEmpty(a.environment, a.getenv("PLAID_ENV"))
+	name := firstNonEmpty(a.profile, a.getenv("PLAID_PROFILE"))
+	p, ok := c.Profiles[name]
+	if name != "" && !ok {
+		return profile{}, fmt.Errorf("unknown profile %q", name)
+	}
+	if name == "" {
+		name = firstNonEmpty(c.DefaultProfile, environment, "sandbox")
+		p = c.Profiles[name]
+	}
+
+	p.Name = name
+	p.Environment = firstNonEmpty(environment, p.Environment, name)
+	p.ClientID = firstNonEmpty(a.getenv("PLAID_CLIENT_ID"), p.ClientID)
+	p.Secret = firstNonEmpty(a.getenv("PLAID_SECRET"), p.Secret)
+	return p, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
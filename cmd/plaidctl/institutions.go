@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newInstitutionsCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "institutions",
+		Short: "looks up institutions",
+	}
+
+	var products, countryCodes []string
+	search := &cobra.Command{
+		Use:   "search QUERY",
+		Short: "prints the institutions matching a query",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			resp, err := client.SearchInstitutions(args[0], products, countryCodes)
+			if err != nil {
+				return err
+			}
+
+			r := result{
+				value:   resp,
+				columns: []string{"INSTITUTION_ID", "NAME", "COUNTRY_CODES", "PRODUCTS", "OAUTH"},
+			}
+			for _, institution := range resp.Institutions {
+				r.add(institution.ID, institution.Name, strings.Join(institution.CountryCodes, ","),
+					strings.Join(institution.Products, ","), strconv.FormatBool(institution.OAuth))
+			}
+			return a.print(r)
+		},
+	}
+	search.Flags().StringSliceVar(&products, "products", nil, "products the institutions must support")
+	search.Flags().StringSliceVar(&countryCodes, "country-codes", []string{"US"}, "countries to search institutions in")
+	cmd.AddCommand(search)
+	return cmd
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+	"github.com/spf13/cobra"
+)
+
+func newItemCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "item",
+		Short: "inspects Items",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get ACCESS_TOKEN",
+		Short: "prints an Item's status",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			resp, err := client.GetItem(args[0])
+			if err != nil {
+				return err
+			}
+
+			r := result{
+				value:   resp,
+				columns: []string{"ITEM_ID", "INSTITUTION_ID", "WEBHOOK", "BILLED_PRODUCTS", "ERROR"},
+			}
+			r.add(resp.Item.ItemID, resp.Item.InstitutionID, resp.Item.Webhook,
+				strings.Join(resp.Item.BilledProducts, ","), resp.Item.Error.ErrorCode)
+			return a.print(r)
+		},
+	})
+	return cmd
+}
+
+func newAccountsCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "accounts",
+		Short: "inspects an Item's accounts",
+	}
+
+	var accountIDs []string
+	balance := &cobra.Command{
+		Use:   "balance ACCESS_TOKEN",
+		Short: "prints the real-time balance of an Item's accounts",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			resp, err := client.GetBalancesWithOptions(args[0], plaid.GetBalancesOptions{AccountIDs: accountIDs})
+			if err != nil {
+				return err
+			}
+
+			r := result{
+				value:   resp,
+				columns: []string{"ACCOUNT_ID", "NAME", "MASK", "TYPE", "SUBTYPE", "AVAILABLE", "CURRENT", "CURRENCY"},
+			}
+			for _, account := range resp.Accounts {
+				r.add(account.AccountID, account.Name, account.Mask, account.Type, account.Subtype,
+					formatAmount(account.Balances.Available), formatAmount(account.Balances.Current),
+					string(account.Balances.Currency()))
+			}
+			return a.print(r)
+		},
+	}
+	balance.Flags().StringSliceVar(&accountIDs, "account-id", nil, "accounts to include, defaulting to all of them")
+	cmd.AddCommand(balance)
+	return cmd
+}
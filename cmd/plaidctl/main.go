@@ -0,0 +1,108 @@
+// Command plaidctl calls the Plaid API from the command line.
+//
+// Credentials are read from the profile named by --profile or PLAID_PROFILE
+// in the config file, and can be overridden with PLAID_CLIENT_ID,
+// PLAID_SECRET and PLAID_ENV. Results are printed as a table, or as JSON or
+// CSV with --output.
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	a := &app{stdout: os.Stdout, getenv: os.Getenv}
+	if err := newRootCmd(a).Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "plaidctl: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+// app holds what the commands share: the global flags and the process
+// environment, which tests replace.
+type app struct {
+	stdout     io.Writer
+	getenv     func(string) string
+	httpClient *http.Client
+
+	configPath  string
+	profile     string
+	environment string
+	output      string
+}
+
+func newRootCmd(a *app) *cobra.Command {
+	root := &cobra.Command{
+		Use:   "plaidctl",
+		Short: "Command-line client for the Plaid API",
+		Long: `Calls the Plaid API from the command line.
+
+Credentials are read from a profile in the config file, which defaults to
+plaidctl/config.yml in the user's config directory, or PLAIDCTL_CONFIG:
+
+    default_profile: sandbox
+    profiles:
+      sandbox:
+        environment: sandbox
+        client_id: ...
+        secret: ...
+
+The profile is chosen with --profile or PLAID_PROFILE, and otherwise is the
+default profile, or the one named after the environment. PLAID_CLIENT_ID,
+PLAID_SECRET and PLAID_ENV override the profile's values.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.configPath, "config", "", "config file to read profiles from")
+	flags.StringVar(&a.profile, "profile", "", "profile to read credentials from")
+	flags.StringVar(&a.environment, "env", "", "Plaid environment (sandbox|development|production) or API URL")
+	flags.StringVarP(&a.output, "output", "o", "table", "output format (table|json|csv)")
+
+	root.AddCommand(
+		newItemCmd(a),
+		newAccountsCmd(a),
+		newTransactionsCmd(a),
+		newInstitutionsCmd(a),
+		newAssetReportCmd(a),
+		newPaymentCmd(a),
+		newSandboxCmd(a),
+	)
+	root.SetOut(a.stdout)
+	return root
+}
+
+// client creates a client from the resolved profile.
+func (a *app) client() (*plaid.Client, error) {
+	profile, err := a.resolveProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	environment, err := plaid.ParseEnvironment(profile.Environment)
+	if err != nil {
+		if !strings.HasPrefix(profile.Environment, "http://") && !strings.HasPrefix(profile.Environment, "https://") {
+			return nil, fmt.Errorf("unknown environment %q", profile.Environment)
+		}
+		environment = plaid.Environment(strings.TrimSuffix(profile.Environment, "/"))
+	}
+	if profile.ClientID == "" || profile.Secret == "" {
+		return nil, fmt.Errorf("no credentials for profile %q, set them in the config file or PLAID_CLIENT_ID and PLAID_SECRET", profile.Name)
+	}
+
+	return plaid.NewClient(plaid.ClientOptions{
+		ClientID:    profile.ClientID,
+		Secret:      profile.Secret,
+		Environment: environment,
+		HTTPClient:  a.httpClient,
+		APIVersion:  profile.APIVersion,
+	})
+}
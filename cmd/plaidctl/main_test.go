@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+type fakeHandler func(body map[string]interface{}) (int, interface{})
+
+// fakePlaid serves canned responses, recording the requests it was sent.
+type fakePlaid struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]fakeHandler
+	requests map[string][]map[string]interface{}
+}
+
+func newFakePlaid(t *testing.T) *fakePlaid {
+	f := &fakePlaid{
+		handlers: map[string]fakeHandler{},
+		requests: map[string][]map[string]interface{}{},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		f.mu.Lock()
+		h, ok := f.handlers[r.URL.Path]
+		f.requests[r.URL.Path] = append(f.requests[r.URL.Path], body)
+		f.mu.Unlock()
+
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error_type": "INVALID_REQUEST", "error_code": "NOT_FOUND"})
+			return
+		}
+		status, resp := h(body)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakePlaid) handle(endpoint string, h fakeHandler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[endpoint] = h
+}
+
+func (f *fakePlaid) sent(endpoint string) []map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[endpoint]
+}
+
+// run runs plaidctl against f with credentials from the environment, and
+// returns what it printed.
+func run(t *testing.T, f *fakePlaid, args ...string) (string, error) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	assert.NoError(t, os.WriteFile(path, nil, 0600))
+	env := map[string]string{
+		"PLAIDCTL_CONFIG": path,
+		"PLAID_CLIENT_ID": "client-id",
+		"PLAID_SECRET":    "secret",
+		"PLAID_ENV":       f.URL,
+	}
+	return runWithEnv(t, env, f, args...)
+}
+
+func runWithEnv(t *testing.T, env map[string]string, f *fakePlaid, args ...string) (string, error) {
+	var out bytes.Buffer
+	a := &app{
+		stdout:     &out,
+		getenv:     func(key string) string { return env[key] },
+		httpClient: f.Client(),
+	}
+	cmd := newRootCmd(a)
+	cmd.SetArgs(args)
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestItemGet(t *testing.T) {
+	f := newFakePlaid(t)
+	f.handle("/item/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"item": map[string]interface{}{
+				"item_id":         "item-1",
+				"institution_id":  "ins_1",
+				"webhook":         "https://example.com/plaid",
+				"billed_products": []string{"auth", "transactions"},
+			},
+		}
+	})
+
+	out, err := run(t, f, "item", "get", "access-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ITEM_ID  INSTITUTION_ID  WEBHOOK                    BILLED_PRODUCTS    ERROR\n"+
+		"item-1   ins_1           https://example.com/plaid  auth,transactions  \n", out)
+	assert.Equal(t, "access-1", f.sent("/item/get")[0]["access_token"])
+	assert.Equal(t, "client-id", f.sent("/item/get")[0]["client_id"])
+
+	out, err = run(t, f, "item", "get", "access-1", "-o", "json")
+	assert.NoError(t, err)
+	var resp map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, "item-1", resp["item"].(map[string]interface{})["item_id"])
+
+	_, err = run(t, f, "item", "get")
+	assert.Error(t, err)
+}
+
+// TestJSONOutputOnStdout runs plaidctl with the process's real stdout, as
+// main does, so that anything else writing to stdout, such as the plaid
+// package warning about the API URL given with --env, breaks the JSON.
+func TestJSONOutputOnStdout(t *testing.T) {
+	f := newFakePlaid(t)
+	f.handle("/item/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"item": map[string]interface{}{"item_id": "item-1"}}
+	})
+
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	env := map[string]string{"PLAID_CLIENT_ID": "client-id", "PLAID_SECRET": "secret"}
+	path := filepath.Join(t.TempDir(), "config.yml")
+	assert.NoError(t, os.WriteFile(path, nil, 0600))
+	a := &app{stdout: os.Stdout, getenv: func(key string) string { return env[key] }, httpClient: f.Client()}
+	cmd := newRootCmd(a)
+	cmd.SetArgs([]string{"item", "get", "access-1", "--config", path, "--env", f.URL, "-o", "json"})
+	cmd.SetErr(&bytes.Buffer{})
+	assert.NoError(t, cmd.Execute())
+
+	os.Stdout = stdout
+	assert.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	var resp map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out, &resp), "stdout: %s", out)
+	assert.Equal(t, "item-1", resp["item"].(map[string]interface{})["item_id"])
+}
+
+func TestAccountsBalance(t *testing.T) {
+	f := newFakePlaid(t)
+	f.handle("/accounts/balance/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"accounts": []interface{}{map[string]interface{}{
+				"account_id": "acc-1",
+				"name":       "Checking",
+				"mask":       "0000",
+				"type":       "depository",
+				"subtype":    "checking",
+				"balances":   map[string]interface{}{"available": 100, "current": 110.5, "iso_currency_code": "USD"},
+			}},
+		}
+	})
+
+	out, err := run(t, f, "accounts", "balance", "access-1", "--account-id", "acc-1", "-o", "csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "ACCOUNT_ID,NAME,MASK,TYPE,SUBTYPE,AVAILABLE,CURRENT,CURRENCY\n"+
+		"acc-1,Checking,0000,depository,checking,100.00,110.50,USD\n", out)
+	options := f.sent("/accounts/balance/get")[0]["options"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"acc-1"}, options["account_ids"])
+}
+
+func TestTransactionsGet(t *testing.T) {
+	f := newFakePlaid(t)
+	f.handle("/transactions/get", func(body map[string]interface{}) (int, interface{}) {
+		options := body["options"].(map[string]interface{})
+		// Zero offsets are left out of the request.
+		offset, _ := options["offset"].(float64)
+		count, _ := options["count"].(float64)
+		var transactions []interface{}
+		for i := int(offset); i < int(offset+count) && i < 5; i++ {
+			transactions = append(transactions, map[string]interface{}{
+				"transaction_id":    "txn-" + string(rune('0'+i)),
+				"date":              "2021-03-01",
+				"name":              "Coffee",
+				"amount":            4.5,
+				"iso_currency_code": "USD",
+			})
+		}
+		return http.StatusOK, map[string]interface{}{"transactions": transactions, "total_transactions": 5}
+	})
+
+	out, err := run(t, f, "transactions", "get", "access-1", "--start", "2021-03-01", "--end", "2021-03-31", "--count", "2", "-o", "csv")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, bytes.Count([]byte(out), []byte("\n")))
+	assert.Len(t, f.sent("/transactions/get"), 1)
+
+	out, err = run(t, f, "transactions", "get", "access-1", "--start", "2021-03-01", "--end", "2021-03-31", "--count", "2", "--offset", "1", "--all", "-o", "csv")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, bytes.Count([]byte(out), []byte("\n")))
+	assert.Len(t, f.sent("/transactions/get"), 3)
+
+	_, err = run(t, f, "transactions", "get", "access-1", "--start", "2021-03-01")
+	assert.EqualError(t, err, "--start and --end must be specified")
+}
+
+func TestInstitutionsSearch(t *testing.T) {
+	f := newFakePlaid(t)
+	f.handle("/institutions/search", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"institutions": []interface{}{map[string]interface{}{
+				"institution_id": "ins_1",
+				"name":           "First Platypus Bank",
+				"country_codes":  []string{"US"},
+				"products":       []string{"auth"},
+			}},
+		}
+	})
+
+	out, err := run(t, f, "institutions", "search", "platypus", "--products", "auth", "-o", "csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "INSTITUTION_ID,NAME,COUNTRY_CODES,PRODUCTS,OAUTH\nins_1,First Platypus Bank,US,auth,false\n", out)
+	assert.Equal(t, "platypus", f.sent("/institutions/search")[0]["query"])
+}
+
+func TestAssetReport(t *testing.T) {
+	f := newFakePlaid(t)
+	f.handle("/asset_report/create", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"asset_report_id": "report-1", "asset_report_token": "assets-1"}
+	})
+	f.handle("/asset_report/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"report": map[string]interface{}{
+				"items": []interface{}{map[string]interface{}{
+					"item_id":          "item-1",
+					"institution_name": "First Platypus Bank",
+					"accounts": []interface{}{map[string]interface{}{
+						"account_id":     "acc-1",
+						"name":           "Checking",
+						"type":           "depository",
+						"days_available": 30,
+						"balances":       map[string]interface{}{"current": 110.5, "iso_currency_code": "USD"},
+					}},
+				}},
+			},
+		}
+	})
+
+	out, err := run(t, f, "asset-report", "create", "access-1", "access-2", "--days", "60", "-o", "csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "ASSET_REPORT_ID,ASSET_REPORT_TOKEN\nreport-1,assets-1\n", out)
+	assert.Equal(t, []interface{}{"access-1", "access-2"}, f.sent("/asset_report/create")[0]["access_tokens"])
+	assert.Equal(t, float64(60), f.sent("/asset_report/create")[0]["days_requested"])
+
+	out, err = run(t, f, "asset-report", "get", "assets-1", "-o", "csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "ITEM_ID,INSTITUTION,ACCOUNT_ID,NAME,TYPE,CURRENT,CURRENCY,DAYS_AVAILABLE\n"+
+		"item-1,First Platypus Bank,acc-1,Checking,depository,110.50,USD,30\n", out)
+}
+
+func TestPaymentCreate(t *testing.T) {
+	f := newFakePlaid(t)
+	f.handle("/payment_initiation/payment/create", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"payment_id": "payment-1", "status": "PAYMENT_STATUS_INPUT_NEEDED"}
+	})
+
+	out, err := run(t, f, "payment", "create", "--recipient-id", "recipient-1", "--reference", "invoice 1", "--amount", "12.5", "-o", "csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "PAYMENT_ID,STATUS\npayment-1,PAYMENT_STATUS_INPUT_NEEDED\n", out)
+	assert.Equal(t, map[string]interface{}{"currency": "GBP", "value": 12.5}, f.sent("/payment_initiation/payment/create")[0]["amount"])
+
+	_, err = run(t, f, "payment", "create", "--recipient-id", "recipient-1", "--reference", "invoice 1")
+	assert.Error(t, err)
+	assert.Len(t, f.sent("/payment_initiation/payment/create"), 1)
+}
+
+func TestSandboxPublicToken(t *testing.T) {
+	f := newFakePlaid(t)
+	f.handle("/sandbox/public_token/create", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"public_token": "public-1"}
+	})
+	f.handle("/item/public_token/exchange", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"access_token": "access-1", "item_id": "item-1"}
+	})
+
+	out, err := run(t, f, "sandbox", "public-token", "-o", "csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "PUBLIC_TOKEN\npublic-1\n", out)
+	assert.Equal(t, "ins_109508", f.sent("/sandbox/public_token/create")[0]["institution_id"])
+
+	out, err = run(t, f, "sandbox", "public-token", "--exchange", "--products", "auth", "-o", "csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "ITEM_ID,ACCESS_TOKEN\nitem-1,access-1\n", out)
+	assert.Equal(t, []interface{}{"auth"}, f.sent("/sandbox/public_token/create")[1]["initial_products"])
+}
+
+func TestPlaidErrors(t *testing.T) {
+	f := newFakePlaid(t)
+	f.handle("/item/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusBadRequest, map[string]interface{}{"error_type": "INVALID_INPUT", "error_code": "INVALID_ACCESS_TOKEN"}
+	})
+
+	_, err := run(t, f, "item", "get", "access-1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "INVALID_ACCESS_TOKEN")
+
+	_, err = run(t, f, "item", "get", "access-1", "-o", "xml")
+	assert.Error(t, err)
+}
+
+func TestProfiles(t *testing.T) {
+	f := newFakePlaid(t)
+	f.handle("/item/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"item": map[string]interface{}{"item_id": "item-1"}}
+	})
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+default_profile: test
+profiles:
+  test:
+    environment: `+f.URL+`
+    client_id: test-client
+    secret: test-secret
+  other:
+    environment: `+f.URL+`
+    client_id: other-client
+    secret: other-secret
+`), 0600))
+
+	env := map[string]string{"PLAIDCTL_CONFIG": path}
+	_, err := runWithEnv(t, env, f, "item", "get", "access-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-client", f.sent("/item/get")[0]["client_id"])
+
+	_, err = runWithEnv(t, env, f, "item", "get", "access-1", "--profile", "other")
+	assert.NoError(t, err)
+	assert.Equal(t, "other-client", f.sent("/item/get")[1]["client_id"])
+
+	env["PLAID_SECRET"] = "env-secret"
+	_, err = runWithEnv(t, env, f, "item", "get", "access-1", "--profile", "other")
+	assert.NoError(t, err)
+	assert.Equal(t, "other-client", f.sent("/item/get")[2]["client_id"])
+	assert.Equal(t, "env-secret", f.sent("/item/get")[2]["secret"])
+
+	_, err = runWithEnv(t, env, f, "item", "get", "access-1", "--profile", "missing")
+	assert.EqualError(t, err, `unknown profile "missing"`)
+
+	_, err = runWithEnv(t, map[string]string{"PLAIDCTL_CONFIG": path}, f, "item", "get", "access-1", "--env", "staging")
+	assert.EqualError(t, err, `unknown environment "staging"`)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// result is what a command prints: value as JSON, or columns and rows as a
+// table or CSV.
+type result struct {
+	value   interface{}
+	columns []string
+	rows    [][]string
+}
+
+func (r *result) add(row ...string) {
+	r.rows = append(r.rows, row)
+}
+
+func (a *app) print(r result) error {
+	return writeResult(a.stdout, a.output, r)
+}
+
+func writeResult(w io.Writer, format string, r result) error {
+	switch format {
+	case "json":
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(r.value)
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(r.columns); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(r.rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(r.columns, "\t"))
+		for _, row := range r.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+	"github.com/spf13/cobra"
+)
+
+func newPaymentCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "payment",
+		Short: "initiates payments",
+	}
+
+	var recipientID, reference, currency, idempotencyKey string
+	var amount float64
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "creates a payment to a recipient, to be authorised by the user in Link",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if recipientID == "" || reference == "" {
+				return fmt.Errorf("--recipient-id and --reference must be specified")
+			}
+			paymentAmount := plaid.PaymentAmount{Currency: plaid.Currency(currency), Value: amount}
+			if !paymentAmount.Currency.Valid() || amount <= 0 {
+				return fmt.Errorf("--amount must be positive and --currency a valid currency")
+			}
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			resp, err := client.CreatePaymentWithOptions(recipientID, reference, paymentAmount, plaid.CreatePaymentOptions{
+				IdempotencyKey: idempotencyKey,
+			})
+			if err != nil {
+				return err
+			}
+
+			r := result{value: resp, columns: []string{"PAYMENT_ID", "STATUS"}}
+			r.add(resp.PaymentID, string(resp.Status))
+			return a.print(r)
+		},
+	}
+
+	flags := create.Flags()
+	flags.StringVar(&recipientID, "recipient-id", "", "recipient to pay")
+	flags.StringVar(&reference, "reference", "", "reference shown on the recipient's statement")
+	flags.Float64Var(&amount, "amount", 0, "amount to pay")
+	flags.StringVar(&currency, "currency", "GBP", "currency of the amount")
+	flags.StringVar(&idempotencyKey, "idempotency-key", "", "key deduplicating retried payments")
+	cmd.AddCommand(create)
+	return cmd
+}
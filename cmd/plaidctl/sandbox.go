@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/perchcredit/plaid-go/v6/plaid/linkserver"
+	"github.com/spf13/cobra"
+)
+
+func newSandboxCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sandbox",
+		Short: "creates test data in the sandbox",
+	}
+
+	var institutionID string
+	var products []string
+	var exchange bool
+	publicToken := &cobra.Command{
+		Use:   "public-token",
+		Short: "creates a public token for a sandbox Item, without Link",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			resp, err := client.CreateSandboxPublicToken(institutionID, products)
+			if err != nil {
+				return err
+			}
+			if !exchange {
+				r := result{value: resp, columns: []string{"PUBLIC_TOKEN"}}
+				r.add(resp.PublicToken)
+				return a.print(r)
+			}
+
+			exchanged, err := client.ExchangePublicToken(resp.PublicToken)
+			if err != nil {
+				return err
+			}
+			r := result{value: exchanged, columns: []string{"ITEM_ID", "ACCESS_TOKEN"}}
+			r.add(exchanged.ItemID, exchanged.AccessToken)
+			return a.print(r)
+		},
+	}
+
+	flags := publicToken.Flags()
+	flags.StringVar(&institutionID, "institution", linkserver.DefaultSandboxInstitutionID, "institution to create the Item at")
+	flags.StringSliceVar(&products, "products", []string{"auth", "transactions"}, "products to initialize the Item with")
+	flags.BoolVar(&exchange, "exchange", false, "exchange the public token and print the access token instead")
+	cmd.AddCommand(publicToken)
+	return cmd
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+	"github.com/spf13/cobra"
+)
+
+func newTransactionsCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transactions",
+		Short: "inspects an Item's transactions",
+	}
+
+	var options plaid.GetTransactionsOptions
+	var all bool
+	get := &cobra.Command{
+		Use:   "get ACCESS_TOKEN",
+		Short: "prints a page of an Item's transactions, or all of them with --all",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if options.StartDate == "" || options.EndDate == "" {
+				return fmt.Errorf("--start and --end must be specified")
+			}
+			if options.Count < 1 || options.Count > 500 {
+				return fmt.Errorf("--count must be between 1 and 500")
+			}
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+
+			resp, err := getTransactions(client, args[0], options, all)
+			if err != nil {
+				return err
+			}
+
+			r := result{
+				value:   resp,
+				columns: []string{"DATE", "NAME", "AMOUNT", "CURRENCY", "ACCOUNT_ID", "PENDING", "CATEGORY"},
+			}
+			for _, t := range resp.Transactions {
+				r.add(t.Date, t.Name, formatAmount(t.Amount), string(t.Currency()), t.AccountID,
+					strconv.FormatBool(t.Pending), strings.Join(t.Category, " > "))
+			}
+			return a.print(r)
+		},
+	}
+
+	flags := get.Flags()
+	flags.StringVar(&options.StartDate, "start", "", "first date to include, as YYYY-MM-DD")
+	flags.StringVar(&options.EndDate, "end", "", "last date to include, as YYYY-MM-DD")
+	flags.StringSliceVar(&options.AccountIDs, "account-id", nil, "accounts to include, defaulting to all of them")
+	flags.IntVar(&options.Count, "count", 100, "number of transactions per page (1-500)")
+	flags.IntVar(&options.Offset, "offset", 0, "number of transactions to skip")
+	flags.BoolVar(&all, "all", false, "fetch every page from --offset on")
+	cmd.AddCommand(get)
+	return cmd
+}
+
+// getTransactions fetches a page of transactions, or when all is set every
+// page from options.Offset on, merged into one response.
+func getTransactions(client *plaid.Client, accessToken string, options plaid.GetTransactionsOptions, all bool) (plaid.GetTransactionsResponse, error) {
+	resp, err := client.GetTransactionsWithOptions(accessToken, options)
+	if err != nil || !all {
+		return resp, err
+	}
+
+	for options.Offset+len(resp.Transactions) < resp.TotalTransactions {
+		page := options
+		page.Offset += len(resp.Transactions)
+		next, err := client.GetTransactionsWithOptions(accessToken, page)
+		if err != nil {
+			return resp, err
+		}
+		if len(next.Transactions) == 0 {
+			break
+		}
+		resp.Transactions = append(resp.Transactions, next.Transactions...)
+		resp.TotalTransactions = next.TotalTransactions
+	}
+	return resp, nil
+}
@@ -4,7 +4,8 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/plaid/plaid-go/internal/release"
+	"github.com/perchcredit/plaid-go/v6/internal/openapi"
+	"github.com/perchcredit/plaid-go/v6/internal/release"
 	"github.com/spf13/cobra"
 )
 
@@ -15,6 +16,10 @@ func main() {
 	}
 
 	rootCmd.AddCommand(releaseCmd)
+	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(extractSpecCmd)
+	rootCmd.AddCommand(linkCmd)
+	rootCmd.AddCommand(webhooksCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -34,3 +39,29 @@ var releaseCmd = &cobra.Command{
 		}
 	},
 }
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "generates the plaid package's endpoints from the OpenAPI specification",
+	Long:  "",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := openapi.Main(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "openapi.Main: %s\n", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var extractSpecCmd = &cobra.Command{
+	Use:   "extract-spec <spec.yml> [operationId...]",
+	Short: "extracts the operations the plaid package generates from Plaid's OpenAPI specification",
+	Long:  "",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := openapi.ExtractMain(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "openapi.ExtractMain: %s\n", err.Error())
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+	"github.com/perchcredit/plaid-go/v6/plaid/linkserver"
+	"github.com/spf13/cobra"
+)
+
+var linkFlags struct {
+	environment  string
+	addr         string
+	products     []string
+	countryCodes []string
+	sandbox      bool
+	institution  string
+	file         string
+}
+
+var linkCmd = &cobra.Command{
+	Use:   "link",
+	Short: "links an Item through a local Link page and prints its access token",
+	Long: `Serves a page that opens Link, exchanges the public token of the Item the
+user links and prints the access token and item ID. With --sandbox the Item is
+created with /sandbox/public_token/create instead, without a browser.
+
+Credentials are read from PLAID_CLIENT_ID and PLAID_SECRET.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runLink(); err != nil {
+			fmt.Fprintf(os.Stderr, "link: %s\n", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	flags := linkCmd.Flags()
+	flags.StringVar(&linkFlags.environment, "env", "sandbox", "Plaid environment (sandbox|development|production)")
+	flags.StringVar(&linkFlags.addr, "addr", "localhost:8080", "address to serve the Link page on")
+	flags.StringSliceVar(&linkFlags.products, "products", []string{"auth"}, "products to initialize Link with")
+	flags.StringSliceVar(&linkFlags.countryCodes, "country-codes", []string{"US"}, "countries to show institutions from")
+	flags.BoolVar(&linkFlags.sandbox, "sandbox", false, "create the Item with /sandbox/public_token/create instead of Link")
+	flags.StringVar(&linkFlags.institution, "institution", linkserver.DefaultSandboxInstitutionID, "institution to create sandbox Items at")
+	flags.StringVar(&linkFlags.file, "out", "", "file to append linked Items to as JSON lines")
+}
+
+func runLink() error {
+	environment, err := plaid.ParseEnvironment(linkFlags.environment)
+	if err != nil {
+		return err
+	}
+	if linkFlags.sandbox && environment != plaid.Sandbox {
+		return fmt.Errorf("--sandbox can only be used in the sandbox environment")
+	}
+
+	client, err := plaid.NewClient(plaid.ClientOptions{
+		ClientID:    os.Getenv("PLAID_CLIENT_ID"),
+		Secret:      os.Getenv("PLAID_SECRET"),
+		Environment: environment,
+	})
+	if err != nil {
+		return err
+	}
+
+	server := linkserver.New(client, linkserver.Options{
+		LinkToken: plaid.LinkTokenConfigs{
+			User:         &plaid.LinkTokenUser{ClientUserID: "plaid-go-link"},
+			ClientName:   "plaid-go",
+			Products:     linkFlags.products,
+			CountryCodes: linkFlags.countryCodes,
+			Language:     "en",
+		},
+		Sandbox:              linkFlags.sandbox,
+		SandboxInstitutionID: linkFlags.institution,
+		Output:               os.Stdout,
+		File:                 linkFlags.file,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	_, err = server.Run(ctx, linkFlags.addr)
+	return err
+}
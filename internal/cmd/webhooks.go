@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+
+	"github.com/perchcredit/plaid-go/v6/internal/webhookmigration"
+	"github.com/perchcredit/plaid-go/v6/plaid"
+	"github.com/spf13/cobra"
+)
+
+var webhooksCmd = &cobra.Command{
+	Use:   "webhooks",
+	Short: "manages the webhooks of Items",
+}
+
+var webhooksMigrateFlags struct {
+	environment        string
+	webhook            string
+	tokens             string
+	vault              string
+	checkpoint         string
+	concurrency        int
+	rate               float64
+	retryLoginRequired bool
+}
+
+var webhooksMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "moves the webhooks of many Items to a new URL",
+	Long: `Updates the webhook of every Item read from --tokens or --vault to --webhook.
+
+--tokens is a file with an access token, or a JSON object with access_token and
+item_id fields as written by the link command, on each line. --vault is a token
+vault file, decrypted with the base64 encoded key in PLAID_VAULT_KEY.
+
+Results are appended to the --checkpoint log as they are known. Running the
+command again with the same log skips the Items that were updated, and retries
+those that failed. Items that need the user to log in again are listed at the
+end, and are only retried with --retry-login-required.
+
+Credentials are read from PLAID_CLIENT_ID and PLAID_SECRET.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runWebhooksMigrate(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "webhooks migrate: %s\n", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	webhooksCmd.AddCommand(webhooksMigrateCmd)
+
+	flags := webhooksMigrateCmd.Flags()
+	flags.StringVar(&webhooksMigrateFlags.environment, "env", "sandbox", "Plaid environment (sandbox|development|production)")
+	flags.StringVar(&webhooksMigrateFlags.webhook, "webhook", "", "URL to move the webhooks to")
+	flags.StringVar(&webhooksMigrateFlags.tokens, "tokens", "", "file to read access tokens from")
+	flags.StringVar(&webhooksMigrateFlags.vault, "vault", "", "token vault file to read access tokens from")
+	flags.StringVar(&webhooksMigrateFlags.checkpoint, "checkpoint", "webhooks-migrate.jsonl", "checkpoint log to resume from and append results to")
+	flags.IntVar(&webhooksMigrateFlags.concurrency, "concurrency", 4, "number of Items to update at once")
+	flags.Float64Var(&webhooksMigrateFlags.rate, "rate", 10, "largest number of calls to make per second")
+	flags.BoolVar(&webhooksMigrateFlags.retryLoginRequired, "retry-login-required", false, "retry Items that needed the user to log in again")
+}
+
+func runWebhooksMigrate(out io.Writer) error {
+	flags := webhooksMigrateFlags
+	environment, err := plaid.ParseEnvironment(flags.environment)
+	if err != nil {
+		return err
+	}
+	if flags.webhook == "" {
+		return fmt.Errorf("--webhook must be specified")
+	}
+	if (flags.tokens == "") == (flags.vault == "") {
+		return fmt.Errorf("exactly one of --tokens and --vault must be specified")
+	}
+
+	targets, err := readWebhookTargets(flags.tokens, flags.vault)
+	if err != nil {
+		return err
+	}
+
+	client, err := plaid.NewClient(plaid.ClientOptions{
+		ClientID:    os.Getenv("PLAID_CLIENT_ID"),
+		Secret:      os.Getenv("PLAID_SECRET"),
+		Environment: environment,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report, err := webhookmigration.Migrate(ctx, client, targets, webhookmigration.Options{
+		Webhook:            flags.webhook,
+		Concurrency:        flags.concurrency,
+		Rate:               flags.rate,
+		Checkpoint:         flags.checkpoint,
+		RetryLoginRequired: flags.retryLoginRequired,
+	})
+	if report.Results != nil {
+		printMigrationReport(out, report, len(targets))
+	}
+	if err != nil {
+		return err
+	}
+	if n := report.Count(webhookmigration.StatusFailed); n > 0 {
+		return fmt.Errorf("%d items failed, run again to retry them", n)
+	}
+	return nil
+}
+
+func readWebhookTargets(tokens, vaultPath string) ([]webhookmigration.Target, error) {
+	if tokens != "" {
+		f, err := os.Open(tokens)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return webhookmigration.ReadTargets(f)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(os.Getenv("PLAID_VAULT_KEY"))
+	if err != nil {
+		return nil, fmt.Errorf("PLAID_VAULT_KEY: %w", err)
+	}
+	cipher, err := plaid.NewAESGCMCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("PLAID_VAULT_KEY: %w", err)
+	}
+	vault, err := plaid.OpenFileTokenVault(vaultPath, cipher)
+	if err != nil {
+		return nil, err
+	}
+	return webhookmigration.VaultTargets(vault)
+}
+
+func printMigrationReport(out io.Writer, report webhookmigration.Report, targets int) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ITEM\tSTATUS\tERROR")
+	for _, result := range report.Results {
+		if result.Status == webhookmigration.StatusLoginRequired || result.Status == webhookmigration.StatusFailed {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", result.Key, result.Status, result.ErrorCode)
+		}
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\n%d of %d items: %d updated, %d skipped, %d need the user to log in, %d failed\n",
+		len(report.Results), targets,
+		report.Count(webhookmigration.StatusUpdated),
+		report.Count(webhookmigration.StatusSkipped),
+		report.Count(webhookmigration.StatusLoginRequired),
+		report.Count(webhookmigration.StatusFailed),
+	)
+}
@@ -0,0 +1,39 @@
+package openapi
+
+import (
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExtract(t *testing.T) {
+	src, err := Extract([]byte(testSpec), []string{"widgetGet"})
+	assert.Nil(t, err)
+
+	var spec Spec
+	assert.Nil(t, yaml.Unmarshal(src, &spec))
+	assert.Equal(t, []string{"widgetGet"}, OperationIDs(&spec))
+
+	var schemas []string
+	for name := range spec.Components.Schemas {
+		schemas = append(schemas, name)
+	}
+	assert.ElementsMatch(t, []string{"ThingGetRequest", "ThingGetResponse", "Thing", "ThingID", "OwnerNullable", "Owner"}, schemas)
+
+	// Extracting from an extracted spec changes nothing.
+	again, err := Extract(src, []string{"widgetGet"})
+	assert.Nil(t, err)
+	assert.Equal(t, string(src), string(again))
+
+	_, err = Extract([]byte(testSpec), []string{"gadgetGet"})
+	assert.NotNil(t, err)
+}
+
+func TestCheckedInSpecIsExtracted(t *testing.T) {
+	spec, err := LoadSpec(filepath.Join("..", "..", SpecPath))
+	assert.Nil(t, err)
+	assert.Contains(t, spec.Components.Schemas, "AccountBase")
+	assert.Contains(t, spec.Components.Schemas["AccountBase"].Properties, Property{Name: "balances", Schema: &Schema{Ref: "#/components/schemas/AccountBalance"}})
+}
@@ -0,0 +1,571 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// methodNames maps Plaid's operation IDs, which are noun first, to the verb
+// first method names used by the rest of the package. Operations that are not
+// listed use their operation ID.
+var methodNames = map[string]string{
+	"employersSearch":        "SearchEmployers",
+	"itemRemove":             "RemoveItem",
+	"processorBalanceGet":    "GetProcessorBalance",
+	"sandboxItemFireWebhook": "FireSandboxItemWebhook",
+	"userCreate":             "CreateUser",
+}
+
+// typeNames maps Plaid's schema names to the hand-written types that model
+// them under a different name.
+var typeNames = map[string]string{
+	"AccountBase": "Account",
+}
+
+// initialisms are the words that golint expects to keep their case.
+var initialisms = map[string]bool{
+	"API": true, "BACS": true, "IBAN": true, "ID": true, "ISO": true,
+	"URI": true, "URL": true,
+}
+
+type field struct {
+	Name string
+	Type string
+	Tag  string
+	// Value is the expression a generated method sets a request field to.
+	Value string
+}
+
+type param struct {
+	Name  string
+	Type  string
+	Check string
+	Words string
+}
+
+type operation struct {
+	Path         string
+	Method       string
+	Doc          string
+	RequestType  string
+	ResponseType string
+	Params       []param
+	Options      []field
+	Request      []field
+	Response     []field
+}
+
+type enumValue struct {
+	Const string
+	Field string
+	Value string
+}
+
+type enum struct {
+	Type   string
+	Doc    string
+	Values []enumValue
+}
+
+type object struct {
+	Type   string
+	Doc    string
+	Fields []field
+}
+
+type generator struct {
+	spec *Spec
+	// types and methods are declared by the hand-written files of the
+	// package. Generated code reuses those types and never redeclares them.
+	types   map[string]bool
+	methods map[string]bool
+
+	operations []operation
+	enums      map[string]enum
+	objects    map[string]*object
+	imports    map[string]bool
+}
+
+// Generate returns the source of the generated file for the package in pkgDir.
+// Operations whose Client method is already declared in a hand-written file of
+// the package are skipped, as are schemas whose type is.
+func Generate(spec *Spec, pkgDir string) ([]byte, error) {
+	g := &generator{
+		spec:    spec,
+		enums:   map[string]enum{},
+		objects: map[string]*object{},
+		imports: map[string]bool{"encoding/json": true},
+	}
+	var err error
+	if g.types, g.methods, err = handWritten(pkgDir); err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		op := spec.Paths[path].Post
+		if op == nil {
+			continue
+		}
+		if err := g.operation(path, op); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := fileTmpl.Execute(&buf, g.view()); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// handWritten returns the types and Client methods declared in the
+// non-generated, non-test files of the package in dir.
+func handWritten(dir string) (types, methods map[string]bool, err error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	types, methods = map[string]bool{}, map[string]bool{}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || strings.HasSuffix(file, "_autogenerated.go") {
+			continue
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		f, err := parser.ParseFile(fset, file, src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok {
+						types[spec.Name.Name] = true
+					}
+				}
+			case *ast.FuncDecl:
+				if isClientMethod(decl) {
+					methods[decl.Name.Name] = true
+				}
+			}
+		}
+	}
+	return types, methods, nil
+}
+
+// isClientMethod reports whether decl is a method on *Client. Methods of the
+// same name on other types do not stand in for a generated endpoint.
+func isClientMethod(decl *ast.FuncDecl) bool {
+	if decl.Recv == nil || len(decl.Recv.List) != 1 {
+		return false
+	}
+	star, ok := decl.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := star.X.(*ast.Ident)
+	return ok && ident.Name == "Client"
+}
+
+func (g *generator) operation(path string, op *Operation) error {
+	method := methodNames[op.OperationID]
+	if method == "" {
+		method = exported(op.OperationID)
+	}
+	if g.methods[method] {
+		return nil
+	}
+
+	request := g.resolve(jsonSchema(op.RequestBody.Content))
+	response := g.resolve(jsonSchema(op.Responses["200"].Content))
+	if request == nil || response == nil {
+		return fmt.Errorf("%s needs a JSON request body and 200 response", op.OperationID)
+	}
+
+	o := operation{
+		Path:         path,
+		Method:       method,
+		Doc:          comment(method+" "+unexported(op.Description), "See "+op.ExternalDocs.URL+"."),
+		RequestType:  unexported(method) + "Request",
+		ResponseType: method + "Response",
+	}
+
+	for _, prop := range request.Properties {
+		name := goName(prop.Name)
+		switch prop.Name {
+		case "client_id":
+			o.Request = append(o.Request, field{Name: name, Type: "string", Tag: prop.Name, Value: "c.clientID"})
+			continue
+		case "secret":
+			o.Request = append(o.Request, field{Name: name, Type: "string", Tag: prop.Name, Value: "c.secret"})
+			continue
+		}
+
+		typ, err := g.goType(prop.Schema)
+		if err != nil {
+			return fmt.Errorf("%s: %w", prop.Name, err)
+		}
+
+		if request.isRequired(prop.Name) {
+			p := param{Name: unexported(name), Type: typ, Words: strings.ReplaceAll(prop.Name, "_", " ")}
+			switch {
+			case strings.HasPrefix(typ, "[]"):
+				p.Check = "len(" + p.Name + ") == 0"
+			case g.isString(prop.Schema):
+				p.Check = p.Name + ` == ""`
+			}
+			o.Params = append(o.Params, p)
+			o.Request = append(o.Request, field{Name: name, Type: typ, Tag: prop.Name, Value: p.Name})
+			continue
+		}
+
+		if g.isObject(prop.Schema) && !strings.HasPrefix(typ, "*") {
+			typ = "*" + typ
+		}
+		o.Options = append(o.Options, field{Name: name, Type: typ})
+		o.Request = append(o.Request, field{Name: name, Type: typ, Tag: prop.Name + ",omitempty", Value: "options." + name})
+	}
+
+	for _, prop := range response.Properties {
+		if prop.Name == "request_id" {
+			continue
+		}
+		typ, err := g.goType(prop.Schema)
+		if err != nil {
+			return fmt.Errorf("%s: %w", prop.Name, err)
+		}
+		o.Response = append(o.Response, field{Name: goName(prop.Name), Type: typ, Tag: prop.Name})
+	}
+
+	g.operations = append(g.operations, o)
+	return nil
+}
+
+// resolve follows references, and components that only wrap another
+// component, to the schema they name.
+func (g *generator) resolve(s *Schema) *Schema {
+	for s != nil {
+		ref := s.refName()
+		if ref == "" {
+			return s
+		}
+		s = g.spec.Components.Schemas[ref]
+	}
+	return nil
+}
+
+func (g *generator) isString(s *Schema) bool {
+	s = g.resolve(s)
+	return s != nil && s.Type == "string" && s.Format != "date-time"
+}
+
+func (g *generator) isObject(s *Schema) bool {
+	s = g.resolve(s)
+	return s != nil && s.Type == "object"
+}
+
+// goType returns the Go type for a schema, queueing the enums and structs it
+// refers to for generation.
+func (g *generator) goType(s *Schema) (string, error) {
+	if ref := s.refName(); ref != "" {
+		name := typeNames[ref]
+		if name == "" {
+			name = ref
+		}
+		component, ok := g.spec.Components.Schemas[ref]
+		if ok && !g.types[name] && component.isAlias() {
+			return g.goType(component)
+		}
+		switch {
+		case !ok && !g.types[name]:
+			return "", fmt.Errorf("unknown schema %s", ref)
+		case !g.types[name]:
+			if err := g.component(name, component); err != nil {
+				return "", fmt.Errorf("%s: %w", ref, err)
+			}
+		}
+		if ok && component.Type == "object" && (s.Nullable || component.Nullable) {
+			return "*" + name, nil
+		}
+		return name, nil
+	}
+
+	switch s.Type {
+	case "string":
+		if s.Format == "date-time" {
+			g.imports["time"] = true
+			return "time.Time", nil
+		}
+		return "string", nil
+	case "integer":
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		if s.Items == nil {
+			return "", fmt.Errorf("array has no items")
+		}
+		elem, err := g.goType(s.Items)
+		return "[]" + elem, err
+	}
+	return "", fmt.Errorf("unsupported inline %q schema, move it to components", s.Type)
+}
+
+// component queues a component schema to be generated as an enum or struct.
+func (g *generator) component(name string, s *Schema) error {
+	if _, ok := g.objects[name]; ok {
+		return nil
+	}
+	if _, ok := g.enums[name]; ok {
+		return nil
+	}
+
+	switch {
+	case s.Type == "string" && len(s.Enum) > 0:
+		e := enum{Type: name, Doc: comment(name + " is " + unexported(s.Description))}
+		for _, value := range s.Enum {
+			e.Values = append(e.Values, enumValue{
+				Const: unexported(name) + goName(strings.ToLower(value)),
+				Field: goName(strings.ToLower(value)),
+				Value: value,
+			})
+		}
+		g.enums[name] = e
+		return nil
+	case s.Type == "object":
+		o := &object{Type: name}
+		if s.Description != "" {
+			o.Doc = comment(name + " is " + unexported(s.Description))
+		}
+		// Registered before its fields are typed, so that recursive schemas
+		// terminate.
+		g.objects[name] = o
+		for _, prop := range s.Properties {
+			typ, err := g.goType(prop.Schema)
+			if err != nil {
+				return fmt.Errorf("%s: %w", prop.Name, err)
+			}
+			o.Fields = append(o.Fields, field{Name: goName(prop.Name), Type: typ, Tag: prop.Name})
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported %q schema", s.Type)
+}
+
+func (g *generator) view() interface{} {
+	needsErrors := false
+	for _, o := range g.operations {
+		for _, p := range o.Params {
+			if p.Check != "" {
+				needsErrors = true
+			}
+		}
+	}
+	if needsErrors {
+		g.imports["errors"] = true
+	}
+
+	imports := make([]string, 0, len(g.imports))
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+
+	enums := make([]enum, 0, len(g.enums))
+	for _, e := range g.enums {
+		enums = append(enums, e)
+	}
+	sort.Slice(enums, func(i, j int) bool { return enums[i].Type < enums[j].Type })
+
+	objects := make([]*object, 0, len(g.objects))
+	for _, o := range g.objects {
+		objects = append(objects, o)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Type < objects[j].Type })
+
+	return struct {
+		Imports    []string
+		Enums      []enum
+		Objects    []*object
+		Operations []operation
+	}{imports, enums, objects, g.operations}
+}
+
+// goName converts a snake_case name to an exported Go identifier.
+func goName(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+func exported(name string) string {
+	if name == "" {
+		return ""
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// unexported lowers the leading capital of a name, or the whole of a leading
+// initialism, so that "IBANNumber" becomes "ibanNumber".
+func unexported(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// comment wraps paragraphs of text into a Go comment.
+func comment(paragraphs ...string) string {
+	var lines []string
+	for _, text := range paragraphs {
+		line := "//"
+		for _, word := range strings.Fields(text) {
+			if len(line)+1+len(word) > 78 && line != "//" {
+				lines = append(lines, line)
+				line = "//"
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+var fileTmpl = template.Must(template.New("file").Funcs(template.FuncMap{
+	"unexported": unexported,
+}).Parse(`// NOTE - this file is auto-generated. DO NOT EDIT.
+
+package plaid
+
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+{{range .Enums}}
+{{.Doc}}
+type {{.Type}} string
+
+const (
+{{- $type := .Type}}
+{{- range .Values}}
+	{{.Const}} {{$type}} = "{{.Value}}"
+{{- end}}
+)
+
+type {{.Type | unexported}}s struct {
+{{- range .Values}}
+	{{.Field}} {{$type}}
+{{- end}}
+}
+
+var {{.Type}}s {{.Type | unexported}}s = {{.Type | unexported}}s{
+{{- range .Values}}
+	{{.Field}}: {{.Const}},
+{{- end}}
+}
+{{end}}
+{{- range .Objects}}
+{{if .Doc}}{{.Doc}}
+{{end -}}
+type {{.Type}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.Tag}}"` + "`" + `
+{{- end}}
+}
+{{end}}
+{{- range .Operations}}
+type {{.RequestType}} struct {
+{{- range .Request}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.Tag}}"` + "`" + `
+{{- end}}
+}
+
+type {{.ResponseType}} struct {
+	APIResponse
+{{- range .Response}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.Tag}}"` + "`" + `
+{{- end}}
+}
+{{- if .Options}}
+
+type {{.Method}}Options struct {
+{{- range .Options}}
+	{{.Name}} {{.Type}}
+{{- end}}
+}
+
+{{.Doc}}
+func (c *Client) {{.Method}}({{range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Name}} {{$p.Type}}{{end}}) (resp {{.ResponseType}}, err error) {
+	return c.{{.Method}}WithOptions({{range .Params}}{{.Name}}, {{end}}{{.Method}}Options{})
+}
+
+func (c *Client) {{.Method}}WithOptions({{range .Params}}{{.Name}} {{.Type}}, {{end}}options {{.Method}}Options) (resp {{.ResponseType}}, err error) {
+{{- else}}
+
+{{.Doc}}
+func (c *Client) {{.Method}}({{range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Name}} {{$p.Type}}{{end}}) (resp {{.ResponseType}}, err error) {
+{{- end}}
+{{- $path := .Path}}
+{{- range .Params}}{{if .Check}}
+	if {{.Check}} {
+		return resp, errors.New("{{$path}} - {{.Words}} must be specified")
+	}
+{{- end}}{{end}}
+
+	jsonBody, err := json.Marshal({{.RequestType}}{
+{{- range .Request}}
+		{{.Name}}: {{.Value}},
+{{- end}}
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("{{.Path}}", jsonBody, &resp)
+	return resp, err
+}
+{{end}}`))
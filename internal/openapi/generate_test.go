@@ -0,0 +1,155 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestGeneratedCodeIsUpToDate(t *testing.T) {
+	repoDir := filepath.Join("..", "..")
+
+	want, err := GenerateRepo(repoDir)
+	assert.Nil(t, err)
+
+	got, err := os.ReadFile(filepath.Join(repoDir, OutputPath))
+	assert.Nil(t, err)
+	assert.True(t, string(want) == string(got), "%s is stale, run `make generate`", OutputPath)
+}
+
+const testSpec = `
+paths:
+  /thing/get:
+    post:
+      operationId: thingGet
+      description: Gets a thing.
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/ThingGetRequest'
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/ThingGetResponse'
+  /widget/get:
+    post:
+      operationId: widgetGet
+      description: Gets a widget.
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/ThingGetRequest'
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/ThingGetResponse'
+components:
+  schemas:
+    ThingGetRequest:
+      type: object
+      properties:
+        client_id:
+          type: string
+        thing_id:
+          $ref: '#/components/schemas/ThingID'
+        since:
+          type: string
+          format: date-time
+        owner:
+          $ref: '#/components/schemas/OwnerNullable'
+      required:
+        - thing_id
+    ThingID:
+      type: string
+      description: The ID of a thing.
+    Owner:
+      type: object
+      properties:
+        name:
+          type: string
+    OwnerNullable:
+      type: object
+      nullable: true
+      allOf:
+        - $ref: '#/components/schemas/Owner'
+    Thing:
+      type: object
+      properties:
+        name:
+          type: string
+    ThingGetResponse:
+      type: object
+      properties:
+        thing:
+          $ref: '#/components/schemas/Thing'
+        request_id:
+          type: string
+`
+
+func generateTestSpec(t *testing.T, handWritten string) string {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "plaid.yml"), []byte(testSpec), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "thing.go"), []byte("package plaid\n\n"+handWritten), 0644))
+
+	spec, err := LoadSpec(filepath.Join(dir, "plaid.yml"))
+	assert.Nil(t, err)
+
+	src, err := Generate(spec, dir)
+	assert.Nil(t, err)
+	return string(src)
+}
+
+func TestGenerate(t *testing.T) {
+	src := generateTestSpec(t, `
+type Thing struct{}
+
+func (c *Client) WidgetGet() {}
+
+func (t *Thing) ThingGet() {}
+`)
+
+	// Hand-written types and methods are reused, not redeclared.
+	assert.NotContains(t, src, "type Thing struct")
+	assert.NotContains(t, src, "func (c *Client) WidgetGet(")
+	// Only methods on *Client stand in for an endpoint.
+	assert.Contains(t, src, "func (c *Client) ThingGet(")
+
+	assert.Contains(t, src, "type thingGetRequest struct")
+	assert.Contains(t, src, "Thing Thing `json:\"thing\"`")
+	assert.Contains(t, src, "func (c *Client) ThingGet(thingID string) (resp ThingGetResponse, err error)")
+	assert.Contains(t, src, "func (c *Client) ThingGetWithOptions(thingID string, options ThingGetOptions)")
+	assert.Contains(t, src, `errors.New("/thing/get - thing id must be specified")`)
+	assert.Contains(t, src, `"time"`)
+	// Aliases resolve to the type they name rather than being declared.
+	assert.NotContains(t, src, "type ThingID")
+	assert.NotContains(t, src, "type OwnerNullable")
+	assert.Contains(t, src, "Owner    *Owner    `json:\"owner,omitempty\"`")
+	assert.False(t, strings.Contains(src, "RequestID"))
+}
+
+func TestGenerateUnknownSchema(t *testing.T) {
+	dir := t.TempDir()
+	spec := &Spec{Paths: map[string]PathItem{
+		"/thing/get": {Post: &Operation{OperationID: "thingGet"}},
+	}}
+
+	_, err := Generate(spec, dir)
+	assert.NotNil(t, err)
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "ClientUserID", goName("client_user_id"))
+	assert.Equal(t, "ISOCurrencyCode", goName("iso_currency_code"))
+	assert.Equal(t, "ibanNumber", unexported("IBANNumber"))
+	assert.Equal(t, "webhookType", unexported("WebhookType"))
+	assert.Equal(t, "id", unexported("ID"))
+}
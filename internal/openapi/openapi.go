@@ -0,0 +1,69 @@
+// Package openapi generates the plaid package's endpoints from Plaid's OpenAPI
+// specification.
+//
+// The generator writes request and response types, enums and Client methods
+// to plaid/endpoints_autogenerated.go. Anything declared in the package's
+// hand-written files takes precedence: an operation whose method is already
+// hand-written is skipped, and a schema whose type is already hand-written is
+// reused rather than generated. Extensions to generated types belong in
+// hand-written files, which the generator never touches.
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/perchcredit/plaid-go/v6/internal"
+)
+
+var (
+	logger *log.Logger
+)
+
+func init() {
+	logger = log.New(os.Stderr, "[generate] ", log.LUTC)
+}
+
+const (
+	// SpecPath is the checked-in specification, relative to the repo root.
+	SpecPath = "internal/openapi/plaid.yml"
+	// OutputPath is the generated file, relative to the repo root.
+	OutputPath = "plaid/endpoints_autogenerated.go"
+)
+
+// GenerateRepo returns the generated file for the repository rooted at
+// repoDir.
+func GenerateRepo(repoDir string) ([]byte, error) {
+	spec, err := LoadSpec(filepath.Join(repoDir, SpecPath))
+	if err != nil {
+		return nil, err
+	}
+	return Generate(spec, filepath.Join(repoDir, filepath.Dir(OutputPath)))
+}
+
+// Main is the entry point into the generate script
+func Main(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("generate takes no arguments")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	repoDir, err := internal.FindRepoRoot(wd)
+	if err != nil {
+		return err
+	}
+
+	logger.Println("generating", OutputPath, "from", SpecPath)
+	src, err := GenerateRepo(repoDir)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(repoDir, OutputPath), src, 0644)
+}
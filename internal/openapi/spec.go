@@ -0,0 +1,124 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the part of an OpenAPI 3 document that the generator reads.
+type Spec struct {
+	Paths      map[string]PathItem `yaml:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+// PathItem holds the operations on a path. Every Plaid endpoint is a POST.
+type PathItem struct {
+	Post *Operation `yaml:"post"`
+}
+
+type Operation struct {
+	OperationID  string `yaml:"operationId"`
+	Summary      string `yaml:"summary"`
+	Description  string `yaml:"description"`
+	ExternalDocs struct {
+		URL string `yaml:"url"`
+	} `yaml:"externalDocs"`
+	RequestBody struct {
+		Content map[string]MediaType `yaml:"content"`
+	} `yaml:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]MediaType `yaml:"content"`
+	} `yaml:"responses"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Schema is a JSON schema. Properties keeps the order the spec lists them in,
+// so that generated structs read in the same order as Plaid's documentation.
+type Schema struct {
+	Ref         string     `yaml:"$ref"`
+	Type        string     `yaml:"type"`
+	Format      string     `yaml:"format"`
+	Description string     `yaml:"description"`
+	Nullable    bool       `yaml:"nullable"`
+	Enum        []string   `yaml:"enum"`
+	Items       *Schema    `yaml:"items"`
+	AllOf       []*Schema  `yaml:"allOf"`
+	Required    []string   `yaml:"required"`
+	Properties  Properties `yaml:"properties"`
+}
+
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+type Properties []Property
+
+// UnmarshalYAML decodes a properties mapping without losing its order.
+func (p *Properties) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: properties must be a mapping", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var schema Schema
+		if err := node.Content[i+1].Decode(&schema); err != nil {
+			return err
+		}
+		*p = append(*p, Property{Name: node.Content[i].Value, Schema: &schema})
+	}
+	return nil
+}
+
+func (s *Schema) isRequired(name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// refName returns the name of the component a schema refers to, looking
+// through a single-element allOf, or "" if it is not a reference.
+func (s *Schema) refName() string {
+	if len(s.AllOf) == 1 {
+		return s.AllOf[0].refName()
+	}
+	return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+}
+
+// isAlias reports whether a component schema only names a scalar or array
+// type, as AccessToken does, or wraps another component, as
+// AddressDataNullable does. Aliases are not declared as Go types.
+func (s *Schema) isAlias() bool {
+	if s.refName() != "" {
+		return len(s.Properties) == 0
+	}
+	return s.Type != "object" && len(s.Enum) == 0
+}
+
+// LoadSpec reads an OpenAPI document from a YAML file.
+func LoadSpec(path string) (*Spec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec Spec
+	if err := yaml.Unmarshal(b, &spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// jsonSchema returns the schema of an application/json body.
+func jsonSchema(content map[string]MediaType) *Schema {
+	return content["application/json"].Schema
+}
@@ -2,11 +2,11 @@ package release
 
 import (
 	"fmt"
-	"go/build"
 	"log"
 	"os"
 	"time"
 
+	"github.com/perchcredit/plaid-go/v6/internal"
 	git "gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
@@ -43,15 +43,19 @@ func validateArguments(args []string) (releaseType, error) {
 
 // Main is the entry point into the release script
 func Main(args []string) error {
-	pkgName := "github.com/plaid/plaid-go"
-	pkg, err := build.Import(pkgName, "", build.FindOnly)
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	repoDir, err := internal.FindRepoRoot(wd)
 	if err != nil {
 		return err
 	}
 
 	// Open the git repository
-	logger.Println("Opening", pkgName)
-	r, err := git.PlainOpen(pkg.Dir)
+	logger.Println("Opening", internal.ModulePath, "at", repoDir)
+	r, err := git.PlainOpen(repoDir)
 	if err != nil {
 		return err
 	}
@@ -76,7 +80,7 @@ func Main(args []string) error {
 		return err
 	}
 
-	newVersion, err := incrementVersion(pkg, release)
+	newVersion, err := incrementVersion(repoDir, release)
 	if err != nil {
 		return err
 	}
@@ -115,8 +119,9 @@ func commitAndTagRepo(r *git.Repository, w *git.Worktree, newVersion string) err
 		return err
 	}
 
+	// Go only resolves module versions from tags with a v prefix.
 	tag := object.Tag{
-		Name:       newVersion,
+		Name:       "v" + newVersion,
 		Message:    "Release of " + newVersion,
 		Tagger:     signature,
 		Target:     commit,
@@ -132,7 +137,7 @@ func commitAndTagRepo(r *git.Repository, w *git.Worktree, newVersion string) err
 		return err
 	}
 
-	if err := r.Storer.SetReference(plumbing.NewReferenceFromStrings("refs/tags/"+newVersion, hash.String())); err != nil {
+	if err := r.Storer.SetReference(plumbing.NewReferenceFromStrings("refs/tags/"+tag.Name, hash.String())); err != nil {
 		return err
 	}
 
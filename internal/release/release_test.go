@@ -0,0 +1,32 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestIncrementVersion(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "internal"), 0755))
+
+	version, err := incrementVersion(dir, minor)
+	assert.Nil(t, err)
+
+	contents, err := os.ReadFile(filepath.Join(dir, "internal", "version_autogenerated.go"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(contents), `Version = "`+version+`"`)
+}
+
+func TestIncrementVersionRequiresMajorVersionSuffix(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "internal"), 0755))
+
+	// A major release needs the module path to move to the next /vN first.
+	_, err := incrementVersion(dir, major)
+	assert.NotNil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "internal", "version_autogenerated.go"))
+	assert.True(t, os.IsNotExist(err))
+}
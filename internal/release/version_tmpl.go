@@ -2,14 +2,13 @@ package release
 
 import (
 	"fmt"
-	"go/build"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
 	"github.com/coreos/go-semver/semver"
-	"github.com/plaid/plaid-go/internal"
+	"github.com/perchcredit/plaid-go/v6/internal"
 )
 
 var versionTmpl = template.Must(template.New("var").Parse(strings.TrimSpace(`
@@ -23,7 +22,7 @@ const (
 )
 `) + "\n"))
 
-func incrementVersion(pkg *build.Package, release releaseType) (string, error) {
+func incrementVersion(repoDir string, release releaseType) (string, error) {
 	// Get the current semantic version
 	v, err := semver.NewVersion(internal.Version)
 	if err != nil {
@@ -43,12 +42,19 @@ func incrementVersion(pkg *build.Package, release releaseType) (string, error) {
 		return "", fmt.Errorf("invalid releaseType %v", release)
 	}
 
+	// Go only resolves major versions from 2 on through a /vN suffix on the
+	// module path, so a major release has to change it first.
+	if suffix := fmt.Sprintf("/v%d", v.Major); v.Major >= 2 && !strings.HasSuffix(internal.ModulePath, suffix) {
+		return "", fmt.Errorf("module path %s must end in %s to release version %s", internal.ModulePath, suffix, v)
+	}
+
 	logger.Println("incrementing version to", v.String())
 
-	f, err := os.OpenFile(filepath.Join(pkg.Dir, "internal", "version_autogenerated.go"), os.O_RDWR|os.O_CREATE, 0755)
+	f, err := os.OpenFile(filepath.Join(repoDir, "internal", "version_autogenerated.go"), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
 		return "", err
 	}
+	defer f.Close()
 
 	if err := versionTmpl.Execute(f, struct {
 		Version string
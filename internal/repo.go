@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ModulePath is the import path declared in the repository's go.mod.
+const ModulePath = "github.com/perchcredit/plaid-go/v6"
+
+// FindRepoRoot walks up from dir until it finds the go.mod declaring
+// ModulePath and returns the directory containing it.
+func FindRepoRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path, err := readModulePath(filepath.Join(dir, "go.mod"))
+		if err == nil && path == ModulePath {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("could not find the %s module in the current directory or any of its parents", ModulePath)
+		}
+		dir = parent
+	}
+}
+
+// readModulePath returns the module path declared in a go.mod file.
+func readModulePath(goMod string) (string, error) {
+	f, err := os.Open(goMod)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s has no module directive", goMod)
+}
@@ -0,0 +1,20 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestFindRepoRoot(t *testing.T) {
+	root, err := FindRepoRoot(".")
+	assert.Nil(t, err)
+
+	_, err = os.Stat(filepath.Join(root, "internal", "version_autogenerated.go"))
+	assert.Nil(t, err)
+
+	_, err = FindRepoRoot(t.TempDir())
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,328 @@
+// Package webhookmigration moves the webhooks of many Items to a new URL, for
+// when the host receiving webhooks changes.
+//
+// Items are updated with /item/webhook/update by a bounded number of workers
+// sharing a rate limit. Every result is appended to a checkpoint log as a JSON
+// line, so that a migration that was interrupted, or that failed for some
+// Items, can be run again and only retries the Items it has not finished.
+// Access tokens are never written to the log.
+package webhookmigration
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+)
+
+// Target is an Item whose webhook is to be migrated.
+type Target struct {
+	ItemID      string `json:"item_id,omitempty"`
+	AccessToken string `json:"access_token"`
+}
+
+// Key identifies the target in the checkpoint log: its item ID when known,
+// and otherwise a hash of its access token.
+func (t Target) Key() string {
+	if t.ItemID != "" {
+		return t.ItemID
+	}
+	sum := sha256.Sum256([]byte(t.AccessToken))
+	return "token:" + hex.EncodeToString(sum[:8])
+}
+
+// ReadTargets reads targets from r, one per line. A line is either an access
+// token or a JSON object with access_token and item_id fields, such as those
+// written by the link command. Blank lines and lines starting with # are
+// ignored.
+func ReadTargets(r io.Reader) ([]Target, error) {
+	var targets []Target
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		target := Target{AccessToken: line}
+		if strings.HasPrefix(line, "{") {
+			target = Target{}
+			if err := json.Unmarshal([]byte(line), &target); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+		}
+		if target.AccessToken == "" {
+			return nil, fmt.Errorf("line %d: access token must be specified", n)
+		}
+		targets = append(targets, target)
+	}
+	return targets, scanner.Err()
+}
+
+// VaultTargets returns a target for every Item in vault.
+func VaultTargets(vault plaid.TokenVault) ([]Target, error) {
+	itemIDs, err := vault.ItemIDs()
+	if err != nil {
+		return nil, err
+	}
+	var targets []Target
+	for _, itemID := range itemIDs {
+		accessToken, err := vault.AccessToken(itemID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", itemID, err)
+		}
+		targets = append(targets, Target{ItemID: itemID, AccessToken: accessToken})
+	}
+	return targets, nil
+}
+
+// Status is the outcome of migrating one Item.
+type Status string
+
+const (
+	// StatusUpdated Items now have the new webhook.
+	StatusUpdated Status = "updated"
+	// StatusLoginRequired Items could not be updated until the user logs in
+	// again through Link's update mode.
+	StatusLoginRequired Status = "login_required"
+	// StatusFailed Items could not be updated, and are retried when the
+	// migration is resumed.
+	StatusFailed Status = "failed"
+	// StatusSkipped Items were finished by an earlier run, according to the
+	// checkpoint log.
+	StatusSkipped Status = "skipped"
+)
+
+// Result is the outcome of migrating one Item, as written to the checkpoint
+// log.
+type Result struct {
+	Key       string    `json:"key"`
+	ItemID    string    `json:"item_id,omitempty"`
+	Webhook   string    `json:"webhook"`
+	Status    Status    `json:"status"`
+	ErrorCode string    `json:"error_code,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// Options configures Migrate.
+type Options struct {
+	// Webhook is the URL to move every Item's webhook to.
+	Webhook string
+	// Concurrency is the number of Items updated at once. Defaults to 4.
+	Concurrency int
+	// Rate is the largest number of calls made per second, to stay within
+	// Plaid's rate limits. Defaults to 10.
+	Rate float64
+	// Checkpoint is the path of the checkpoint log. Items it records as
+	// updated to Webhook, or as needing the user to log in, are skipped. No
+	// log is kept when it is empty.
+	Checkpoint string
+	// RetryLoginRequired retries Items the checkpoint log records as needing
+	// the user to log in, for after users have been through update mode.
+	RetryLoginRequired bool
+	// OnResult, if set, is called with each result as it is known.
+	OnResult func(Result)
+
+	now func() time.Time
+}
+
+// Report lists the results of a migration, in the order of its targets.
+type Report struct {
+	Results []Result
+}
+
+// Count returns the number of Items with the given status.
+func (r Report) Count(status Status) int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// WithStatus returns the results with the given status.
+func (r Report) WithStatus(status Status) []Result {
+	var results []Result
+	for _, result := range r.Results {
+		if result.Status == status {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// Migrate updates the webhook of every target. It returns early, with the
+// results known so far, if ctx is cancelled; calls already in flight are
+// allowed to finish and are recorded.
+func Migrate(ctx context.Context, client *plaid.Client, targets []Target, options Options) (Report, error) {
+	if options.Webhook == "" {
+		return Report{}, errors.New("webhook migration - webhook must be specified")
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = 4
+	}
+	if options.Rate <= 0 {
+		options.Rate = 10
+	}
+	if options.now == nil {
+		options.now = time.Now
+	}
+
+	done := map[string]Result{}
+	var log io.Writer = io.Discard
+	if options.Checkpoint != "" {
+		var partial bool
+		var err error
+		if done, partial, err = readCheckpoint(options.Checkpoint); err != nil {
+			return Report{}, err
+		}
+		f, err := os.OpenFile(options.Checkpoint, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return Report{}, err
+		}
+		defer f.Close()
+		if partial {
+			if _, err := f.Write([]byte("\n")); err != nil {
+				return Report{}, err
+			}
+		}
+		log = f
+	}
+
+	results := make([]Result, len(targets))
+	var mu sync.Mutex
+	var logErr error
+	record := func(i int, result Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = result
+		if result.Status != StatusSkipped && logErr == nil {
+			line, _ := json.Marshal(result)
+			_, logErr = log.Write(append(line, '\n'))
+		}
+		if options.OnResult != nil {
+			options.OnResult(result)
+		}
+	}
+
+	// Rates above one call per nanosecond are as good as unlimited.
+	interval := time.Duration(float64(time.Second) / options.Rate)
+	if interval <= 0 {
+		interval = 1
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < options.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				record(i, migrate(client, targets[i], options))
+			}
+		}()
+	}
+
+	var err error
+dispatch:
+	for i, target := range targets {
+		if previous, ok := done[target.Key()]; ok && previous.Webhook == options.Webhook && skip(previous.Status, options) {
+			previous.Status = StatusSkipped
+			record(i, previous)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break dispatch
+		case <-ticker.C:
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break dispatch
+		case work <- i:
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	report := Report{}
+	for _, result := range results {
+		if result.Key != "" {
+			report.Results = append(report.Results, result)
+		}
+	}
+	if err == nil {
+		err = logErr
+	}
+	return report, err
+}
+
+func skip(status Status, options Options) bool {
+	return status == StatusUpdated || (status == StatusLoginRequired && !options.RetryLoginRequired)
+}
+
+func migrate(client *plaid.Client, target Target, options Options) Result {
+	result := Result{Key: target.Key(), ItemID: target.ItemID, Webhook: options.Webhook}
+	resp, err := client.UpdateItemWebhook(target.AccessToken, options.Webhook)
+	result.Time = options.now()
+	if err == nil {
+		result.Status = StatusUpdated
+		if resp.Item.ItemID != "" {
+			result.ItemID = resp.Item.ItemID
+		}
+		return result
+	}
+
+	result.Status = StatusFailed
+	result.Error = err.Error()
+	var plaidErr plaid.Error
+	if errors.As(err, &plaidErr) {
+		result.ErrorCode = plaidErr.ErrorCode
+		if plaidErr.ErrorCode == "ITEM_LOGIN_REQUIRED" {
+			result.Status = StatusLoginRequired
+		}
+	}
+	return result
+}
+
+// readCheckpoint returns the last result recorded for each key in the
+// checkpoint log at path, which may not exist yet, and whether the log ends
+// part way through a line. Such a line, left by a run that was killed, is
+// ignored.
+func readCheckpoint(path string) (map[string]Result, bool, error) {
+	done := map[string]Result{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var result Result
+		if err := json.Unmarshal(line, &result); err != nil || result.Key == "" {
+			continue
+		}
+		done[result.Key] = result
+	}
+	return done, len(data) > 0 && data[len(data)-1] != '\n', nil
+}
@@ -0,0 +1,238 @@
+package webhookmigration
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+	assert "github.com/stretchr/testify/require"
+)
+
+// fakePlaid serves /item/webhook/update, failing for the access tokens in
+// errors.
+type fakePlaid struct {
+	mu       sync.Mutex
+	webhooks map[string]string
+	errors   map[string]string
+	calls    int
+	inFlight int
+	maxCalls int
+}
+
+func newFakePlaid(t *testing.T) (*fakePlaid, *plaid.Client) {
+	f := &fakePlaid{webhooks: map[string]string{}, errors: map[string]string{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			AccessToken string `json:"access_token"`
+			Webhook     string `json:"webhook"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		f.mu.Lock()
+		f.calls++
+		f.inFlight++
+		if f.inFlight > f.maxCalls {
+			f.maxCalls = f.inFlight
+		}
+		code := f.errors[body.AccessToken]
+		f.mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.inFlight--
+		if code != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(plaid.Error{ErrorType: "ITEM_ERROR", ErrorCode: code})
+			return
+		}
+		f.webhooks[body.AccessToken] = body.Webhook
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"item": map[string]interface{}{"item_id": strings.Replace(body.AccessToken, "access", "item", 1), "webhook": body.Webhook},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := plaid.NewClient(plaid.ClientOptions{
+		ClientID:    "client-id",
+		Secret:      "secret",
+		Environment: plaid.Environment(server.URL),
+		HTTPClient:  server.Client(),
+	})
+	assert.NoError(t, err)
+	return f, client
+}
+
+func TestReadTargets(t *testing.T) {
+	targets, err := ReadTargets(strings.NewReader(`
+# exported from the database
+access-1
+{"access_token":"access-2","item_id":"item-2","linked_at":"2021-03-01T00:00:00Z"}
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Target{
+		{AccessToken: "access-1"},
+		{AccessToken: "access-2", ItemID: "item-2"},
+	}, targets)
+
+	assert.Equal(t, "item-2", targets[1].Key())
+	assert.True(t, strings.HasPrefix(targets[0].Key(), "token:"))
+	assert.NotContains(t, targets[0].Key(), "access-1")
+
+	_, err = ReadTargets(strings.NewReader(`{"item_id":"item-1"}`))
+	assert.EqualError(t, err, "line 1: access token must be specified")
+}
+
+func TestVaultTargets(t *testing.T) {
+	cipher, err := plaid.NewAESGCMCipher(make([]byte, 32))
+	assert.NoError(t, err)
+	vault := plaid.NewMemoryTokenVault(cipher)
+	assert.NoError(t, vault.PutAccessToken("item-1", "access-1"))
+
+	targets, err := VaultTargets(vault)
+	assert.NoError(t, err)
+	assert.Equal(t, []Target{{ItemID: "item-1", AccessToken: "access-1"}}, targets)
+}
+
+func testTargets(n int) []Target {
+	var targets []Target
+	for i := 0; i < n; i++ {
+		targets = append(targets, Target{AccessToken: "access-" + string(rune('a'+i))})
+	}
+	return targets
+}
+
+func TestMigrate(t *testing.T) {
+	f, client := newFakePlaid(t)
+	f.errors["access-b"] = "ITEM_LOGIN_REQUIRED"
+	f.errors["access-c"] = "INTERNAL_SERVER_ERROR"
+
+	var seen int
+	report, err := Migrate(context.Background(), client, testTargets(8), Options{
+		Webhook:     "https://new.example.com/plaid",
+		Concurrency: 2,
+		Rate:        1000,
+		OnResult:    func(Result) { seen++ },
+	})
+	assert.NoError(t, err)
+	assert.Len(t, report.Results, 8)
+	assert.Equal(t, 8, seen)
+	assert.Equal(t, 6, report.Count(StatusUpdated))
+	assert.LessOrEqual(t, f.maxCalls, 2)
+
+	assert.Equal(t, "item-a", report.Results[0].ItemID)
+	loginRequired := report.WithStatus(StatusLoginRequired)
+	assert.Len(t, loginRequired, 1)
+	assert.Equal(t, "ITEM_LOGIN_REQUIRED", loginRequired[0].ErrorCode)
+	assert.Equal(t, StatusFailed, report.Results[2].Status)
+	assert.Equal(t, "https://new.example.com/plaid", f.webhooks["access-h"])
+
+	_, err = Migrate(context.Background(), client, nil, Options{})
+	assert.Error(t, err)
+}
+
+func TestMigrateRateLimit(t *testing.T) {
+	_, client := newFakePlaid(t)
+	start := time.Now()
+	_, err := Migrate(context.Background(), client, testTargets(5), Options{
+		Webhook:     "https://new.example.com/plaid",
+		Concurrency: 5,
+		Rate:        50,
+	})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+}
+
+func TestMigrateUnlimitedRate(t *testing.T) {
+	_, client := newFakePlaid(t)
+	for _, rate := range []float64{2e9, math.Inf(1)} {
+		report, err := Migrate(context.Background(), client, testTargets(3), Options{
+			Webhook: "https://new.example.com/plaid",
+			Rate:    rate,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, report.Count(StatusUpdated))
+	}
+}
+
+func TestMigrateResumesFromCheckpoint(t *testing.T) {
+	f, client := newFakePlaid(t)
+	f.errors["access-b"] = "ITEM_LOGIN_REQUIRED"
+	f.errors["access-c"] = "INTERNAL_SERVER_ERROR"
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	options := Options{
+		Webhook:    "https://new.example.com/plaid",
+		Rate:       1000,
+		Checkpoint: checkpoint,
+	}
+
+	_, err := Migrate(context.Background(), client, testTargets(4), options)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, f.calls)
+
+	data, err := os.ReadFile(checkpoint)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "access-")
+	info, err := os.Stat(checkpoint)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// A run killed while writing leaves a partial line behind.
+	logFile, err := os.OpenFile(checkpoint, os.O_APPEND|os.O_WRONLY, 0600)
+	assert.NoError(t, err)
+	_, err = logFile.WriteString(`{"key":"tok`)
+	assert.NoError(t, err)
+	assert.NoError(t, logFile.Close())
+
+	// Only the failed Item is retried.
+	delete(f.errors, "access-c")
+	report, err := Migrate(context.Background(), client, testTargets(4), options)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, f.calls)
+	assert.Equal(t, 3, report.Count(StatusSkipped))
+	assert.Equal(t, StatusUpdated, report.Results[2].Status)
+
+	// Login required Items are retried on request.
+	delete(f.errors, "access-b")
+	options.RetryLoginRequired = true
+	report, err = Migrate(context.Background(), client, testTargets(4), options)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, f.calls)
+	assert.Equal(t, 4, report.Count(StatusSkipped)+report.Count(StatusUpdated))
+
+	// Moving to yet another webhook starts over.
+	options.Webhook = "https://newer.example.com/plaid"
+	_, err = Migrate(context.Background(), client, testTargets(4), options)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, f.calls)
+}
+
+func TestMigrateStopsWhenCancelled(t *testing.T) {
+	f, client := newFakePlaid(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	var n int
+	report, err := Migrate(ctx, client, testTargets(10), Options{
+		Webhook:     "https://new.example.com/plaid",
+		Concurrency: 1,
+		Rate:        1000,
+		OnResult: func(Result) {
+			n++
+			if n == 2 {
+				cancel()
+			}
+		},
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, len(report.Results), 10)
+	assert.Equal(t, len(report.Results), f.calls)
+}
@@ -0,0 +1,281 @@
+package plaid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ibanLengths holds the IBAN length of each country using IBANs.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16,
+	"BG": 22, "BH": 22, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28,
+	"CZ": 24, "DE": 22, "DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24,
+	"FI": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18,
+	"GR": 27, "GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23,
+	"IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LC": 32,
+	"LI": 21, "LT": 20, "LU": 20, "LV": 21, "LY": 25, "MC": 27, "MD": 24,
+	"ME": 22, "MK": 19, "MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15,
+	"PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22,
+	"RU": 33, "SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24,
+	"SM": 27, "ST": 25, "SV": 28, "TL": 23, "TN": 24, "TR": 26, "UA": 29,
+	"VA": 22, "VG": 24, "XK": 20,
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isUpperLetters(s string) bool {
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isUpperAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateRoutingNumber checks that s is a nine digit ABA routing number with
+// a valid prefix and checksum.
+func ValidateRoutingNumber(s string) error {
+	if len(s) != 9 || !isDigits(s) {
+		return fmt.Errorf("routing number %q must be 9 digits", s)
+	}
+
+	// The first two digits identify the Federal Reserve district (01-12),
+	// a thrift institution (21-32), an electronic transaction (61-72) or a
+	// traveler's check (80).
+	prefix := int(s[0]-'0')*10 + int(s[1]-'0')
+	switch {
+	case prefix <= 12, prefix >= 21 && prefix <= 32, prefix >= 61 && prefix <= 72, prefix == 80:
+	default:
+		return fmt.Errorf("routing number %q has an invalid prefix", s)
+	}
+
+	weights := [9]int{3, 7, 1, 3, 7, 1, 3, 7, 1}
+	sum := 0
+	for i := range s {
+		sum += int(s[i]-'0') * weights[i]
+	}
+	if sum%10 != 0 {
+		return fmt.Errorf("routing number %q has an invalid checksum", s)
+	}
+	return nil
+}
+
+// ValidateACHAccountNumber checks that s is a US bank account number of 4 to
+// 17 digits.
+func ValidateACHAccountNumber(s string) error {
+	if len(s) < 4 || len(s) > 17 || !isDigits(s) {
+		return fmt.Errorf("account number %q must be 4 to 17 digits", s)
+	}
+	return nil
+}
+
+// ValidateIBAN checks the country specific length and the mod-97 check digits
+// of an IBAN. Spaces are ignored and letters may be in any case.
+func ValidateIBAN(s string) error {
+	iban := strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	if len(iban) < 4 || !isUpperAlphanumeric(iban) {
+		return fmt.Errorf("IBAN %q must be alphanumeric", s)
+	}
+
+	country := iban[:2]
+	length, ok := ibanLengths[country]
+	if !ok || !isUpperLetters(country) {
+		return fmt.Errorf("IBAN %q has an unknown country code", s)
+	}
+	if len(iban) != length {
+		return fmt.Errorf("IBAN %q must be %d characters for country %s", s, length, country)
+	}
+	if !isDigits(iban[2:4]) {
+		return fmt.Errorf("IBAN %q has invalid check digits", s)
+	}
+
+	// Move the first four characters to the end, convert letters to numbers
+	// (A=10 ... Z=35) and compute the remainder piecewise.
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for _, r := range rearranged {
+		if r >= 'A' {
+			remainder = (remainder*100 + int(r-'A'+10)) % 97
+		} else {
+			remainder = (remainder*10 + int(r-'0')) % 97
+		}
+	}
+	if remainder != 1 {
+		return fmt.Errorf("IBAN %q has invalid check digits", s)
+	}
+	return nil
+}
+
+// ValidateBIC checks that s is an 8 or 11 character BIC (SWIFT code).
+func ValidateBIC(s string) error {
+	if len(s) != 8 && len(s) != 11 {
+		return fmt.Errorf("BIC %q must be 8 or 11 characters", s)
+	}
+	if !isUpperLetters(s[:6]) {
+		return fmt.Errorf("BIC %q must start with a 4 letter bank code and a 2 letter country code", s)
+	}
+	if !isUpperAlphanumeric(s[6:]) {
+		return fmt.Errorf("BIC %q has an invalid location or branch code", s)
+	}
+	return nil
+}
+
+// ValidateSortCode checks that s is a UK sort code of 6 digits, optionally
+// written as three hyphen separated pairs.
+func ValidateSortCode(s string) error {
+	code := s
+	if len(code) == 8 && code[2] == '-' && code[5] == '-' {
+		code = code[:2] + code[3:5] + code[6:]
+	}
+	if len(code) != 6 || !isDigits(code) {
+		return fmt.Errorf("sort code %q must be 6 digits", s)
+	}
+	return nil
+}
+
+// ValidateBACSAccountNumber checks that s is an 8 digit UK account number.
+func ValidateBACSAccountNumber(s string) error {
+	if len(s) != 8 || !isDigits(s) {
+		return fmt.Errorf("account number %q must be 8 digits", s)
+	}
+	return nil
+}
+
+// ValidateInstitutionNumber checks that s is a 3 digit Canadian financial
+// institution number.
+func ValidateInstitutionNumber(s string) error {
+	if len(s) != 3 || !isDigits(s) {
+		return fmt.Errorf("institution number %q must be 3 digits", s)
+	}
+	return nil
+}
+
+// ValidateTransitNumber checks that s is a 5 digit Canadian branch transit
+// number.
+func ValidateTransitNumber(s string) error {
+	if len(s) != 5 || !isDigits(s) {
+		return fmt.Errorf("transit number %q must be 5 digits", s)
+	}
+	return nil
+}
+
+// ValidateEFTAccountNumber checks that s is a Canadian account number of 7 to
+// 12 digits.
+func ValidateEFTAccountNumber(s string) error {
+	if len(s) < 7 || len(s) > 12 || !isDigits(s) {
+		return fmt.Errorf("account number %q must be 7 to 12 digits", s)
+	}
+	return nil
+}
+
+// Validate checks the account and routing numbers. The wire routing number is
+// only checked when present.
+func (n ACHNumber) Validate() error {
+	if err := ValidateACHAccountNumber(n.Account); err != nil {
+		return err
+	}
+	if err := ValidateRoutingNumber(n.Routing); err != nil {
+		return err
+	}
+	if n.WireRouting != "" {
+		return ValidateRoutingNumber(n.WireRouting)
+	}
+	return nil
+}
+
+// Validate checks the account, institution and branch transit numbers.
+func (n EFTNumber) Validate() error {
+	if err := ValidateEFTAccountNumber(n.Account); err != nil {
+		return err
+	}
+	if err := ValidateInstitutionNumber(n.Institution); err != nil {
+		return err
+	}
+	return ValidateTransitNumber(n.Branch)
+}
+
+// Validate checks the IBAN and BIC.
+func (n IBANNumber) Validate() error {
+	if err := ValidateIBAN(n.IBAN); err != nil {
+		return err
+	}
+	return ValidateBIC(n.BIC)
+}
+
+// Validate checks the account number and sort code.
+func (n BACSNumber) Validate() error {
+	if err := ValidateBACSAccountNumber(n.Account); err != nil {
+		return err
+	}
+	return ValidateSortCode(n.SortCode)
+}
+
+// Validate checks the account number and sort code.
+func (b PaymentRecipientBacs) Validate() error {
+	if err := ValidateBACSAccountNumber(b.Account); err != nil {
+		return err
+	}
+	return ValidateSortCode(b.SortCode)
+}
+
+// Validate checks every account number in the collection and returns the
+// first error found, annotated with the account ID.
+func (c AccountNumberCollection) Validate() error {
+	for _, n := range c.ACH {
+		if err := n.Validate(); err != nil {
+			return fmt.Errorf("account %s: %w", n.AccountID, err)
+		}
+	}
+	for _, n := range c.EFT {
+		if err := n.Validate(); err != nil {
+			return fmt.Errorf("account %s: %w", n.AccountID, err)
+		}
+	}
+	for _, n := range c.International {
+		if err := n.Validate(); err != nil {
+			return fmt.Errorf("account %s: %w", n.AccountID, err)
+		}
+	}
+	for _, n := range c.BACS {
+		if err := n.Validate(); err != nil {
+			return fmt.Errorf("account %s: %w", n.AccountID, err)
+		}
+	}
+	return nil
+}
+
+// validatePaymentRecipientNumbers checks the IBAN and BACS details passed to
+// /payment_initiation/recipient/create.
+func validatePaymentRecipientNumbers(params OptionalRecipientCreateParams) error {
+	if params.IBAN != nil {
+		if err := ValidateIBAN(*params.IBAN); err != nil {
+			return errors.New("/payment_initiation/recipient/create - " + err.Error())
+		}
+	}
+	if params.BACS != nil {
+		if err := params.BACS.Validate(); err != nil {
+			return errors.New("/payment_initiation/recipient/create - " + err.Error())
+		}
+	}
+	return nil
+}
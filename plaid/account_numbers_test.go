@@ -0,0 +1,185 @@
+package plaid
+
+import (
+	"net/http"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestValidateRoutingNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		routing string
+		valid   bool
+	}{
+		{"chase", "021000021", true},
+		{"wells fargo", "121042882", true},
+		{"bank of america", "026009593", true},
+		{"thrift", "322271627", true},
+		{"bad checksum", "021000022", false},
+		{"bad prefix", "501000019", false},
+		{"too short", "02100002", false},
+		{"too long", "0210000210", false},
+		{"letters", "02100002A", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRoutingNumber(tt.routing)
+			assert.Equal(t, tt.valid, err == nil, "%v", err)
+		})
+	}
+}
+
+func TestValidateACHAccountNumber(t *testing.T) {
+	tests := []struct {
+		account string
+		valid   bool
+	}{
+		{"1111222233330000", true},
+		{"1234", true},
+		{"12345678901234567", true},
+		{"123", false},
+		{"123456789012345678", false},
+		{"1234-5678", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.account, func(t *testing.T) {
+			err := ValidateACHAccountNumber(tt.account)
+			assert.Equal(t, tt.valid, err == nil, "%v", err)
+		})
+	}
+}
+
+func TestValidateIBAN(t *testing.T) {
+	tests := []struct {
+		name  string
+		iban  string
+		valid bool
+	}{
+		{"gb", "GB33BUKB20201555555555", true},
+		{"gb with spaces", "GB82 WEST 1234 5698 7654 32", true},
+		{"de", "DE89370400440532013000", true},
+		{"fr with letters", "FR1420041010050500013M02606", true},
+		{"nl", "NL91ABNA0417164300", true},
+		{"lower case", "nl91abna0417164300", true},
+		{"bad check digits", "GB34BUKB20201555555555", false},
+		{"wrong length for country", "GB33BUKB2020155555555", false},
+		{"unknown country", "ZZ33BUKB20201555555555", false},
+		{"non numeric check digits", "GBXXBUKB20201555555555", false},
+		{"punctuation", "GB33-BUKB-2020-1555-5555-55", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateIBAN(tt.iban)
+			assert.Equal(t, tt.valid, err == nil, "%v", err)
+		})
+	}
+}
+
+func TestValidateBIC(t *testing.T) {
+	tests := []struct {
+		bic   string
+		valid bool
+	}{
+		{"DEUTDEFF", true},
+		{"DEUTDEFF500", true},
+		{"NWBKGB2L", true},
+		{"DEUTDEF", false},
+		{"DEUTDEFF5", false},
+		{"DEU1DEFF", false},
+		{"deutdeff", false},
+		{"DEUTDEFF50!", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.bic, func(t *testing.T) {
+			err := ValidateBIC(tt.bic)
+			assert.Equal(t, tt.valid, err == nil, "%v", err)
+		})
+	}
+}
+
+func TestValidateBACS(t *testing.T) {
+	tests := []struct {
+		name     string
+		account  string
+		sortCode string
+		valid    bool
+	}{
+		{"plain", "26207729", "560029", true},
+		{"hyphenated sort code", "12345678", "01-02-03", true},
+		{"short account", "1234567", "560029", false},
+		{"long account", "123456789", "560029", false},
+		{"short sort code", "26207729", "56002", false},
+		{"misplaced hyphens", "26207729", "5600-29", false},
+		{"letters", "2620772A", "560029", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := BACSNumber{Account: tt.account, SortCode: tt.sortCode}.Validate()
+			assert.Equal(t, tt.valid, err == nil, "%v", err)
+			err = PaymentRecipientBacs{Account: tt.account, SortCode: tt.sortCode}.Validate()
+			assert.Equal(t, tt.valid, err == nil, "%v", err)
+		})
+	}
+}
+
+func TestValidateEFT(t *testing.T) {
+	tests := []struct {
+		name   string
+		number EFTNumber
+		valid  bool
+	}{
+		{"valid", EFTNumber{Account: "111122223333", Institution: "021", Branch: "01140"}, true},
+		{"short account", EFTNumber{Account: "123456", Institution: "021", Branch: "01140"}, false},
+		{"long institution", EFTNumber{Account: "1111222", Institution: "0210", Branch: "01140"}, false},
+		{"short transit", EFTNumber{Account: "1111222", Institution: "021", Branch: "0114"}, false},
+		{"letters", EFTNumber{Account: "1111222", Institution: "02A", Branch: "01140"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.number.Validate()
+			assert.Equal(t, tt.valid, err == nil, "%v", err)
+		})
+	}
+}
+
+func TestValidateAccountNumberCollection(t *testing.T) {
+	numbers := AccountNumberCollection{
+		ACH:           []ACHNumber{{AccountID: "a", Account: "1111222233330000", Routing: "011401533", WireRouting: "021000021"}},
+		EFT:           []EFTNumber{{AccountID: "b", Account: "111122223333", Institution: "021", Branch: "01140"}},
+		International: []IBANNumber{{AccountID: "c", IBAN: "GB33BUKB20201555555555", BIC: "NWBKGB2L"}},
+		BACS:          []BACSNumber{{AccountID: "d", Account: "26207729", SortCode: "560029"}},
+	}
+	assert.Nil(t, numbers.Validate())
+
+	numbers.ACH[0].WireRouting = "021000022"
+	err := numbers.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "account a")
+}
+
+func TestCreatePaymentRecipientValidation(t *testing.T) {
+	server, client := newFakeServer(t)
+	server.handle("/payment_initiation/recipient/create", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, CreatePaymentRecipientResponse{RecipientID: "recipient-1"}
+	})
+	client.validateAccountNumbers = true
+
+	iban := "GB34BUKB20201555555555"
+	_, err := client.CreatePaymentRecipient("John Doe", OptionalRecipientCreateParams{IBAN: &iban})
+	assert.NotNil(t, err)
+
+	_, err = client.CreatePaymentRecipient("John Doe", OptionalRecipientCreateParams{
+		BACS: &PaymentRecipientBacs{Account: "2620772", SortCode: "560029"},
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, server.callCount("/payment_initiation/recipient/create"))
+
+	iban = "GB33BUKB20201555555555"
+	resp, err := client.CreatePaymentRecipient("John Doe", OptionalRecipientCreateParams{IBAN: &iban})
+	assert.Nil(t, err)
+	assert.Equal(t, "recipient-1", resp.RecipientID)
+}
@@ -3,8 +3,6 @@ package plaid
 import (
 	"encoding/json"
 	"errors"
-
-	"github.com/perchcredit/alderson/currency"
 )
 
 type Account struct {
@@ -19,11 +17,11 @@ type Account struct {
 }
 
 type AccountBalances struct {
-	Available              float64                `json:"available"`
-	Current                float64                `json:"current"`
-	Limit                  float64                `json:"limit"`
-	ISOCurrencyCode        currency.CurrencyCode  `json:"iso_currency_code"`
-	UnofficialCurrencyCode UnofficialCurrencyCode `json:"unofficial_currency_code"`
+	Available              float64  `json:"available"`
+	Current                float64  `json:"current"`
+	Limit                  float64  `json:"limit"`
+	ISOCurrencyCode        Currency `json:"iso_currency_code"`
+	UnofficialCurrencyCode Currency `json:"unofficial_currency_code"`
 }
 
 type ACHNumber struct {
@@ -66,6 +64,7 @@ type getBalancesRequest struct {
 type GetBalancesResponse struct {
 	APIResponse
 	Accounts []Account `json:"accounts"`
+	Item     Item      `json:"item"`
 }
 
 type getAccountsRequestOptions struct {
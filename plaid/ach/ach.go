@@ -0,0 +1,228 @@
+// Package ach builds and parses NACHA formatted ACH files from the account and
+// routing numbers returned by /auth/get.
+//
+// A File is written with File.Write, which validates every entry, assigns
+// batch and trace numbers and computes the control records. Parse reads a
+// file back and verifies its control totals, so a written file can be checked
+// by round-tripping it.
+package ach
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+)
+
+// recordLength is the fixed length of every record in a NACHA file.
+const recordLength = 94
+
+// blockingFactor is the number of records per block; files are padded with
+// filler records to a multiple of it.
+const blockingFactor = 10
+
+type TransactionCode int
+
+const (
+	transactionCodeCheckingCredit        TransactionCode = 22
+	transactionCodeCheckingCreditPrenote TransactionCode = 23
+	transactionCodeCheckingDebit         TransactionCode = 27
+	transactionCodeCheckingDebitPrenote  TransactionCode = 28
+	transactionCodeSavingsCredit         TransactionCode = 32
+	transactionCodeSavingsCreditPrenote  TransactionCode = 33
+	transactionCodeSavingsDebit          TransactionCode = 37
+	transactionCodeSavingsDebitPrenote   TransactionCode = 38
+)
+
+type transactionCodes struct {
+	CheckingCredit        TransactionCode
+	CheckingCreditPrenote TransactionCode
+	CheckingDebit         TransactionCode
+	CheckingDebitPrenote  TransactionCode
+	SavingsCredit         TransactionCode
+	SavingsCreditPrenote  TransactionCode
+	SavingsDebit          TransactionCode
+	SavingsDebitPrenote   TransactionCode
+}
+
+var TransactionCodes transactionCodes = transactionCodes{
+	CheckingCredit:        transactionCodeCheckingCredit,
+	CheckingCreditPrenote: transactionCodeCheckingCreditPrenote,
+	CheckingDebit:         transactionCodeCheckingDebit,
+	CheckingDebitPrenote:  transactionCodeCheckingDebitPrenote,
+	SavingsCredit:         transactionCodeSavingsCredit,
+	SavingsCreditPrenote:  transactionCodeSavingsCreditPrenote,
+	SavingsDebit:          transactionCodeSavingsDebit,
+	SavingsDebitPrenote:   transactionCodeSavingsDebitPrenote,
+}
+
+// IsCredit reports whether the code moves money into the receiver's account.
+func (c TransactionCode) IsCredit() bool {
+	switch c {
+	case transactionCodeCheckingCredit, transactionCodeCheckingCreditPrenote,
+		transactionCodeSavingsCredit, transactionCodeSavingsCreditPrenote:
+		return true
+	}
+	return false
+}
+
+// IsDebit reports whether the code moves money out of the receiver's account.
+func (c TransactionCode) IsDebit() bool {
+	switch c {
+	case transactionCodeCheckingDebit, transactionCodeCheckingDebitPrenote,
+		transactionCodeSavingsDebit, transactionCodeSavingsDebitPrenote:
+		return true
+	}
+	return false
+}
+
+// IsPrenote reports whether the code is a zero dollar prenotification.
+func (c TransactionCode) IsPrenote() bool {
+	switch c {
+	case transactionCodeCheckingCreditPrenote, transactionCodeCheckingDebitPrenote,
+		transactionCodeSavingsCreditPrenote, transactionCodeSavingsDebitPrenote:
+		return true
+	}
+	return false
+}
+
+type ServiceClassCode int
+
+const (
+	serviceClassCodeMixed   ServiceClassCode = 200
+	serviceClassCodeCredits ServiceClassCode = 220
+	serviceClassCodeDebits  ServiceClassCode = 225
+)
+
+type serviceClassCodes struct {
+	Mixed   ServiceClassCode
+	Credits ServiceClassCode
+	Debits  ServiceClassCode
+}
+
+var ServiceClassCodes serviceClassCodes = serviceClassCodes{
+	Mixed:   serviceClassCodeMixed,
+	Credits: serviceClassCodeCredits,
+	Debits:  serviceClassCodeDebits,
+}
+
+type StandardEntryClass string
+
+const (
+	standardEntryClassPPD StandardEntryClass = "PPD"
+	standardEntryClassCCD StandardEntryClass = "CCD"
+)
+
+type standardEntryClasses struct {
+	// PPD entries move money to or from consumer accounts.
+	PPD StandardEntryClass
+	// CCD entries move money to or from business accounts.
+	CCD StandardEntryClass
+}
+
+var StandardEntryClasses standardEntryClasses = standardEntryClasses{
+	PPD: standardEntryClassPPD,
+	CCD: standardEntryClassCCD,
+}
+
+// File is a NACHA file made of one or more batches.
+type File struct {
+	Header  FileHeader
+	Batches []Batch
+}
+
+// FileHeader holds the fields of the file header record.
+type FileHeader struct {
+	// ImmediateDestination is the routing number of the ACH operator or
+	// receiving point the file is sent to.
+	ImmediateDestination string
+	// ImmediateOrigin identifies the sender, usually the ODFI routing number
+	// or a "1" followed by the company's tax ID.
+	ImmediateOrigin          string
+	ImmediateDestinationName string
+	ImmediateOriginName      string
+	// CreationTime defaults to the current time when the file is written.
+	CreationTime time.Time
+	// FileIDModifier distinguishes files created on the same day; it must be
+	// an upper case letter or digit and defaults to 'A'.
+	FileIDModifier byte
+	ReferenceCode  string
+}
+
+// Batch is a group of entries sharing an originator and entry class.
+type Batch struct {
+	Header  BatchHeader
+	Entries []Entry
+}
+
+// BatchHeader holds the fields of a batch header record.
+type BatchHeader struct {
+	// ServiceClassCode is derived from the batch's entries when left at zero.
+	ServiceClassCode         ServiceClassCode
+	CompanyName              string
+	CompanyDiscretionaryData string
+	CompanyIdentification    string
+	StandardEntryClass       StandardEntryClass
+	CompanyEntryDescription  string
+	CompanyDescriptiveDate   string
+	EffectiveEntryDate       time.Time
+	// OriginatingDFI is the routing number of the originating bank. Only its
+	// first eight digits are written.
+	OriginatingDFI string
+	// BatchNumber is assigned sequentially when the file is written.
+	BatchNumber int
+}
+
+// Entry is a single debit or credit to a receiver's account.
+type Entry struct {
+	TransactionCode TransactionCode
+	RoutingNumber   string
+	AccountNumber   string
+	// Amount is expressed in cents.
+	Amount int64
+	// IndividualID is the receiver's identification number at the
+	// originator, e.g. a customer ID.
+	IndividualID string
+	// IndividualName is the receiver's name, or the receiving company's name
+	// in CCD batches.
+	IndividualName    string
+	DiscretionaryData string
+	Addenda           []Addenda
+	// TraceNumber is assigned when the file is written if left empty.
+	TraceNumber string
+}
+
+// Addenda carries payment related information for an entry.
+type Addenda struct {
+	PaymentRelatedInformation string
+}
+
+// AmountFromDollars converts a dollar amount as returned by Plaid to cents.
+func AmountFromDollars(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// NewEntry creates an entry paying to or collecting from an account returned
+// by /auth/get. The routing and account numbers are validated so that bad Auth
+// data is rejected before a file is built, and so are the individual ID and
+// name, which NACHA limits to printable ASCII.
+func NewEntry(number plaid.ACHNumber, code TransactionCode, amount int64, individualID, individualName string) (Entry, error) {
+	if err := number.Validate(); err != nil {
+		return Entry{}, fmt.Errorf("ach: account %s: %w", number.AccountID, err)
+	}
+	if !code.IsCredit() && !code.IsDebit() {
+		return Entry{}, fmt.Errorf("ach: unsupported transaction code %d", code)
+	}
+	if err := validateAlpha("individual ID", individualID, "individual name", individualName); err != nil {
+		return Entry{}, fmt.Errorf("ach: %w", err)
+	}
+	return Entry{
+		TransactionCode: code,
+		RoutingNumber:   number.Routing,
+		AccountNumber:   number.Account,
+		Amount:          amount,
+		IndividualID:    individualID,
+		IndividualName:  individualName,
+	}, nil
+}
@@ -0,0 +1,203 @@
+package ach
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+	assert "github.com/stretchr/testify/require"
+)
+
+func testFile(t *testing.T) *File {
+	payout, err := NewEntry(plaid.ACHNumber{
+		AccountID: "account-1",
+		Account:   "1111222233330000",
+		Routing:   "011401533",
+	}, TransactionCodes.CheckingCredit, AmountFromDollars(125.5), "CUST-1", "Jane Doe")
+	assert.Nil(t, err)
+
+	savings, err := NewEntry(plaid.ACHNumber{
+		AccountID: "account-2",
+		Account:   "1111222233331111",
+		Routing:   "021000021",
+	}, TransactionCodes.SavingsCredit, 1000, "CUST-2", "John Smith")
+	assert.Nil(t, err)
+
+	collection, err := NewEntry(plaid.ACHNumber{
+		AccountID: "account-3",
+		Account:   "987654321",
+		Routing:   "121042882",
+	}, TransactionCodes.CheckingDebit, 250000, "INV-42", "Acme Corp")
+	assert.Nil(t, err)
+	collection.Addenda = []Addenda{{PaymentRelatedInformation: "Invoice 42"}}
+
+	effective := time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)
+	return &File{
+		Header: FileHeader{
+			ImmediateDestination:     "021000021",
+			ImmediateOrigin:          "1234567890",
+			ImmediateDestinationName: "JPMorgan Chase",
+			ImmediateOriginName:      "Perch Credit",
+			CreationTime:             time.Date(2021, 3, 1, 13, 45, 0, 0, time.UTC),
+		},
+		Batches: []Batch{
+			{
+				Header: BatchHeader{
+					CompanyName:             "Perch Credit",
+					CompanyIdentification:   "1234567890",
+					StandardEntryClass:      StandardEntryClasses.PPD,
+					CompanyEntryDescription: "Payout",
+					EffectiveEntryDate:      effective,
+					OriginatingDFI:          "021000021",
+				},
+				Entries: []Entry{payout, savings},
+			},
+			{
+				Header: BatchHeader{
+					CompanyName:             "Perch Credit",
+					CompanyIdentification:   "1234567890",
+					StandardEntryClass:      StandardEntryClasses.CCD,
+					CompanyEntryDescription: "Invoice",
+					EffectiveEntryDate:      effective,
+					OriginatingDFI:          "021000021",
+				},
+				Entries: []Entry{collection},
+			},
+		},
+	}
+}
+
+func TestWrite(t *testing.T) {
+	f := testFile(t)
+	data, err := f.Bytes()
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	assert.Len(t, lines, 10)
+	for _, line := range lines {
+		assert.Len(t, line, recordLength)
+	}
+
+	assert.Equal(t, "101 02100002112345678902103011345A094101JPMORGAN CHASE         PERCH CREDIT                   ", lines[0])
+	assert.Equal(t, "5220PERCH CREDIT                        1234567890PPDPAYOUT          210302   1021000020000001", lines[1])
+	assert.Equal(t, "6220114015331111222233330000 0000012550CUST-1         JANE DOE                0021000020000001", lines[2])
+	assert.Equal(t, "6320210000211111222233331111 0000001000CUST-2         JOHN SMITH              0021000020000002", lines[3])
+	assert.Equal(t, "822000000200032401550000000000000000000135501234567890                         021000020000001", lines[4])
+	assert.Equal(t, "5225PERCH CREDIT                        1234567890CCDINVOICE         210302   1021000020000002", lines[5])
+	assert.Equal(t, "627121042882987654321        0000250000INV-42         ACME CORP               1021000020000003", lines[6])
+	assert.Equal(t, "705INVOICE 42                                                                      00010000003", lines[7])
+	assert.Equal(t, "822500000200121042880000002500000000000000001234567890                         021000020000002", lines[8])
+	assert.Equal(t, "9000002000001000000040015344443000000250000000000013550                                       ", lines[9])
+
+	assert.Equal(t, ServiceClassCodes.Credits, f.Batches[0].Header.ServiceClassCode)
+	assert.Equal(t, ServiceClassCodes.Debits, f.Batches[1].Header.ServiceClassCode)
+}
+
+func TestRoundTrip(t *testing.T) {
+	data, err := testFile(t).Bytes()
+	assert.Nil(t, err)
+
+	parsed, err := Parse(bytes.NewReader(data))
+	assert.Nil(t, err)
+	assert.Len(t, parsed.Batches, 2)
+	assert.Equal(t, "021000021", parsed.Header.ImmediateDestination)
+	assert.Equal(t, StandardEntryClasses.CCD, parsed.Batches[1].Header.StandardEntryClass)
+	assert.Equal(t, int64(12550), parsed.Batches[0].Entries[0].Amount)
+	assert.Equal(t, "1111222233330000", parsed.Batches[0].Entries[0].AccountNumber)
+	assert.Equal(t, "INVOICE 42", parsed.Batches[1].Entries[0].Addenda[0].PaymentRelatedInformation)
+
+	rewritten, err := parsed.Bytes()
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), string(rewritten))
+}
+
+func TestPadding(t *testing.T) {
+	f := testFile(t)
+	f.Batches = f.Batches[1:]
+	data, err := f.Bytes()
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	assert.Len(t, lines, 10)
+	assert.Equal(t, strings.Repeat("9", recordLength), lines[9])
+	assert.True(t, strings.HasPrefix(lines[5], "9000001000001"))
+
+	_, err = Parse(bytes.NewReader(data))
+	assert.Nil(t, err)
+}
+
+func TestParseDetectsTampering(t *testing.T) {
+	data, err := testFile(t).Bytes()
+	assert.Nil(t, err)
+
+	// Change the amount of the first entry without updating the controls.
+	tampered := strings.Replace(string(data), "0000012550CUST-1", "0000099999CUST-1", 1)
+	_, err = Parse(strings.NewReader(tampered))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "total credit amount")
+
+	_, err = Parse(strings.NewReader(string(data[:len(data)-95])))
+	assert.NotNil(t, err)
+}
+
+func TestNewEntryRejectsInvalidAuthData(t *testing.T) {
+	_, err := NewEntry(plaid.ACHNumber{Account: "1111222233330000", Routing: "011401534"},
+		TransactionCodes.CheckingCredit, 100, "CUST-1", "Jane Doe")
+	assert.NotNil(t, err)
+
+	_, err = NewEntry(plaid.ACHNumber{Account: "12", Routing: "011401533"},
+		TransactionCodes.CheckingCredit, 100, "CUST-1", "Jane Doe")
+	assert.NotNil(t, err)
+
+	_, err = NewEntry(plaid.ACHNumber{Account: "1111222233330000", Routing: "011401533"},
+		TransactionCode(99), 100, "CUST-1", "Jane Doe")
+	assert.NotNil(t, err)
+
+	_, err = NewEntry(plaid.ACHNumber{Account: "1111222233330000", Routing: "011401533"},
+		TransactionCodes.CheckingCredit, 100, "CUST-1", "José Núñez")
+	assert.NotNil(t, err)
+
+	_, err = NewEntry(plaid.ACHNumber{Account: "1111222233330000", Routing: "011401533"},
+		TransactionCodes.CheckingCredit, 100, "CUST-1\n", "Jane Doe")
+	assert.NotNil(t, err)
+}
+
+func TestWriteValidation(t *testing.T) {
+	f := testFile(t)
+	f.Batches[0].Entries[0].RoutingNumber = "011401534"
+	_, err := f.Bytes()
+	assert.NotNil(t, err)
+
+	f = testFile(t)
+	f.Batches[0].Header.StandardEntryClass = "WEB"
+	_, err = f.Bytes()
+	assert.NotNil(t, err)
+
+	f = testFile(t)
+	f.Batches[0].Header.ServiceClassCode = ServiceClassCodes.Debits
+	_, err = f.Bytes()
+	assert.NotNil(t, err)
+
+	f = testFile(t)
+	f.Batches[1].Entries[0].Addenda = append(f.Batches[1].Entries[0].Addenda, Addenda{})
+	_, err = f.Bytes()
+	assert.NotNil(t, err)
+
+	// Non-ASCII text would shift every later field of the record.
+	f = testFile(t)
+	f.Batches[0].Entries[0].IndividualName = "Zoë Smith"
+	_, err = f.Bytes()
+	assert.NotNil(t, err)
+
+	f = testFile(t)
+	f.Batches[0].Header.CompanyName = "Café Ltd"
+	_, err = f.Bytes()
+	assert.NotNil(t, err)
+
+	f = testFile(t)
+	f.Header.ImmediateOriginName = "ACME\tBANK"
+	_, err = f.Bytes()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,286 @@
+package ach
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field returns the characters of a record between the 1-indexed, inclusive
+// positions used by the NACHA specification, with padding removed.
+func field(record string, start, end int) string {
+	return strings.TrimSpace(record[start-1 : end])
+}
+
+func numericField(record string, start, end int) (int64, error) {
+	s := field(record, start, end)
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("positions %d-%d: %q is not numeric", start, end, s)
+	}
+	return n, nil
+}
+
+type parser struct {
+	records []string
+	pos     int
+}
+
+// Parse reads a NACHA file and verifies its batch and file control totals.
+func Parse(r io.Reader) (*File, error) {
+	p := &parser{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		record := strings.TrimRight(scanner.Text(), "\r")
+		if record == "" {
+			continue
+		}
+		if len(record) != recordLength {
+			return nil, fmt.Errorf("ach: line %d is %d characters, expected %d", len(p.records)+1, len(record), recordLength)
+		}
+		p.records = append(p.records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	f, err := p.file()
+	if err != nil {
+		return nil, fmt.Errorf("ach: line %d: %w", p.pos+1, err)
+	}
+	return f, nil
+}
+
+func (p *parser) next(recordType byte) (string, error) {
+	if p.pos >= len(p.records) {
+		return "", fmt.Errorf("unexpected end of file, expected record type %c", recordType)
+	}
+	record := p.records[p.pos]
+	if record[0] != recordType {
+		return "", fmt.Errorf("unexpected record type %c, expected %c", record[0], recordType)
+	}
+	p.pos++
+	return record, nil
+}
+
+func (p *parser) peek() byte {
+	if p.pos >= len(p.records) {
+		return 0
+	}
+	return p.records[p.pos][0]
+}
+
+func (p *parser) file() (*File, error) {
+	record, err := p.next('1')
+	if err != nil {
+		return nil, err
+	}
+	created, err := time.Parse("0601021504", field(record, 24, 33))
+	if err != nil {
+		return nil, fmt.Errorf("invalid file creation date: %w", err)
+	}
+	f := &File{
+		Header: FileHeader{
+			ImmediateDestination:     field(record, 4, 13),
+			ImmediateOrigin:          field(record, 14, 23),
+			CreationTime:             created,
+			FileIDModifier:           record[33],
+			ImmediateDestinationName: field(record, 41, 63),
+			ImmediateOriginName:      field(record, 64, 86),
+			ReferenceCode:            field(record, 87, 94),
+		},
+	}
+
+	var (
+		entryCount   int64
+		entryHash    int64
+		totalDebits  int64
+		totalCredits int64
+	)
+	for p.peek() == '5' {
+		batch, count, hash, debits, credits, err := p.batch()
+		if err != nil {
+			return nil, err
+		}
+		f.Batches = append(f.Batches, batch)
+		entryCount += count
+		entryHash += hash
+		totalDebits += debits
+		totalCredits += credits
+	}
+
+	record, err = p.next('9')
+	if err != nil {
+		return nil, err
+	}
+	if err := expect(record, 2, 7, int64(len(f.Batches)), "batch count"); err != nil {
+		return nil, err
+	}
+	if err := expect(record, 8, 13, int64((p.pos+blockingFactor-1)/blockingFactor), "block count"); err != nil {
+		return nil, err
+	}
+	if err := expect(record, 14, 21, entryCount, "entry and addenda count"); err != nil {
+		return nil, err
+	}
+	if err := expect(record, 22, 31, entryHash%10000000000, "entry hash"); err != nil {
+		return nil, err
+	}
+	if err := expect(record, 32, 43, totalDebits, "total debit amount"); err != nil {
+		return nil, err
+	}
+	if err := expect(record, 44, 55, totalCredits, "total credit amount"); err != nil {
+		return nil, err
+	}
+
+	for ; p.pos < len(p.records); p.pos++ {
+		if p.records[p.pos] != strings.Repeat("9", recordLength) {
+			return nil, fmt.Errorf("unexpected record after file control")
+		}
+	}
+	if len(p.records)%blockingFactor != 0 {
+		return nil, fmt.Errorf("file is not padded to a multiple of %d records", blockingFactor)
+	}
+	return f, nil
+}
+
+func (p *parser) batch() (batch Batch, count, hash, debits, credits int64, err error) {
+	record, err := p.next('5')
+	if err != nil {
+		return
+	}
+	serviceClass, err := numericField(record, 2, 4)
+	if err != nil {
+		return
+	}
+	effective, err := time.Parse("060102", field(record, 70, 75))
+	if err != nil {
+		err = fmt.Errorf("invalid effective entry date: %w", err)
+		return
+	}
+	batchNumber, err := numericField(record, 88, 94)
+	if err != nil {
+		return
+	}
+	batch.Header = BatchHeader{
+		ServiceClassCode:         ServiceClassCode(serviceClass),
+		CompanyName:              field(record, 5, 20),
+		CompanyDiscretionaryData: field(record, 21, 40),
+		CompanyIdentification:    field(record, 41, 50),
+		StandardEntryClass:       StandardEntryClass(field(record, 51, 53)),
+		CompanyEntryDescription:  field(record, 54, 63),
+		CompanyDescriptiveDate:   field(record, 64, 69),
+		EffectiveEntryDate:       effective,
+		// Only the first eight digits of the ODFI routing number are
+		// recorded in the file.
+		OriginatingDFI: field(record, 80, 87),
+		BatchNumber:    int(batchNumber),
+	}
+
+	for p.peek() == '6' {
+		var entry Entry
+		entry, err = p.entry()
+		if err != nil {
+			return
+		}
+		batch.Entries = append(batch.Entries, entry)
+		count += int64(1 + len(entry.Addenda))
+		hash += dfiID(entry.RoutingNumber)
+		if entry.TransactionCode.IsDebit() {
+			debits += entry.Amount
+		} else {
+			credits += entry.Amount
+		}
+	}
+
+	record, err = p.next('8')
+	if err != nil {
+		return
+	}
+	if err = expect(record, 2, 4, serviceClass, "service class code"); err != nil {
+		return
+	}
+	if err = expect(record, 5, 10, count, "entry and addenda count"); err != nil {
+		return
+	}
+	if err = expect(record, 11, 20, hash%10000000000, "entry hash"); err != nil {
+		return
+	}
+	if err = expect(record, 21, 32, debits, "total debit amount"); err != nil {
+		return
+	}
+	if err = expect(record, 33, 44, credits, "total credit amount"); err != nil {
+		return
+	}
+	err = expect(record, 88, 94, batchNumber, "batch number")
+	return
+}
+
+func (p *parser) entry() (entry Entry, err error) {
+	record, err := p.next('6')
+	if err != nil {
+		return
+	}
+	code, err := numericField(record, 2, 3)
+	if err != nil {
+		return
+	}
+	amount, err := numericField(record, 30, 39)
+	if err != nil {
+		return
+	}
+	entry = Entry{
+		TransactionCode:   TransactionCode(code),
+		RoutingNumber:     field(record, 4, 12),
+		AccountNumber:     field(record, 13, 29),
+		Amount:            amount,
+		IndividualID:      field(record, 40, 54),
+		IndividualName:    field(record, 55, 76),
+		DiscretionaryData: field(record, 77, 78),
+		TraceNumber:       field(record, 80, 94),
+	}
+	if err = entry.validate(); err != nil {
+		return
+	}
+
+	if record[78] == '1' {
+		for p.peek() == '7' {
+			record, err = p.next('7')
+			if err != nil {
+				return
+			}
+			if field(record, 2, 3) != "05" {
+				err = fmt.Errorf("unsupported addenda type %q", field(record, 2, 3))
+				return
+			}
+			if err = expect(record, 84, 87, int64(len(entry.Addenda)+1), "addenda sequence number"); err != nil {
+				return
+			}
+			if field(record, 88, 94) != entry.TraceNumber[8:] {
+				err = fmt.Errorf("addenda does not match entry %s", entry.TraceNumber)
+				return
+			}
+			entry.Addenda = append(entry.Addenda, Addenda{
+				PaymentRelatedInformation: field(record, 4, 83),
+			})
+		}
+		if len(entry.Addenda) == 0 {
+			err = fmt.Errorf("entry %s is missing its addenda record", entry.TraceNumber)
+		}
+	}
+	return
+}
+
+func expect(record string, start, end int, want int64, name string) error {
+	got, err := numericField(record, start, end)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("%s is %d, expected %d", name, got, want)
+	}
+	return nil
+}
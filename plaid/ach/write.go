@@ -0,0 +1,369 @@
+package ach
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+)
+
+// alpha left-justifies s in a field of width characters, upper-casing it and
+// truncating it if needed. s must have been checked with validateAlpha, so
+// that truncating it by bytes keeps whole characters.
+func alpha(s string, width int) string {
+	s = strings.ToUpper(s)
+	if len(s) > width {
+		return s[:width]
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
+
+// validateAlpha checks that alphanumeric fields only hold printable ASCII,
+// which is all NACHA allows. Fields are given as name, value pairs.
+func validateAlpha(fields ...string) error {
+	for i := 0; i+1 < len(fields); i += 2 {
+		for _, r := range fields[i+1] {
+			if r < ' ' || r > '~' {
+				return fmt.Errorf("%s %q must be printable ASCII", fields[i], fields[i+1])
+			}
+		}
+	}
+	return nil
+}
+
+// numeric right-justifies n in a zero padded field of width digits.
+func numeric(n int64, width int) string {
+	s := strconv.FormatInt(n, 10)
+	if len(s) > width {
+		return s[len(s)-width:]
+	}
+	return strings.Repeat("0", width-len(s)) + s
+}
+
+// rightJustify right-justifies s in a space padded field of width characters.
+func rightJustify(s string, width int) string {
+	if len(s) > width {
+		return s[len(s)-width:]
+	}
+	return strings.Repeat(" ", width-len(s)) + s
+}
+
+// dfiID returns the eight digit DFI identification of a routing number.
+func dfiID(routingNumber string) int64 {
+	id, _ := strconv.ParseInt(routingNumber[:8], 10, 64)
+	return id
+}
+
+// Bytes returns the file in NACHA format.
+func (f *File) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write validates the file and writes it to w in NACHA format. Batch numbers,
+// trace numbers, service class codes and defaults are filled in on f.
+func (f *File) Write(w io.Writer) error {
+	if err := f.prepare(); err != nil {
+		return err
+	}
+
+	var records []string
+	records = append(records, f.headerRecord())
+
+	var (
+		entryCount   int64
+		entryHash    int64
+		totalDebits  int64
+		totalCredits int64
+	)
+	for i := range f.Batches {
+		batch := &f.Batches[i]
+		records = append(records, batch.headerRecord())
+
+		var (
+			batchCount   int64
+			batchHash    int64
+			batchDebits  int64
+			batchCredits int64
+		)
+		for _, entry := range batch.Entries {
+			records = append(records, entry.record())
+			batchCount++
+			batchHash += dfiID(entry.RoutingNumber)
+			if entry.TransactionCode.IsDebit() {
+				batchDebits += entry.Amount
+			} else {
+				batchCredits += entry.Amount
+			}
+
+			for j, addenda := range entry.Addenda {
+				records = append(records, addenda.record(j+1, entry.TraceNumber))
+				batchCount++
+			}
+		}
+		records = append(records, batch.controlRecord(batchCount, batchHash, batchDebits, batchCredits))
+
+		entryCount += batchCount
+		entryHash += batchHash
+		totalDebits += batchDebits
+		totalCredits += batchCredits
+	}
+
+	// The block count includes the file control record.
+	blocks := (len(records) + 1 + blockingFactor - 1) / blockingFactor
+	records = append(records, "9"+
+		numeric(int64(len(f.Batches)), 6)+
+		numeric(int64(blocks), 6)+
+		numeric(entryCount, 8)+
+		numeric(entryHash, 10)+
+		numeric(totalDebits, 12)+
+		numeric(totalCredits, 12)+
+		strings.Repeat(" ", 39))
+	for len(records)%blockingFactor != 0 {
+		records = append(records, strings.Repeat("9", recordLength))
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, record := range records {
+		if len(record) != recordLength {
+			return fmt.Errorf("ach: internal error: record %q is %d characters", record, len(record))
+		}
+		if _, err := bw.WriteString(record + "\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// prepare validates the file and fills in derived fields.
+func (f *File) prepare() error {
+	h := &f.Header
+	if err := plaid.ValidateRoutingNumber(h.ImmediateDestination); err != nil {
+		return fmt.Errorf("ach: immediate destination: %w", err)
+	}
+	if h.ImmediateOrigin == "" || len(h.ImmediateOrigin) > 10 {
+		return fmt.Errorf("ach: immediate origin %q must be 1 to 10 characters", h.ImmediateOrigin)
+	}
+	if err := validateAlpha(
+		"immediate origin", h.ImmediateOrigin,
+		"immediate destination name", h.ImmediateDestinationName,
+		"immediate origin name", h.ImmediateOriginName,
+		"reference code", h.ReferenceCode,
+	); err != nil {
+		return fmt.Errorf("ach: %w", err)
+	}
+	if h.CreationTime.IsZero() {
+		h.CreationTime = time.Now()
+	}
+	if h.FileIDModifier == 0 {
+		h.FileIDModifier = 'A'
+	}
+	if !(h.FileIDModifier >= 'A' && h.FileIDModifier <= 'Z') && !(h.FileIDModifier >= '0' && h.FileIDModifier <= '9') {
+		return fmt.Errorf("ach: file ID modifier %q must be an upper case letter or digit", h.FileIDModifier)
+	}
+	if len(f.Batches) == 0 {
+		return fmt.Errorf("ach: file has no batches")
+	}
+
+	trace := int64(0)
+	for i := range f.Batches {
+		batch := &f.Batches[i]
+		batch.Header.BatchNumber = i + 1
+		if err := batch.prepare(&trace); err != nil {
+			return fmt.Errorf("ach: batch %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (b *Batch) prepare(trace *int64) error {
+	h := &b.Header
+	if h.StandardEntryClass != StandardEntryClasses.PPD && h.StandardEntryClass != StandardEntryClasses.CCD {
+		return fmt.Errorf("unsupported standard entry class %q", h.StandardEntryClass)
+	}
+	if h.CompanyName == "" || h.CompanyIdentification == "" || h.CompanyEntryDescription == "" {
+		return fmt.Errorf("company name, identification and entry description must be specified")
+	}
+	if err := validateAlpha(
+		"company name", h.CompanyName,
+		"company discretionary data", h.CompanyDiscretionaryData,
+		"company identification", h.CompanyIdentification,
+		"company entry description", h.CompanyEntryDescription,
+		"company descriptive date", h.CompanyDescriptiveDate,
+	); err != nil {
+		return err
+	}
+	// Parsed files only carry the first eight digits of the ODFI routing
+	// number, so accept those as well as a full routing number.
+	if len(h.OriginatingDFI) != 8 || !isDigits(h.OriginatingDFI) {
+		if err := plaid.ValidateRoutingNumber(h.OriginatingDFI); err != nil {
+			return fmt.Errorf("originating DFI: %w", err)
+		}
+	}
+	if h.EffectiveEntryDate.IsZero() {
+		return fmt.Errorf("effective entry date must be specified")
+	}
+	if len(b.Entries) == 0 {
+		return fmt.Errorf("batch has no entries")
+	}
+
+	var credits, debits bool
+	for i := range b.Entries {
+		entry := &b.Entries[i]
+		if err := entry.validate(); err != nil {
+			return fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		credits = credits || entry.TransactionCode.IsCredit()
+		debits = debits || entry.TransactionCode.IsDebit()
+
+		if entry.TraceNumber == "" {
+			*trace++
+			entry.TraceNumber = numeric(dfiID(h.OriginatingDFI), 8) + numeric(*trace, 7)
+		}
+	}
+
+	derived := ServiceClassCodes.Mixed
+	switch {
+	case credits && !debits:
+		derived = ServiceClassCodes.Credits
+	case debits && !credits:
+		derived = ServiceClassCodes.Debits
+	}
+	if h.ServiceClassCode == 0 {
+		h.ServiceClassCode = derived
+	} else if h.ServiceClassCode != ServiceClassCodes.Mixed && h.ServiceClassCode != derived {
+		return fmt.Errorf("service class code %d does not match the batch's entries", h.ServiceClassCode)
+	}
+	return nil
+}
+
+func (e *Entry) validate() error {
+	if err := plaid.ValidateRoutingNumber(e.RoutingNumber); err != nil {
+		return err
+	}
+	if err := plaid.ValidateACHAccountNumber(e.AccountNumber); err != nil {
+		return err
+	}
+	if !e.TransactionCode.IsCredit() && !e.TransactionCode.IsDebit() {
+		return fmt.Errorf("unsupported transaction code %d", e.TransactionCode)
+	}
+	if e.TransactionCode.IsPrenote() && e.Amount != 0 {
+		return fmt.Errorf("prenote entries must have a zero amount")
+	}
+	if e.Amount < 0 || e.Amount > 99999999_99 {
+		return fmt.Errorf("amount %d is out of range", e.Amount)
+	}
+	if e.IndividualName == "" {
+		return fmt.Errorf("individual name must be specified")
+	}
+	if err := validateAlpha(
+		"individual ID", e.IndividualID,
+		"individual name", e.IndividualName,
+		"discretionary data", e.DiscretionaryData,
+	); err != nil {
+		return err
+	}
+	if len(e.Addenda) > 1 {
+		return fmt.Errorf("PPD and CCD entries allow at most one addenda record")
+	}
+	for _, addenda := range e.Addenda {
+		if err := validateAlpha("payment related information", addenda.PaymentRelatedInformation); err != nil {
+			return err
+		}
+	}
+	if e.TraceNumber != "" && (len(e.TraceNumber) != 15 || !isDigits(e.TraceNumber)) {
+		return fmt.Errorf("trace number %q must be 15 digits", e.TraceNumber)
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func (f *File) headerRecord() string {
+	h := f.Header
+	return "1" +
+		"01" +
+		rightJustify(h.ImmediateDestination, 10) +
+		rightJustify(h.ImmediateOrigin, 10) +
+		h.CreationTime.Format("060102") +
+		h.CreationTime.Format("1504") +
+		string(h.FileIDModifier) +
+		"094" +
+		numeric(blockingFactor, 2) +
+		"1" +
+		alpha(h.ImmediateDestinationName, 23) +
+		alpha(h.ImmediateOriginName, 23) +
+		alpha(h.ReferenceCode, 8)
+}
+
+func (b *Batch) headerRecord() string {
+	h := b.Header
+	return "5" +
+		numeric(int64(h.ServiceClassCode), 3) +
+		alpha(h.CompanyName, 16) +
+		alpha(h.CompanyDiscretionaryData, 20) +
+		alpha(h.CompanyIdentification, 10) +
+		string(h.StandardEntryClass) +
+		alpha(h.CompanyEntryDescription, 10) +
+		alpha(h.CompanyDescriptiveDate, 6) +
+		h.EffectiveEntryDate.Format("060102") +
+		"   " + // settlement date, filled in by the ACH operator
+		"1" +
+		numeric(dfiID(h.OriginatingDFI), 8) +
+		numeric(int64(h.BatchNumber), 7)
+}
+
+func (b *Batch) controlRecord(count, hash, debits, credits int64) string {
+	h := b.Header
+	return "8" +
+		numeric(int64(h.ServiceClassCode), 3) +
+		numeric(count, 6) +
+		numeric(hash, 10) +
+		numeric(debits, 12) +
+		numeric(credits, 12) +
+		alpha(h.CompanyIdentification, 10) +
+		strings.Repeat(" ", 19) + // message authentication code
+		strings.Repeat(" ", 6) +
+		numeric(dfiID(h.OriginatingDFI), 8) +
+		numeric(int64(h.BatchNumber), 7)
+}
+
+func (e Entry) record() string {
+	addendaIndicator := "0"
+	if len(e.Addenda) > 0 {
+		addendaIndicator = "1"
+	}
+	return "6" +
+		numeric(int64(e.TransactionCode), 2) +
+		e.RoutingNumber +
+		alpha(e.AccountNumber, 17) +
+		numeric(e.Amount, 10) +
+		alpha(e.IndividualID, 15) +
+		alpha(e.IndividualName, 22) +
+		alpha(e.DiscretionaryData, 2) +
+		addendaIndicator +
+		e.TraceNumber
+}
+
+func (a Addenda) record(sequence int, traceNumber string) string {
+	return "7" +
+		"05" +
+		alpha(a.PaymentRelatedInformation, 80) +
+		numeric(int64(sequence), 4) +
+		traceNumber[8:]
+}
@@ -17,9 +17,9 @@ var (
 )
 
 var testOptions = ClientOptions{
-	testClientID,
-	testSecret,
-	testEnv,
-	&http.Client{},
+	ClientID:    testClientID,
+	Secret:      testSecret,
+	Environment: testEnv,
+	HTTPClient:  &http.Client{},
 }
 var testClient, _ = NewClient(testOptions)
@@ -0,0 +1,154 @@
+package plaid
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// APIVersions lists the versions of the Plaid API the client supports, oldest
+// first. Responses from older versions are decoded into the same types as
+// APIVersion responses; see WithAPIVersion.
+var APIVersions = []string{"2018-05-22", "2019-05-29", APIVersion}
+
+// APIVersionRange is the range of API versions an endpoint is valid under.
+// An empty bound is open.
+type APIVersionRange struct {
+	// Since is the first version the endpoint is valid under.
+	Since string
+	// Until is the last version the endpoint is valid under.
+	Until string
+}
+
+// Contains reports whether version falls within the range. API versions are
+// dates, so they compare as strings.
+func (r APIVersionRange) Contains(version string) bool {
+	return (r.Since == "" || version >= r.Since) && (r.Until == "" || version <= r.Until)
+}
+
+// EndpointAPIVersions is the compatibility table for endpoints that are not
+// valid under every supported API version. Endpoints that are not listed are
+// valid under all of them. Calls to an endpoint under a version it is not
+// valid under fail with an UnsupportedAPIVersionError before they are sent.
+// Every endpoint the client calls is currently valid under all supported
+// versions.
+var EndpointAPIVersions = map[string]APIVersionRange{}
+
+// UnsupportedAPIVersionError is returned for a call made under an API version
+// the client does not support, or that the endpoint is not valid under.
+type UnsupportedAPIVersionError struct {
+	Endpoint   string
+	APIVersion string
+}
+
+func (e UnsupportedAPIVersionError) Error() string {
+	if !isSupportedAPIVersion(e.APIVersion) {
+		return fmt.Sprintf("%s - unsupported API version %q, must be one of %s", e.Endpoint, e.APIVersion, strings.Join(APIVersions, ", "))
+	}
+	return fmt.Sprintf("%s - not available under API version %s", e.Endpoint, e.APIVersion)
+}
+
+// EndpointSupportsAPIVersion reports whether an endpoint can be called under
+// an API version.
+func EndpointSupportsAPIVersion(endpoint string, version string) bool {
+	if !isSupportedAPIVersion(version) {
+		return false
+	}
+	r, ok := EndpointAPIVersions[endpoint]
+	return !ok || r.Contains(version)
+}
+
+func isSupportedAPIVersion(version string) bool {
+	for _, v := range APIVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// WithAPIVersion returns a copy of the client that makes its calls under
+// another API version, for example to move a service onto a new version one
+// call at a time:
+//
+//	resp, err := client.WithAPIVersion("2019-05-29").GetIdentity(accessToken)
+//
+// The copy shares the client's HTTP client and idempotency store.
+func (c *Client) WithAPIVersion(version string) *Client {
+	clone := *c
+	clone.apiVersion = version
+	return &clone
+}
+
+// APIVersion returns the version of the Plaid API the client calls.
+func (c *Client) APIVersion() string {
+	return c.apiVersion
+}
+
+// legacyResponses rewrites the responses of older API versions, keyed by
+// version and endpoint, into the shape of the APIVersion response before they
+// are decoded.
+var legacyResponses = map[string]map[string]func(interface{}){
+	"2018-05-22": {
+		"/asset_report/get": renameAddressFields,
+		"/identity/get":     renameAddressFields,
+	},
+}
+
+// renameAddressFields renames the state and zip fields of the owner addresses
+// in a response to region and postal_code, as they were renamed in
+// 2019-05-29.
+func renameAddressFields(v interface{}) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, e := range v {
+			renameAddressFields(e)
+		}
+	case map[string]interface{}:
+		if addresses, ok := v["addresses"].([]interface{}); ok {
+			for _, address := range addresses {
+				address, ok := address.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if data, ok := address["data"].(map[string]interface{}); ok {
+					renameField(data, "state", "region")
+					renameField(data, "zip", "postal_code")
+				}
+			}
+		}
+		for _, e := range v {
+			renameAddressFields(e)
+		}
+	}
+}
+
+func renameField(m map[string]interface{}, from, to string) {
+	if value, ok := m[from]; ok {
+		if _, exists := m[to]; !exists {
+			m[to] = value
+		}
+		delete(m, from)
+	}
+}
+
+// decodeResponse decodes a successful response into v, first rewriting it
+// into the current shape if it was made under an older API version.
+func decodeResponse(r io.Reader, version string, endpoint string, v interface{}) error {
+	legacy, ok := legacyResponses[version][endpoint]
+	if !ok {
+		return json.NewDecoder(r).Decode(v)
+	}
+
+	var raw interface{}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return err
+	}
+	legacy(raw)
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
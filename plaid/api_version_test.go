@@ -0,0 +1,103 @@
+package plaid
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestNewClientAPIVersion(t *testing.T) {
+	client, err := NewClient(ClientOptions{Environment: Sandbox})
+	assert.Nil(t, err)
+	assert.Equal(t, APIVersion, client.APIVersion())
+
+	client, err = NewClient(ClientOptions{Environment: Sandbox, APIVersion: "2019-05-29"})
+	assert.Nil(t, err)
+	assert.Equal(t, "2019-05-29", client.APIVersion())
+
+	_, err = NewClient(ClientOptions{Environment: Sandbox, APIVersion: "2017-03-08"})
+	assert.NotNil(t, err)
+}
+
+func TestWithAPIVersion(t *testing.T) {
+	server, client := newFakeServer(t)
+	server.handle("/item/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, GetItemResponse{}
+	})
+
+	_, err := client.WithAPIVersion("2019-05-29").GetItem("access-sandbox-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "2019-05-29", server.apiVersion("/item/get"))
+
+	// The override does not change the client it was made from.
+	_, err = client.GetItem("access-sandbox-1")
+	assert.Nil(t, err)
+	assert.Equal(t, APIVersion, server.apiVersion("/item/get"))
+
+	_, err = client.WithAPIVersion("2017-03-08").GetItem("access-sandbox-1")
+	var unsupported UnsupportedAPIVersionError
+	assert.True(t, errors.As(err, &unsupported))
+	assert.Equal(t, "/item/get", unsupported.Endpoint)
+	assert.Equal(t, 2, server.callCount("/item/get"))
+}
+
+func TestEndpointAPIVersions(t *testing.T) {
+	EndpointAPIVersions["/item/get"] = APIVersionRange{Since: "2019-05-29"}
+	t.Cleanup(func() { delete(EndpointAPIVersions, "/item/get") })
+
+	server, client := newFakeServer(t)
+	server.handle("/item/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, GetItemResponse{}
+	})
+
+	_, err := client.WithAPIVersion("2018-05-22").GetItem("access-sandbox-1")
+	assert.Equal(t, UnsupportedAPIVersionError{Endpoint: "/item/get", APIVersion: "2018-05-22"}, err)
+	assert.Equal(t, 0, server.callCount("/item/get"))
+
+	_, err = client.GetItem("access-sandbox-1")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, server.callCount("/item/get"))
+
+	assert.True(t, EndpointSupportsAPIVersion("/item/get", "2019-05-29"))
+	assert.False(t, EndpointSupportsAPIVersion("/item/get", "2018-05-22"))
+	assert.True(t, EndpointSupportsAPIVersion("/auth/get", "2018-05-22"))
+	assert.False(t, EndpointSupportsAPIVersion("/auth/get", "2017-03-08"))
+}
+
+func TestLegacyIdentityResponse(t *testing.T) {
+	server, client := newFakeServer(t)
+	server.handle("/identity/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"accounts": []interface{}{map[string]interface{}{
+				"account_id": "BxBXxLj1m4HMXBm9WZZmCWVbPjX16EHwv99vp",
+				"owners": []interface{}{map[string]interface{}{
+					"addresses": []interface{}{map[string]interface{}{
+						"data": map[string]interface{}{
+							"city":   "Malakoff",
+							"state":  "NY",
+							"street": "2992 Cameron Road",
+							"zip":    "14236",
+						},
+						"primary": true,
+					}},
+				}},
+			}},
+			"request_id": "3nARps6TOYtbACO",
+		}
+	})
+
+	resp, err := client.WithAPIVersion("2018-05-22").GetIdentity("access-sandbox-1")
+	assert.Nil(t, err)
+	address := resp.Accounts[0].Owners[0].Addresses[0].Data
+	assert.Equal(t, "NY", address.Region)
+	assert.Equal(t, "14236", address.PostalCode)
+	assert.Equal(t, "Malakoff", address.City)
+	assert.Equal(t, "3nARps6TOYtbACO", resp.RequestID)
+
+	// Responses under the current version are decoded as they are.
+	resp, err = client.GetIdentity("access-sandbox-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "", resp.Accounts[0].Owners[0].Addresses[0].Data.Region)
+}
@@ -0,0 +1,91 @@
+package plaid
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// AssetReportWaiterOptions configures an AssetReportWaiter.
+type AssetReportWaiterOptions struct {
+	// PollInterval is how often GetAssetReport is retried while no webhook
+	// has arrived. It defaults to 30 seconds.
+	PollInterval time.Duration
+	// IncludeInsights is passed on to GetAssetReportWithOptions.
+	IncludeInsights bool
+}
+
+// AssetReportWaiter waits for Asset Reports to finish generating. Reports are
+// fetched as soon as an ASSETS webhook is passed to HandleWebhook, and polled
+// in case the webhook never arrives.
+type AssetReportWaiter struct {
+	client  *Client
+	options AssetReportWaiterOptions
+
+	mu   sync.Mutex
+	wake chan struct{}
+}
+
+// NewAssetReportWaiter creates an AssetReportWaiter.
+func NewAssetReportWaiter(client *Client, options AssetReportWaiterOptions) *AssetReportWaiter {
+	if options.PollInterval <= 0 {
+		options.PollInterval = 30 * time.Second
+	}
+	return &AssetReportWaiter{
+		client:  client,
+		options: options,
+		wake:    make(chan struct{}),
+	}
+}
+
+// HandleWebhook wakes every pending WaitForAssetReport call when an ASSETS
+// PRODUCT_READY or ERROR webhook arrives, and reports whether it did. Asset
+// webhooks identify reports by ID rather than token, so each waiter checks
+// its own report.
+func (w *AssetReportWaiter) HandleWebhook(webhook Webhook) bool {
+	if webhook.WebhookType != "ASSETS" || (webhook.WebhookCode != "PRODUCT_READY" && webhook.WebhookCode != "ERROR") {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	close(w.wake)
+	w.wake = make(chan struct{})
+	return true
+}
+
+func (w *AssetReportWaiter) wakeChannel() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.wake
+}
+
+// WaitForAssetReport blocks until the Asset Report is ready and returns it.
+// It returns early if ctx is done or GetAssetReport fails with an error other
+// than PRODUCT_NOT_READY, such as when the report could not be generated.
+func (w *AssetReportWaiter) WaitForAssetReport(ctx context.Context, assetReportToken string) (GetAssetReportResponse, error) {
+	ticker := time.NewTicker(w.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Take the wake channel before checking the report so that a webhook
+		// arriving during the call is not missed.
+		wake := w.wakeChannel()
+
+		resp, err := w.client.GetAssetReportWithOptions(assetReportToken, GetAssetReportOptions{
+			IncludeInsights: w.options.IncludeInsights,
+		})
+		var plaidErr Error
+		if err == nil || !errors.As(err, &plaidErr) || plaidErr.ErrorCode != "PRODUCT_NOT_READY" {
+			return resp, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return resp, ctx.Err()
+		case <-wake:
+		case <-ticker.C:
+		}
+	}
+}
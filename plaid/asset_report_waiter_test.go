@@ -0,0 +1,85 @@
+package plaid
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+var errProductNotReady = Error{
+	ErrorType:    "ASSET_REPORT_ERROR",
+	ErrorCode:    "PRODUCT_NOT_READY",
+	ErrorMessage: "the requested product is not yet ready",
+}
+
+// handleAssetReportReadyAfter makes /asset_report/get fail with
+// PRODUCT_NOT_READY until ready returns true.
+func handleAssetReportReadyAfter(server *fakeServer, ready func() bool) {
+	server.handle("/asset_report/get", func(body map[string]interface{}) (int, interface{}) {
+		if !ready() {
+			return http.StatusBadRequest, errProductNotReady
+		}
+		return http.StatusOK, GetAssetReportResponse{Report: AssetReport{AssetReportID: "report-1"}}
+	})
+}
+
+func TestWaitForAssetReportPolls(t *testing.T) {
+	server, client := newFakeServer(t)
+	var calls int32
+	handleAssetReportReadyAfter(server, func() bool { return atomic.AddInt32(&calls, 1) >= 3 })
+
+	waiter := NewAssetReportWaiter(client, AssetReportWaiterOptions{PollInterval: time.Millisecond})
+	resp, err := waiter.WaitForAssetReport(context.Background(), "assets-token")
+	assert.Nil(t, err)
+	assert.Equal(t, "report-1", resp.Report.AssetReportID)
+	assert.Equal(t, 3, server.callCount("/asset_report/get"))
+}
+
+func TestWaitForAssetReportWebhook(t *testing.T) {
+	server, client := newFakeServer(t)
+	var ready int32
+	handleAssetReportReadyAfter(server, func() bool { return atomic.LoadInt32(&ready) == 1 })
+
+	waiter := NewAssetReportWaiter(client, AssetReportWaiterOptions{PollInterval: time.Hour})
+	var handled []bool
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for server.callCount("/asset_report/get") == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		handled = append(handled, waiter.HandleWebhook(Webhook{WebhookType: "TRANSACTIONS", WebhookCode: "DEFAULT_UPDATE"}))
+		atomic.StoreInt32(&ready, 1)
+		handled = append(handled, waiter.HandleWebhook(Webhook{WebhookType: "ASSETS", WebhookCode: "PRODUCT_READY", AssetReportID: "report-1"}))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := waiter.WaitForAssetReport(ctx, "assets-token")
+	assert.Nil(t, err)
+	assert.Equal(t, "report-1", resp.Report.AssetReportID)
+	assert.Equal(t, 2, server.callCount("/asset_report/get"))
+	<-done
+	assert.Equal(t, []bool{false, true}, handled)
+}
+
+func TestWaitForAssetReportStops(t *testing.T) {
+	server, client := newFakeServer(t)
+	handleAssetReportReadyAfter(server, func() bool { return false })
+	waiter := NewAssetReportWaiter(client, AssetReportWaiterOptions{PollInterval: time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := waiter.WaitForAssetReport(ctx, "assets-token")
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	server.handle("/asset_report/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusBadRequest, Error{ErrorType: "ASSET_REPORT_ERROR", ErrorCode: "ASSET_REPORT_GENERATION_FAILED"}
+	})
+	_, err = waiter.WaitForAssetReport(context.Background(), "assets-token")
+	assert.Equal(t, "ASSET_REPORT_GENERATION_FAILED", err.(Error).ErrorCode)
+}
@@ -3,6 +3,7 @@ package plaid
 import (
 	"encoding/json"
 	"errors"
+	"io"
 )
 
 type AssetReport struct {
@@ -56,7 +57,7 @@ type AssetReportAccountOwnerPhoneNumber struct {
 
 type AssetReportAccount struct {
 	Balances           AccountBalances           `json:"balances"`
-	HistoricalBalances []AccountBalances         `json:"historical_balances"`
+	HistoricalBalances []HistoricalBalance       `json:"historical_balances"`
 	AccountID          string                    `json:"account_id"`
 	DaysAvailable      int                       `json:"days_available"`
 	Mask               string                    `json:"mask"`
@@ -65,6 +66,34 @@ type AssetReportAccount struct {
 	Owners             []AssetReportAccountOwner `json:"owners"`
 	Subtype            string                    `json:"subtype"`
 	Type               string                    `json:"type"`
+	Transactions       []AssetReportTransaction  `json:"transactions"`
+}
+
+// HistoricalBalance is the current balance of an account at the end of a day.
+type HistoricalBalance struct {
+	Current                float64  `json:"current"`
+	Date                   string   `json:"date"`
+	ISOCurrencyCode        Currency `json:"iso_currency_code"`
+	UnofficialCurrencyCode Currency `json:"unofficial_currency_code"`
+}
+
+// AssetReportTransaction is a transaction in an Asset Report. The category,
+// merchant and location fields are only populated when the report is
+// retrieved with IncludeInsights.
+type AssetReportTransaction struct {
+	AccountID              string   `json:"account_id"`
+	Amount                 float64  `json:"amount"`
+	ISOCurrencyCode        Currency `json:"iso_currency_code"`
+	UnofficialCurrencyCode Currency `json:"unofficial_currency_code"`
+	Date                   string   `json:"date"`
+	OriginalDescription    string   `json:"original_description"`
+	Pending                bool     `json:"pending"`
+	ID                     string   `json:"transaction_id"`
+	Name                   string   `json:"name"`
+	MerchantName           string   `json:"merchant_name"`
+	Category               []string `json:"category"`
+	CategoryID             string   `json:"category_id"`
+	Location               Location `json:"location"`
 }
 
 type AssetReportUser struct {
@@ -81,6 +110,13 @@ type getAssetReportRequest struct {
 	ClientID         string `json:"client_id"`
 	Secret           string `json:"secret"`
 	AssetReportToken string `json:"asset_report_token"`
+	IncludeInsights  bool   `json:"include_insights,omitempty"`
+}
+
+type GetAssetReportOptions struct {
+	// IncludeInsights adds transaction categories, merchant names and
+	// locations to the report.
+	IncludeInsights bool
 }
 
 type GetAssetReportResponse struct {
@@ -98,17 +134,21 @@ type createAssetReportRequest struct {
 }
 
 type CreateAssetReportOptions struct {
-	ClientReportID string `json:"client_report_id,omitempty"`
-	Webhook        string `json:"webhook,omitempty"`
-	User           struct {
-		ClientUserID string `json:"client_user_id,omitempty"`
-		FirstName    string `json:"first_name,omitempty"`
-		LastName     string `json:"last_name,omitempty"`
-		MiddleName   string `json:"middle_name,omitempty"`
-		Ssn          string `json:"ssn,omitempty"`
-		PhoneNumber  string `json:"phone_number,omitempty"`
-		Email        string `json:"email,omitempty"`
-	} `json:"user,omitempty"`
+	ClientReportID string                 `json:"client_report_id,omitempty"`
+	Webhook        string                 `json:"webhook,omitempty"`
+	User           *CreateAssetReportUser `json:"user,omitempty"`
+}
+
+// CreateAssetReportUser holds information about the user an Asset Report is
+// created for, which is included in the report.
+type CreateAssetReportUser struct {
+	ClientUserID string `json:"client_user_id,omitempty"`
+	FirstName    string `json:"first_name,omitempty"`
+	LastName     string `json:"last_name,omitempty"`
+	MiddleName   string `json:"middle_name,omitempty"`
+	Ssn          string `json:"ssn,omitempty"`
+	PhoneNumber  string `json:"phone_number,omitempty"`
+	Email        string `json:"email,omitempty"`
 }
 
 type CreateAssetReportResponse struct {
@@ -117,6 +157,33 @@ type CreateAssetReportResponse struct {
 	RequestID        string `json:"request_id"`
 }
 
+type refreshAssetReportRequest struct {
+	ClientID         string                    `json:"client_id"`
+	Secret           string                    `json:"secret"`
+	AssetReportToken string                    `json:"asset_report_token"`
+	DaysRequested    int                       `json:"days_requested,omitempty"`
+	Options          *CreateAssetReportOptions `json:"options,omitempty"`
+}
+
+type RefreshAssetReportResponse struct {
+	APIResponse
+	AssetReportToken string `json:"asset_report_token"`
+	AssetReportID    string `json:"asset_report_id"`
+}
+
+type filterAssetReportRequest struct {
+	ClientID            string   `json:"client_id"`
+	Secret              string   `json:"secret"`
+	AssetReportToken    string   `json:"asset_report_token"`
+	AccountIDsToExclude []string `json:"account_ids_to_exclude"`
+}
+
+type FilterAssetReportResponse struct {
+	APIResponse
+	AssetReportToken string `json:"asset_report_token"`
+	AssetReportID    string `json:"asset_report_id"`
+}
+
 type removeAssetReportRequest struct {
 	ClientID         string `json:"client_id"`
 	Secret           string `json:"secret"`
@@ -128,6 +195,23 @@ type RemoveAssetReportResponse struct {
 	Removed bool `json:"removed"`
 }
 
+type getAssetReportPDFRequest struct {
+	ClientID         string `json:"client_id"`
+	Secret           string `json:"secret"`
+	AssetReportToken string `json:"asset_report_token"`
+}
+
+type auditCopyRequest struct {
+	ClientID       string `json:"client_id"`
+	Secret         string `json:"secret"`
+	AuditCopyToken string `json:"audit_copy_token"`
+}
+
+type RemoveAuditCopyResponse struct {
+	APIResponse
+	Removed bool `json:"removed"`
+}
+
 type createAuditCopyRequest struct {
 	ClientID         string `json:"client_id"`
 	Secret           string `json:"secret"`
@@ -141,6 +225,10 @@ type CreateAuditCopyTokenResponse struct {
 }
 
 func (c *Client) GetAssetReport(assetReportToken string) (resp GetAssetReportResponse, err error) {
+	return c.GetAssetReportWithOptions(assetReportToken, GetAssetReportOptions{})
+}
+
+func (c *Client) GetAssetReportWithOptions(assetReportToken string, options GetAssetReportOptions) (resp GetAssetReportResponse, err error) {
 	if assetReportToken == "" {
 		return resp, errors.New("/asset_report/get - asset report token must be specified")
 	}
@@ -149,6 +237,7 @@ func (c *Client) GetAssetReport(assetReportToken string) (resp GetAssetReportRes
 		ClientID:         c.clientID,
 		Secret:           c.secret,
 		AssetReportToken: assetReportToken,
+		IncludeInsights:  options.IncludeInsights,
 	})
 
 	if err != nil {
@@ -159,6 +248,28 @@ func (c *Client) GetAssetReport(assetReportToken string) (resp GetAssetReportRes
 	return resp, err
 }
 
+// GetAssetReportPDF writes the PDF version of an Asset Report to w and returns
+// the number of bytes written. The PDF is streamed rather than buffered, so
+// large reports can be written straight to a file or HTTP response.
+// See https://plaid.com/docs/api/products/assets/#asset_reportpdfget.
+func (c *Client) GetAssetReportPDF(assetReportToken string, w io.Writer) (int64, error) {
+	if assetReportToken == "" {
+		return 0, errors.New("/asset_report/pdf/get - asset report token must be specified")
+	}
+
+	jsonBody, err := json.Marshal(getAssetReportPDFRequest{
+		ClientID:         c.clientID,
+		Secret:           c.secret,
+		AssetReportToken: assetReportToken,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return c.CallRaw("/asset_report/pdf/get", jsonBody, w)
+}
+
 func (c *Client) CreateAssetReportWithOptions(itemAccessTokens []string, daysRequested int, options CreateAssetReportOptions) (resp CreateAssetReportResponse, err error) {
 	if itemAccessTokens == nil || len(itemAccessTokens) == 0 {
 		return resp, errors.New("/asset_report/create - asset report token must be specified")
@@ -238,3 +349,104 @@ func (c *Client) RemoveAssetReport(assetReportToken string) (resp RemoveAssetRep
 	err = c.Call("/asset_report/remove", jsonBody, &resp)
 	return resp, err
 }
+
+// RefreshAssetReport creates a new Asset Report from the same Items and
+// options as an existing one, with up to date data. A daysRequested of zero
+// keeps the original report's value.
+// See https://plaid.com/docs/api/products/assets/#asset_reportrefresh.
+func (c *Client) RefreshAssetReport(assetReportToken string, daysRequested int) (resp RefreshAssetReportResponse, err error) {
+	return c.RefreshAssetReportWithOptions(assetReportToken, daysRequested, CreateAssetReportOptions{})
+}
+
+// RefreshAssetReportWithOptions is like RefreshAssetReport, overriding the
+// options of the original report with any that are set.
+func (c *Client) RefreshAssetReportWithOptions(assetReportToken string, daysRequested int, options CreateAssetReportOptions) (resp RefreshAssetReportResponse, err error) {
+	if assetReportToken == "" {
+		return resp, errors.New("/asset_report/refresh - asset report token must be specified")
+	}
+
+	req := refreshAssetReportRequest{
+		ClientID:         c.clientID,
+		Secret:           c.secret,
+		AssetReportToken: assetReportToken,
+		DaysRequested:    daysRequested,
+	}
+	if options != (CreateAssetReportOptions{}) {
+		req.Options = &options
+	}
+
+	jsonBody, err := json.Marshal(req)
+
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("/asset_report/refresh", jsonBody, &resp)
+	return resp, err
+}
+
+// FilterAssetReport creates a new Asset Report from an existing one, without
+// the given accounts.
+// See https://plaid.com/docs/api/products/assets/#asset_reportfilter.
+func (c *Client) FilterAssetReport(assetReportToken string, accountIDsToExclude []string) (resp FilterAssetReportResponse, err error) {
+	if assetReportToken == "" || len(accountIDsToExclude) == 0 {
+		return resp, errors.New("/asset_report/filter - asset report token and account ids to exclude must be specified")
+	}
+
+	jsonBody, err := json.Marshal(filterAssetReportRequest{
+		ClientID:            c.clientID,
+		Secret:              c.secret,
+		AssetReportToken:    assetReportToken,
+		AccountIDsToExclude: accountIDsToExclude,
+	})
+
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("/asset_report/filter", jsonBody, &resp)
+	return resp, err
+}
+
+// GetAuditCopy retrieves the Asset Report an audit copy token was created for.
+// It is called by auditors such as Fannie Mae.
+// See https://plaid.com/docs/api/products/assets/#asset_reportaudit_copyget.
+func (c *Client) GetAuditCopy(auditCopyToken string) (resp GetAssetReportResponse, err error) {
+	if auditCopyToken == "" {
+		return resp, errors.New("/asset_report/audit_copy/get - audit copy token must be specified")
+	}
+
+	jsonBody, err := json.Marshal(auditCopyRequest{
+		ClientID:       c.clientID,
+		Secret:         c.secret,
+		AuditCopyToken: auditCopyToken,
+	})
+
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("/asset_report/audit_copy/get", jsonBody, &resp)
+	return resp, err
+}
+
+// RemoveAuditCopy revokes an audit copy token.
+// See https://plaid.com/docs/api/products/assets/#asset_reportaudit_copyremove.
+func (c *Client) RemoveAuditCopy(auditCopyToken string) (resp RemoveAuditCopyResponse, err error) {
+	if auditCopyToken == "" {
+		return resp, errors.New("/asset_report/audit_copy/remove - audit copy token must be specified")
+	}
+
+	jsonBody, err := json.Marshal(auditCopyRequest{
+		ClientID:       c.clientID,
+		Secret:         c.secret,
+		AuditCopyToken: auditCopyToken,
+	})
+
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("/asset_report/audit_copy/remove", jsonBody, &resp)
+	return resp, err
+}
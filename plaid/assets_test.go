@@ -0,0 +1,115 @@
+package plaid
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestGetAssetReportPDF(t *testing.T) {
+	server, client := newFakeServer(t)
+	pdf := append([]byte("%PDF-1.4\n"), bytes.Repeat([]byte{0xff, 0x00}, 64*1024)...)
+	server.handle("/asset_report/pdf/get", func(body map[string]interface{}) (int, interface{}) {
+		if body["asset_report_token"] != "assets-token" {
+			return http.StatusBadRequest, Error{
+				ErrorType:    "INVALID_INPUT",
+				ErrorCode:    "INVALID_ASSET_REPORT_TOKEN",
+				ErrorMessage: "provided asset report token is invalid",
+			}
+		}
+		return http.StatusOK, pdf
+	})
+
+	var buf bytes.Buffer
+	n, err := client.GetAssetReportPDF("assets-token", &buf)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(pdf)), n)
+	assert.Equal(t, pdf, buf.Bytes())
+
+	buf.Reset()
+	n, err = client.GetAssetReportPDF("bad-token", &buf)
+	assert.Equal(t, int64(0), n)
+	assert.Equal(t, 0, buf.Len())
+	plaidErr, ok := err.(Error)
+	assert.True(t, ok, "%v", err)
+	assert.Equal(t, http.StatusBadRequest, plaidErr.StatusCode)
+	assert.Equal(t, "INVALID_ASSET_REPORT_TOKEN", plaidErr.ErrorCode)
+
+	_, err = client.GetAssetReportPDF("", &buf)
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, server.callCount("/asset_report/pdf/get"))
+}
+
+func TestAssetReportLifecycle(t *testing.T) {
+	server, client := newFakeServer(t)
+	requests := map[string]map[string]interface{}{}
+	record := func(endpoint string, resp interface{}) {
+		server.handle(endpoint, func(body map[string]interface{}) (int, interface{}) {
+			requests[endpoint] = body
+			return http.StatusOK, resp
+		})
+	}
+	record("/asset_report/get", map[string]interface{}{
+		"report": map[string]interface{}{
+			"items": []interface{}{map[string]interface{}{
+				"accounts": []interface{}{map[string]interface{}{
+					"transactions": []interface{}{map[string]interface{}{
+						"transaction_id": "txn-1",
+						"merchant_name":  "Starbucks",
+						"category":       []string{"Food and Drink", "Coffee Shop"},
+					}},
+				}},
+			}},
+		},
+	})
+	record("/asset_report/refresh", RefreshAssetReportResponse{AssetReportToken: "assets-token-2"})
+	record("/asset_report/filter", FilterAssetReportResponse{AssetReportToken: "assets-token-3"})
+	record("/asset_report/audit_copy/get", GetAssetReportResponse{Report: AssetReport{AssetReportID: "report-1"}})
+	record("/asset_report/audit_copy/remove", RemoveAuditCopyResponse{Removed: true})
+
+	report, err := client.GetAssetReportWithOptions("assets-token", GetAssetReportOptions{IncludeInsights: true})
+	assert.Nil(t, err)
+	assert.Equal(t, true, requests["/asset_report/get"]["include_insights"])
+	txn := report.Report.Items[0].Accounts[0].Transactions[0]
+	assert.Equal(t, "Starbucks", txn.MerchantName)
+	assert.Equal(t, []string{"Food and Drink", "Coffee Shop"}, txn.Category)
+
+	_, err = client.GetAssetReport("assets-token")
+	assert.Nil(t, err)
+	assert.NotContains(t, requests["/asset_report/get"], "include_insights")
+
+	refresh, err := client.RefreshAssetReportWithOptions("assets-token", 30, CreateAssetReportOptions{
+		User: &CreateAssetReportUser{ClientUserID: "user-1"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "assets-token-2", refresh.AssetReportToken)
+	assert.Equal(t, float64(30), requests["/asset_report/refresh"]["days_requested"])
+	options := requests["/asset_report/refresh"]["options"].(map[string]interface{})
+	assert.Equal(t, "user-1", options["user"].(map[string]interface{})["client_user_id"])
+
+	_, err = client.RefreshAssetReport("assets-token", 0)
+	assert.Nil(t, err)
+	assert.NotContains(t, requests["/asset_report/refresh"], "days_requested")
+	assert.NotContains(t, requests["/asset_report/refresh"], "options")
+
+	filter, err := client.FilterAssetReport("assets-token", []string{"account-1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "assets-token-3", filter.AssetReportToken)
+	assert.Equal(t, []interface{}{"account-1"}, requests["/asset_report/filter"]["account_ids_to_exclude"])
+	_, err = client.FilterAssetReport("assets-token", nil)
+	assert.NotNil(t, err)
+
+	audit, err := client.GetAuditCopy("audit-token")
+	assert.Nil(t, err)
+	assert.Equal(t, "report-1", audit.Report.AssetReportID)
+	assert.Equal(t, "audit-token", requests["/asset_report/audit_copy/get"]["audit_copy_token"])
+
+	removed, err := client.RemoveAuditCopy("audit-token")
+	assert.Nil(t, err)
+	assert.True(t, removed.Removed)
+
+	_, err = client.RemoveAuditCopy("")
+	assert.NotNil(t, err)
+}
@@ -6,7 +6,7 @@ import (
 )
 
 type getAuthRequestOptions struct {
-	AccountIDs []string `json:"account_ids"`
+	AccountIDs []string `json:"account_ids,omitempty"`
 }
 
 type getAuthRequest struct {
@@ -0,0 +1,446 @@
+package plaid
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BalanceSnapshot is the balance of a single account at a point in time.
+type BalanceSnapshot struct {
+	ItemID    string
+	AccountID string
+	Balances  AccountBalances
+	Time      time.Time
+}
+
+// BalanceStore persists the snapshots taken by a BalanceMonitor.
+type BalanceStore interface {
+	// LatestSnapshots returns the most recent snapshot of each account of
+	// an Item, keyed by account ID.
+	LatestSnapshots(itemID string) (map[string]BalanceSnapshot, error)
+	// SaveSnapshots records a new set of snapshots for an Item. The set
+	// replaces the Item's latest snapshots, so accounts missing from it are
+	// no longer considered current.
+	SaveSnapshots(itemID string, snapshots []BalanceSnapshot) error
+}
+
+// MemoryBalanceStore is a BalanceStore that keeps every snapshot in memory.
+type MemoryBalanceStore struct {
+	mu      sync.Mutex
+	latest  map[string]map[string]BalanceSnapshot
+	history map[string][]BalanceSnapshot
+}
+
+// NewMemoryBalanceStore returns an empty MemoryBalanceStore.
+func NewMemoryBalanceStore() *MemoryBalanceStore {
+	return &MemoryBalanceStore{
+		latest:  map[string]map[string]BalanceSnapshot{},
+		history: map[string][]BalanceSnapshot{},
+	}
+}
+
+// LatestSnapshots implements BalanceStore.
+func (s *MemoryBalanceStore) LatestSnapshots(itemID string) (map[string]BalanceSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latest := make(map[string]BalanceSnapshot, len(s.latest[itemID]))
+	for accountID, snapshot := range s.latest[itemID] {
+		latest[accountID] = snapshot
+	}
+	return latest, nil
+}
+
+// SaveSnapshots implements BalanceStore.
+func (s *MemoryBalanceStore) SaveSnapshots(itemID string, snapshots []BalanceSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latest := make(map[string]BalanceSnapshot, len(snapshots))
+	for _, snapshot := range snapshots {
+		latest[snapshot.AccountID] = snapshot
+		s.history[snapshot.AccountID] = append(s.history[snapshot.AccountID], snapshot)
+	}
+	s.latest[itemID] = latest
+	return nil
+}
+
+// History returns every snapshot recorded for an account, oldest first.
+func (s *MemoryBalanceStore) History(accountID string) []BalanceSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]BalanceSnapshot(nil), s.history[accountID]...)
+}
+
+type BalanceEventType string
+
+const (
+	balanceEventTypeThresholdCrossed   BalanceEventType = "THRESHOLD_CROSSED"
+	balanceEventTypeLargeChange        BalanceEventType = "LARGE_CHANGE"
+	balanceEventTypeLimitChanged       BalanceEventType = "LIMIT_CHANGED"
+	balanceEventTypeAccountDisappeared BalanceEventType = "ACCOUNT_DISAPPEARED"
+)
+
+type balanceEventTypes struct {
+	ThresholdCrossed   BalanceEventType
+	LargeChange        BalanceEventType
+	LimitChanged       BalanceEventType
+	AccountDisappeared BalanceEventType
+}
+
+var BalanceEventTypes balanceEventTypes = balanceEventTypes{
+	ThresholdCrossed:   balanceEventTypeThresholdCrossed,
+	LargeChange:        balanceEventTypeLargeChange,
+	LimitChanged:       balanceEventTypeLimitChanged,
+	AccountDisappeared: balanceEventTypeAccountDisappeared,
+}
+
+// BalanceEvent describes a change detected between two snapshots of an
+// account. Previous is nil for accounts seen for the first time and Current is
+// nil for accounts that disappeared.
+type BalanceEvent struct {
+	Type      BalanceEventType
+	ItemID    string
+	AccountID string
+	Previous  *BalanceSnapshot
+	Current   *BalanceSnapshot
+
+	// Threshold and Below are set on THRESHOLD_CROSSED events. Below is true
+	// when the balance dropped below the threshold and false when it rose
+	// back to or above it.
+	Threshold BalanceThreshold
+	Below     bool
+}
+
+// BalanceThreshold is a balance level that triggers a THRESHOLD_CROSSED event
+// whenever an account's balance crosses it.
+type BalanceThreshold struct {
+	// AccountID restricts the threshold to one account. Leave it empty to
+	// apply the threshold to every account.
+	AccountID string
+	Amount    float64
+	// UseAvailable compares the available balance instead of the current
+	// balance.
+	UseAvailable bool
+}
+
+func (t BalanceThreshold) balance(b AccountBalances) float64 {
+	if t.UseAvailable {
+		return b.Available
+	}
+	return b.Current
+}
+
+// BalanceMonitorOptions configures a BalanceMonitor.
+type BalanceMonitorOptions struct {
+	// PollInterval is how often Run refreshes every watched Item. Leave it
+	// at zero to only refresh in response to webhooks.
+	PollInterval time.Duration
+	// MinRefreshInterval is the minimum time between two
+	// /accounts/balance/get calls for the same Item, to stay within Plaid's
+	// rate limits. Defaults to one minute.
+	MinRefreshInterval time.Duration
+	// AccountIDs restricts monitoring to the given accounts.
+	AccountIDs []string
+
+	Thresholds []BalanceThreshold
+	// LargeChange is the absolute change in current balance that triggers
+	// a LARGE_CHANGE event. Zero disables the event.
+	LargeChange float64
+
+	// OnEvent is called for every event detected. It may be nil if events
+	// are only consumed through the return values of Refresh and
+	// HandleWebhook, but then the events of refreshes HandleWebhook defers
+	// are lost.
+	OnEvent func(BalanceEvent)
+	// OnError is called by Run, and by refreshes HandleWebhook defers, when
+	// refreshing an Item fails.
+	OnError func(itemID string, err error)
+}
+
+// ErrBalanceRefreshThrottled is returned by BalanceMonitor.Refresh when the
+// Item was refreshed less than MinRefreshInterval ago.
+var ErrBalanceRefreshThrottled = errors.New("balance monitor - item refreshed too recently")
+
+// BalanceMonitor polls /accounts/balance/get for a set of Items, persists
+// the results through a BalanceStore and reports changes as BalanceEvents.
+type BalanceMonitor struct {
+	client  *Client
+	store   BalanceStore
+	options BalanceMonitorOptions
+	now     func() time.Time
+	// afterFunc calls f in its own goroutine once d has elapsed.
+	afterFunc func(d time.Duration, f func())
+
+	mu          sync.Mutex
+	items       map[string]string
+	lastRefresh map[string]time.Time
+	deferred    map[string]bool
+}
+
+// NewBalanceMonitor creates a BalanceMonitor.
+func NewBalanceMonitor(client *Client, store BalanceStore, options BalanceMonitorOptions) *BalanceMonitor {
+	if options.MinRefreshInterval == 0 {
+		options.MinRefreshInterval = time.Minute
+	}
+	return &BalanceMonitor{
+		client:      client,
+		store:       store,
+		options:     options,
+		now:         time.Now,
+		afterFunc:   func(d time.Duration, f func()) { time.AfterFunc(d, f) },
+		items:       map[string]string{},
+		lastRefresh: map[string]time.Time{},
+		deferred:    map[string]bool{},
+	}
+}
+
+// Watch adds an Item to the set of monitored Items.
+func (m *BalanceMonitor) Watch(itemID, accessToken string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[itemID] = accessToken
+}
+
+// Unwatch removes an Item from the set of monitored Items.
+func (m *BalanceMonitor) Unwatch(itemID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, itemID)
+	delete(m.lastRefresh, itemID)
+}
+
+// Refresh fetches the current balances of a watched Item, stores them and
+// returns the events detected since the previous snapshot.
+func (m *BalanceMonitor) Refresh(itemID string) ([]BalanceEvent, error) {
+	m.mu.Lock()
+	accessToken, ok := m.items[itemID]
+	if !ok {
+		m.mu.Unlock()
+		return nil, errors.New("balance monitor - item " + itemID + " is not watched")
+	}
+	now := m.now()
+	last, refreshed := m.lastRefresh[itemID]
+	if refreshed && now.Sub(last) < m.options.MinRefreshInterval {
+		m.mu.Unlock()
+		return nil, ErrBalanceRefreshThrottled
+	}
+	// The refresh is recorded before calling Plaid so that concurrent
+	// refreshes are throttled, and undone if the call fails.
+	m.lastRefresh[itemID] = now
+	m.mu.Unlock()
+
+	resp, err := m.client.GetBalancesWithOptions(accessToken, GetBalancesOptions{
+		AccountIDs: m.options.AccountIDs,
+	})
+	if err != nil {
+		m.mu.Lock()
+		if m.lastRefresh[itemID].Equal(now) {
+			if refreshed {
+				m.lastRefresh[itemID] = last
+			} else {
+				delete(m.lastRefresh, itemID)
+			}
+		}
+		m.mu.Unlock()
+		return nil, err
+	}
+
+	previous, err := m.store.LatestSnapshots(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]BalanceSnapshot, 0, len(resp.Accounts))
+	for _, account := range resp.Accounts {
+		snapshots = append(snapshots, BalanceSnapshot{
+			ItemID:    itemID,
+			AccountID: account.AccountID,
+			Balances:  account.Balances,
+			Time:      now,
+		})
+	}
+	if err := m.store.SaveSnapshots(itemID, snapshots); err != nil {
+		return nil, err
+	}
+
+	events := m.compare(itemID, previous, snapshots)
+	if m.options.OnEvent != nil {
+		for _, event := range events {
+			m.options.OnEvent(event)
+		}
+	}
+	return events, nil
+}
+
+// HandleWebhook refreshes the Item a TRANSACTIONS DEFAULT_UPDATE webhook was
+// sent for. Other webhooks and unwatched Items are ignored.
+//
+// If the Item was refreshed less than MinRefreshInterval ago, the refresh is
+// deferred until the interval has elapsed and HandleWebhook returns no events.
+// The deferred refresh reports its events to OnEvent and its failure, if any,
+// to OnError. Webhooks arriving while a refresh is deferred share it.
+func (m *BalanceMonitor) HandleWebhook(webhook Webhook) ([]BalanceEvent, error) {
+	if webhook.WebhookType != "TRANSACTIONS" || webhook.WebhookCode != "DEFAULT_UPDATE" {
+		return nil, nil
+	}
+
+	m.mu.Lock()
+	_, ok := m.items[webhook.ItemID]
+	m.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	events, err := m.Refresh(webhook.ItemID)
+	if err == ErrBalanceRefreshThrottled {
+		m.deferRefresh(webhook.ItemID)
+		return nil, nil
+	}
+	return events, err
+}
+
+// deferRefresh schedules a refresh of an Item for when MinRefreshInterval has
+// elapsed since its last refresh, unless one is already scheduled.
+func (m *BalanceMonitor) deferRefresh(itemID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.deferred[itemID] {
+		return
+	}
+	m.deferred[itemID] = true
+	delay := m.lastRefresh[itemID].Add(m.options.MinRefreshInterval).Sub(m.now())
+	m.afterFunc(delay, func() {
+		m.mu.Lock()
+		delete(m.deferred, itemID)
+		_, ok := m.items[itemID]
+		m.mu.Unlock()
+		if ok {
+			m.refreshAndReport(itemID)
+		}
+	})
+}
+
+// Run refreshes every watched Item each PollInterval until ctx is done.
+// Throttled refreshes are skipped silently; other failures are reported to
+// OnError.
+func (m *BalanceMonitor) Run(ctx context.Context) error {
+	if m.options.PollInterval <= 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ticker := time.NewTicker(m.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		m.refreshAll()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *BalanceMonitor) refreshAll() {
+	m.mu.Lock()
+	itemIDs := make([]string, 0, len(m.items))
+	for itemID := range m.items {
+		itemIDs = append(itemIDs, itemID)
+	}
+	m.mu.Unlock()
+
+	for _, itemID := range itemIDs {
+		m.refreshAndReport(itemID)
+	}
+}
+
+// refreshAndReport refreshes an Item, reporting failures other than
+// throttling to OnError.
+func (m *BalanceMonitor) refreshAndReport(itemID string) {
+	_, err := m.Refresh(itemID)
+	if err != nil && err != ErrBalanceRefreshThrottled && m.options.OnError != nil {
+		m.options.OnError(itemID, err)
+	}
+}
+
+func (m *BalanceMonitor) compare(itemID string, previous map[string]BalanceSnapshot, current []BalanceSnapshot) []BalanceEvent {
+	var events []BalanceEvent
+
+	seen := map[string]bool{}
+	for i := range current {
+		cur := &current[i]
+		seen[cur.AccountID] = true
+
+		prevSnapshot, ok := previous[cur.AccountID]
+		if !ok {
+			continue
+		}
+		prev := &prevSnapshot
+
+		for _, threshold := range m.options.Thresholds {
+			if threshold.AccountID != "" && threshold.AccountID != cur.AccountID {
+				continue
+			}
+			before := threshold.balance(prev.Balances) < threshold.Amount
+			after := threshold.balance(cur.Balances) < threshold.Amount
+			if before != after {
+				events = append(events, BalanceEvent{
+					Type:      BalanceEventTypes.ThresholdCrossed,
+					ItemID:    itemID,
+					AccountID: cur.AccountID,
+					Previous:  prev,
+					Current:   cur,
+					Threshold: threshold,
+					Below:     after,
+				})
+			}
+		}
+
+		if m.options.LargeChange > 0 && math.Abs(cur.Balances.Current-prev.Balances.Current) >= m.options.LargeChange {
+			events = append(events, BalanceEvent{
+				Type:      BalanceEventTypes.LargeChange,
+				ItemID:    itemID,
+				AccountID: cur.AccountID,
+				Previous:  prev,
+				Current:   cur,
+			})
+		}
+
+		if cur.Balances.Limit != prev.Balances.Limit {
+			events = append(events, BalanceEvent{
+				Type:      BalanceEventTypes.LimitChanged,
+				ItemID:    itemID,
+				AccountID: cur.AccountID,
+				Previous:  prev,
+				Current:   cur,
+			})
+		}
+	}
+
+	var disappeared []string
+	for accountID := range previous {
+		if !seen[accountID] {
+			disappeared = append(disappeared, accountID)
+		}
+	}
+	sort.Strings(disappeared)
+	for _, accountID := range disappeared {
+		prev := previous[accountID]
+		events = append(events, BalanceEvent{
+			Type:      BalanceEventTypes.AccountDisappeared,
+			ItemID:    itemID,
+			AccountID: accountID,
+			Previous:  &prev,
+		})
+	}
+
+	return events
+}
@@ -0,0 +1,256 @@
+package plaid
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestBalanceMonitor(t *testing.T) {
+	server, client := newFakeServer(t)
+
+	// The handler runs on the server's goroutines, so it only records what it
+	// receives and the test asserts on it afterwards.
+	var (
+		mu           sync.Mutex
+		accessTokens []interface{}
+		accounts     = []Account{
+			{AccountID: "checking", Balances: AccountBalances{Available: 500, Current: 500, ISOCurrencyCode: "USD"}},
+			{AccountID: "credit", Balances: AccountBalances{Current: 100, Limit: 1000, ISOCurrencyCode: "USD"}},
+		}
+	)
+	setAccounts := func(a []Account) {
+		mu.Lock()
+		defer mu.Unlock()
+		accounts = a
+	}
+	server.handle("/accounts/balance/get", func(body map[string]interface{}) (int, interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		accessTokens = append(accessTokens, body["access_token"])
+		return http.StatusOK, GetBalancesResponse{Accounts: accounts}
+	})
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryBalanceStore()
+	var received []BalanceEvent
+	monitor := NewBalanceMonitor(client, store, BalanceMonitorOptions{
+		Thresholds:  []BalanceThreshold{{AccountID: "checking", Amount: 100, UseAvailable: true}},
+		LargeChange: 250,
+		OnEvent:     func(e BalanceEvent) { received = append(received, e) },
+	})
+	monitor.now = func() time.Time { return now }
+	monitor.Watch("item-1", "access-token")
+
+	// The first snapshot has nothing to compare against.
+	events, err := monitor.Refresh("item-1")
+	assert.Nil(t, err)
+	assert.Empty(t, events)
+
+	// A second refresh within the rate limit is rejected without a call.
+	_, err = monitor.Refresh("item-1")
+	assert.Equal(t, ErrBalanceRefreshThrottled, err)
+	assert.Equal(t, 1, server.callCount("/accounts/balance/get"))
+
+	now = now.Add(time.Hour)
+	setAccounts([]Account{
+		{AccountID: "checking", Balances: AccountBalances{Available: 50, Current: 50, ISOCurrencyCode: "USD"}},
+		{AccountID: "credit", Balances: AccountBalances{Current: 100, Limit: 2000, ISOCurrencyCode: "USD"}},
+	})
+	events, err = monitor.Refresh("item-1")
+	assert.Nil(t, err)
+	assert.Len(t, events, 3)
+	assert.Equal(t, BalanceEventTypes.ThresholdCrossed, events[0].Type)
+	assert.Equal(t, "checking", events[0].AccountID)
+	assert.True(t, events[0].Below)
+	assert.Equal(t, BalanceEventTypes.LargeChange, events[1].Type)
+	assert.Equal(t, 500.0, events[1].Previous.Balances.Current)
+	assert.Equal(t, 50.0, events[1].Current.Balances.Current)
+	assert.Equal(t, BalanceEventTypes.LimitChanged, events[2].Type)
+	assert.Equal(t, "credit", events[2].AccountID)
+	assert.Equal(t, events, received)
+
+	now = now.Add(time.Hour)
+	setAccounts([]Account{
+		{AccountID: "checking", Balances: AccountBalances{Available: 50, Current: 50, ISOCurrencyCode: "USD"}},
+	})
+	events, err = monitor.Refresh("item-1")
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, BalanceEventTypes.AccountDisappeared, events[0].Type)
+	assert.Equal(t, "credit", events[0].AccountID)
+	assert.Nil(t, events[0].Current)
+
+	assert.Len(t, store.History("checking"), 3)
+	assert.Len(t, store.History("credit"), 2)
+
+	_, err = monitor.Refresh("unknown-item")
+	assert.NotNil(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []interface{}{"access-token", "access-token", "access-token"}, accessTokens)
+}
+
+func TestBalanceMonitorRetriesFailedRefresh(t *testing.T) {
+	server, client := newFakeServer(t)
+	var mu sync.Mutex
+	status := http.StatusInternalServerError
+	setStatus := func(s int) {
+		mu.Lock()
+		defer mu.Unlock()
+		status = s
+	}
+	server.handle("/accounts/balance/get", func(body map[string]interface{}) (int, interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		if status != http.StatusOK {
+			return status, map[string]interface{}{"error_type": "API_ERROR", "error_code": "INTERNAL_SERVER_ERROR"}
+		}
+		return status, GetBalancesResponse{Accounts: []Account{{AccountID: "checking"}}}
+	})
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	monitor := NewBalanceMonitor(client, NewMemoryBalanceStore(), BalanceMonitorOptions{})
+	monitor.now = func() time.Time { return now }
+	monitor.Watch("item-1", "access-token")
+
+	// A failed refresh does not count against the rate limit.
+	_, err := monitor.Refresh("item-1")
+	assert.NotNil(t, err)
+	assert.NotEqual(t, ErrBalanceRefreshThrottled, err)
+
+	setStatus(http.StatusOK)
+	_, err = monitor.Refresh("item-1")
+	assert.Nil(t, err)
+
+	// Nor does one that follows a successful refresh.
+	now = now.Add(time.Hour)
+	setStatus(http.StatusInternalServerError)
+	_, err = monitor.Refresh("item-1")
+	assert.NotEqual(t, ErrBalanceRefreshThrottled, err)
+	setStatus(http.StatusOK)
+	_, err = monitor.Refresh("item-1")
+	assert.Nil(t, err)
+
+	_, err = monitor.Refresh("item-1")
+	assert.Equal(t, ErrBalanceRefreshThrottled, err)
+	assert.Equal(t, 4, server.callCount("/accounts/balance/get"))
+}
+
+func TestBalanceMonitorWebhook(t *testing.T) {
+	server, client := newFakeServer(t)
+	server.handle("/accounts/balance/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, GetBalancesResponse{Accounts: []Account{{AccountID: "checking"}}}
+	})
+
+	monitor := NewBalanceMonitor(client, NewMemoryBalanceStore(), BalanceMonitorOptions{})
+	monitor.Watch("item-1", "access-token")
+
+	_, err := monitor.HandleWebhook(Webhook{WebhookType: "ITEM", WebhookCode: "ERROR", ItemID: "item-1"})
+	assert.Nil(t, err)
+	_, err = monitor.HandleWebhook(Webhook{WebhookType: "TRANSACTIONS", WebhookCode: "DEFAULT_UPDATE", ItemID: "item-2"})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, server.callCount("/accounts/balance/get"))
+
+	_, err = monitor.HandleWebhook(Webhook{WebhookType: "TRANSACTIONS", WebhookCode: "DEFAULT_UPDATE", ItemID: "item-1"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, server.callCount("/accounts/balance/get"))
+}
+
+func TestBalanceMonitorWebhookDefersThrottledRefresh(t *testing.T) {
+	server, client := newFakeServer(t)
+	var (
+		mu       sync.Mutex
+		accounts = []Account{{AccountID: "checking", Balances: AccountBalances{Current: 500}}}
+	)
+	server.handle("/accounts/balance/get", func(body map[string]interface{}) (int, interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		return http.StatusOK, GetBalancesResponse{Accounts: accounts}
+	})
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var received []BalanceEvent
+	monitor := NewBalanceMonitor(client, NewMemoryBalanceStore(), BalanceMonitorOptions{
+		MinRefreshInterval: time.Minute,
+		LargeChange:        100,
+		OnEvent:            func(e BalanceEvent) { received = append(received, e) },
+	})
+	monitor.now = func() time.Time { return now }
+	var (
+		delays   []time.Duration
+		deferred func()
+	)
+	monitor.afterFunc = func(d time.Duration, f func()) {
+		delays = append(delays, d)
+		deferred = f
+	}
+	monitor.Watch("item-1", "access-token")
+
+	update := Webhook{WebhookType: "TRANSACTIONS", WebhookCode: "DEFAULT_UPDATE", ItemID: "item-1"}
+	_, err := monitor.HandleWebhook(update)
+	assert.Nil(t, err)
+
+	// Updates within the rate limit share a single refresh, run once the
+	// limit allows it.
+	now = now.Add(20 * time.Second)
+	mu.Lock()
+	accounts = []Account{{AccountID: "checking", Balances: AccountBalances{Current: 50}}}
+	mu.Unlock()
+	events, err := monitor.HandleWebhook(update)
+	assert.Nil(t, err)
+	assert.Empty(t, events)
+	_, err = monitor.HandleWebhook(update)
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Duration{40 * time.Second}, delays)
+	assert.Equal(t, 1, server.callCount("/accounts/balance/get"))
+
+	now = now.Add(40 * time.Second)
+	deferred()
+	assert.Equal(t, 2, server.callCount("/accounts/balance/get"))
+	assert.Len(t, received, 1)
+	assert.Equal(t, BalanceEventTypes.LargeChange, received[0].Type)
+
+	// A refresh deferred for an Item that is no longer watched is dropped.
+	_, err = monitor.HandleWebhook(update)
+	assert.Nil(t, err)
+	assert.Len(t, delays, 2)
+	monitor.Unwatch("item-1")
+	deferred()
+	assert.Equal(t, 2, server.callCount("/accounts/balance/get"))
+}
+
+func TestBalanceMonitorRun(t *testing.T) {
+	server, client := newFakeServer(t)
+	server.handle("/accounts/balance/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusBadRequest, Error{ErrorType: "ITEM_ERROR", ErrorCode: "ITEM_LOGIN_REQUIRED"}
+	})
+
+	type failure struct {
+		itemID string
+		err    error
+	}
+	failures := make(chan failure, 1)
+	monitor := NewBalanceMonitor(client, NewMemoryBalanceStore(), BalanceMonitorOptions{
+		PollInterval: time.Hour,
+		OnError: func(itemID string, err error) {
+			failures <- failure{itemID, err}
+		},
+	})
+	monitor.Watch("item-1", "access-token")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- monitor.Run(ctx) }()
+
+	f := <-failures
+	assert.Equal(t, "item-1", f.itemID)
+	assert.Equal(t, "ITEM_LOGIN_REQUIRED", f.err.(Error).ErrorCode)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
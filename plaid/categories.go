@@ -18,7 +18,7 @@ type GetCategoriesResponse struct {
 // GetCategories returns information for all categories.
 // See https://plaid.com/docs/api/products/#categoriesget.
 func (c *Client) GetCategories() (resp GetCategoriesResponse, err error) {
-	jsonBody, _ := json.Marshal(nil)
+	jsonBody, _ := json.Marshal(struct{}{})
 
 	err = c.Call("/categories/get", jsonBody, &resp)
 	return resp, err
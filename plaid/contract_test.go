@@ -0,0 +1,475 @@
+package plaid
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the request fixtures in testdata/api")
+
+// contract exercises one endpoint against the fixtures in
+// testdata/api/<endpoint>: request.json is the body the call must send, and
+// response.json (or response.pdf) is an example of what Plaid responds with.
+// An endpoint whose requests vary in shape can have further contracts named
+// <endpoint>#<variant>, whose bodies are checked against request_<variant>.json
+// and which share the endpoint's response.
+type contract struct {
+	endpoint string
+	call     func(c *Client) (interface{}, error)
+}
+
+// contracts must have an entry for every endpoint the package calls; see
+// TestEveryEndpointHasContract.
+var contracts = []contract{
+	{"/accounts/balance/get", func(c *Client) (interface{}, error) {
+		return c.GetBalancesWithOptions("access-sandbox-1", GetBalancesOptions{AccountIDs: []string{"BxBXxLj1m4HMXBm9WZZmCWVbPjX16EHwv99vp"}})
+	}},
+	{"/accounts/get", func(c *Client) (interface{}, error) {
+		return c.GetAccounts("access-sandbox-1")
+	}},
+	{"/asset_report/audit_copy/create", func(c *Client) (interface{}, error) {
+		return c.CreateAuditCopy("assets-sandbox-1", "fannie_mae")
+	}},
+	{"/asset_report/audit_copy/get", func(c *Client) (interface{}, error) {
+		return c.GetAuditCopy("a-sandbox-1")
+	}},
+	{"/asset_report/audit_copy/remove", func(c *Client) (interface{}, error) {
+		return c.RemoveAuditCopy("a-sandbox-1")
+	}},
+	{"/asset_report/create", func(c *Client) (interface{}, error) {
+		return c.CreateAssetReportWithOptions([]string{"access-sandbox-1"}, 60, CreateAssetReportOptions{
+			ClientReportID: "report-1",
+			Webhook:        "https://www.example.com/webhook",
+			User: &CreateAssetReportUser{
+				ClientUserID: "user-1",
+				FirstName:    "Alberta",
+				LastName:     "Charleson",
+				Email:        "accountholder0@example.com",
+			},
+		})
+	}},
+	{"/asset_report/filter", func(c *Client) (interface{}, error) {
+		return c.FilterAssetReport("assets-sandbox-1", []string{"QPO8Jo8vdDHMepg41PBwckXm4KdK1yUdmXOwK"})
+	}},
+	{"/asset_report/get", func(c *Client) (interface{}, error) {
+		return c.GetAssetReportWithOptions("assets-sandbox-1", GetAssetReportOptions{IncludeInsights: true})
+	}},
+	{"/asset_report/pdf/get", func(c *Client) (interface{}, error) {
+		var buf bytes.Buffer
+		_, err := c.GetAssetReportPDF("assets-sandbox-1", &buf)
+		return buf.Bytes(), err
+	}},
+	{"/asset_report/refresh", func(c *Client) (interface{}, error) {
+		return c.RefreshAssetReport("assets-sandbox-1", 30)
+	}},
+	{"/asset_report/remove", func(c *Client) (interface{}, error) {
+		return c.RemoveAssetReport("assets-sandbox-1")
+	}},
+	{"/auth/get", func(c *Client) (interface{}, error) {
+		return c.GetAuth("access-sandbox-1")
+	}},
+	{"/categories/get", func(c *Client) (interface{}, error) {
+		return c.GetCategories()
+	}},
+	{"/deposit_switch/create", func(c *Client) (interface{}, error) {
+		return c.CreateDepositSwitchWithOptions("9rWqxnDPGNSjVL8M6RAjcM6rLQnPp3iQvMEdb", "access-sandbox-1", CreateDepositSwitchOptions{
+			IdempotencyKey: "switch-1",
+		})
+	}},
+	{"/deposit_switch/get", func(c *Client) (interface{}, error) {
+		return c.GetDepositSwitch("LjDyMrNpJbIzOR6kAHt6pOJ4E1ZMyDTJIjMTd")
+	}},
+	{"/deposit_switch/token/create", func(c *Client) (interface{}, error) {
+		return c.CreateDepositSwitchToken("LjDyMrNpJbIzOR6kAHt6pOJ4E1ZMyDTJIjMTd")
+	}},
+	{"/employers/search", func(c *Client) (interface{}, error) {
+		return c.SearchEmployers("Plaid", []string{"deposit_switch"})
+	}},
+	{"/identity/get", func(c *Client) (interface{}, error) {
+		return c.GetIdentity("access-sandbox-1")
+	}},
+	{"/identity/match", func(c *Client) (interface{}, error) {
+		return c.GetIdentityMatch("access-sandbox-1", IdentityMatchUser{
+			LegalName:    "Alberta Bobbeth Charleson",
+			PhoneNumber:  "+14155555555",
+			EmailAddress: "accountholder0@example.com",
+			Address: &AddressData{
+				City:       "Malakoff",
+				Region:     "NY",
+				Street:     "2992 Cameron Road",
+				PostalCode: "14236",
+				Country:    "US",
+			},
+		})
+	}},
+	{"/income/get", func(c *Client) (interface{}, error) {
+		return c.GetIncome("access-sandbox-1")
+	}},
+	{"/institutions/get", func(c *Client) (interface{}, error) {
+		return c.GetInstitutions(2, 0, []string{"US"})
+	}},
+	{"/institutions/get_by_id", func(c *Client) (interface{}, error) {
+		return c.GetInstitutionByIDWithOptions("ins_109508", []string{"US"}, GetInstitutionByIDOptions{IncludeStatus: true})
+	}},
+	{"/institutions/search", func(c *Client) (interface{}, error) {
+		return c.SearchInstitutions("First Platypus", []string{"transactions"}, []string{"US"})
+	}},
+	{"/investments/holdings/get", func(c *Client) (interface{}, error) {
+		return c.GetHoldings("access-sandbox-1")
+	}},
+	{"/investments/transactions/get", func(c *Client) (interface{}, error) {
+		return c.GetInvestmentTransactions("access-sandbox-1", "2021-01-01", "2021-03-31")
+	}},
+	{"/item/access_token/invalidate", func(c *Client) (interface{}, error) {
+		return c.InvalidateAccessToken("access-sandbox-1")
+	}},
+	{"/item/get", func(c *Client) (interface{}, error) {
+		return c.GetItem("access-sandbox-1")
+	}},
+	{"/item/import", func(c *Client) (interface{}, error) {
+		return c.ImportItem([]string{"identity", "auth"}, map[string]interface{}{
+			"user_id":    "user-1",
+			"auth_token": "auth-token-1",
+		}, importItemRequestOptions{Webhook: "https://www.example.com/webhook"})
+	}},
+	{"/item/public_token/create", func(c *Client) (interface{}, error) {
+		return c.CreatePublicToken("access-sandbox-1")
+	}},
+	{"/item/public_token/exchange", func(c *Client) (interface{}, error) {
+		return c.ExchangePublicToken("public-sandbox-1")
+	}},
+	{"/item/remove", func(c *Client) (interface{}, error) {
+		return c.RemoveItem("access-sandbox-1")
+	}},
+	{"/item/webhook/update", func(c *Client) (interface{}, error) {
+		return c.UpdateItemWebhook("access-sandbox-1", "https://www.example.com/webhook")
+	}},
+	{"/liabilities/get", func(c *Client) (interface{}, error) {
+		return c.GetLiabilities("access-sandbox-1")
+	}},
+	{"/link/token/create", func(c *Client) (interface{}, error) {
+		return c.CreateLinkToken(LinkTokenConfigs{
+			User: &LinkTokenUser{
+				ClientUserID: "user-1",
+				Address: &LinkTokenUserAddress{
+					Street:     "2992 Cameron Road",
+					City:       "Malakoff",
+					Region:     "NY",
+					PostalCode: "14236",
+					Country:    "US",
+				},
+				DateOfBirth: "1975-01-18",
+			},
+			ClientName:   "Plaid Test",
+			Products:     []string{"auth", "transactions"},
+			CountryCodes: []string{"US"},
+			Language:     "en",
+			Webhook:      "https://www.example.com/webhook",
+			AccountFilters: &LinkTokenAccountFilters{
+				Depository: &DepositoryAccountFilter{AccountSubtypes: []DepositorySubtype{DepositorySubtypes.Checking}},
+			},
+			Auth:            &LinkTokenAuth{SameDayMicrodepositsEnabled: true},
+			InstitutionData: &LinkTokenInstitutionData{RoutingNumber: "011401533"},
+		})
+	}},
+	{"/link/token/get", func(c *Client) (interface{}, error) {
+		return c.GetLinkToken("link-sandbox-1")
+	}},
+	{"/payment_initiation/consent/create", func(c *Client) (interface{}, error) {
+		from := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		return c.CreatePaymentConsent("recipient-id-sandbox-1", "ref-00001", []PaymentConsentScope{PaymentConsentScopes.MeToMe}, PaymentConsentConstraints{
+			ValidDateTime:    &PaymentConsentValidDateTime{From: &from},
+			MaxPaymentAmount: PaymentAmount{Currency: "GBP", Value: 100},
+			PeriodicAmounts: []PaymentConsentPeriodicAmount{{
+				Amount:    PaymentAmount{Currency: "GBP", Value: 300},
+				Interval:  PaymentConsentPeriodicIntervals.Month,
+				Alignment: PaymentConsentPeriodicAlignments.Calendar,
+			}},
+		})
+	}},
+	{"/payment_initiation/consent/get", func(c *Client) (interface{}, error) {
+		return c.GetPaymentConsent("consent-id-sandbox-1")
+	}},
+	{"/payment_initiation/consent/payment/execute", func(c *Client) (interface{}, error) {
+		return c.ExecutePaymentConsent("consent-id-sandbox-1", PaymentAmount{Currency: "GBP", Value: 7.99}, "idempotency-key-1")
+	}},
+	{"/payment_initiation/consent/revoke", func(c *Client) (interface{}, error) {
+		return c.RevokePaymentConsent("consent-id-sandbox-1")
+	}},
+	{"/payment_initiation/payment/create", func(c *Client) (interface{}, error) {
+		return c.CreatePaymentWithOptions("recipient-id-sandbox-1", "ref-00001", PaymentAmount{Currency: "GBP", Value: 100}, CreatePaymentOptions{
+			Schedule: &PaymentSchedule{
+				Interval:             PaymentScheduleIntervals.Weekly,
+				IntervalExecutionDay: 1,
+				StartDate:            "2021-03-01",
+			},
+			RequestRefundDetails: true,
+			IdempotencyKey:       "order-1",
+		})
+	}},
+	{"/payment_initiation/payment/create#single", func(c *Client) (interface{}, error) {
+		return c.CreatePayment("recipient-id-sandbox-1", "ref-00001", PaymentAmount{Currency: "GBP", Value: 100}, nil)
+	}},
+	{"/payment_initiation/payment/get", func(c *Client) (interface{}, error) {
+		return c.GetPayment("payment-id-sandbox-1")
+	}},
+	{"/payment_initiation/payment/list", func(c *Client) (interface{}, error) {
+		count := 10
+		return c.ListPayments(ListPaymentsOptions{Count: &count})
+	}},
+	{"/payment_initiation/payment/token/create", func(c *Client) (interface{}, error) {
+		return c.CreatePaymentToken("payment-id-sandbox-1")
+	}},
+	{"/payment_initiation/recipient/create", func(c *Client) (interface{}, error) {
+		iban := "GB33BUKB20201555555555"
+		return c.CreatePaymentRecipient("Wonder Wallet", OptionalRecipientCreateParams{
+			IBAN: &iban,
+			Address: &PaymentRecipientAddress{
+				Street:     []string{"96 Guild Street", "9th Floor"},
+				City:       "London",
+				PostalCode: "SE14 8JW",
+				Country:    "GB",
+			},
+			IdempotencyKey: "recipient-1",
+		})
+	}},
+	{"/payment_initiation/recipient/create#bacs", func(c *Client) (interface{}, error) {
+		recipient, err := NewPaymentRecipient("Wonder Wallet", nil, &PaymentRecipientBacs{Account: "26207729", SortCode: "560029"}, nil)
+		if err != nil {
+			return nil, err
+		}
+		return c.CreatePaymentRecipientWithOptions(recipient, CreatePaymentRecipientOptions{})
+	}},
+	{"/payment_initiation/recipient/get", func(c *Client) (interface{}, error) {
+		return c.GetPaymentRecipient("recipient-id-sandbox-1")
+	}},
+	{"/payment_initiation/recipient/list", func(c *Client) (interface{}, error) {
+		return c.ListPaymentRecipients()
+	}},
+	{"/processor/balance/get", func(c *Client) (interface{}, error) {
+		return c.GetProcessorBalance("processor-sandbox-1")
+	}},
+	{"/processor/apex/processor_token/create", func(c *Client) (interface{}, error) {
+		return c.CreateApexTokenWithOptions("access-sandbox-1", "blwVRY37nGfVX5lAkYzxsA4lkLRwZNuRVAV4j", ProcessorTokenOptions{IdempotencyKey: "apex-1"})
+	}},
+	{"/processor/dwolla/processor_token/create", func(c *Client) (interface{}, error) {
+		return c.CreateDwollaTokenWithOptions("access-sandbox-1", "blwVRY37nGfVX5lAkYzxsA4lkLRwZNuRVAV4j", ProcessorTokenOptions{IdempotencyKey: "dwolla-1"})
+	}},
+	{"/processor/ocrolus/processor_token/create", func(c *Client) (interface{}, error) {
+		return c.CreateOcrolusTokenWithOptions("access-sandbox-1", "blwVRY37nGfVX5lAkYzxsA4lkLRwZNuRVAV4j", ProcessorTokenOptions{IdempotencyKey: "ocrolus-1"})
+	}},
+	{"/processor/stripe/bank_account_token/create", func(c *Client) (interface{}, error) {
+		return c.CreateStripeTokenWithOptions("access-sandbox-1", "blwVRY37nGfVX5lAkYzxsA4lkLRwZNuRVAV4j", ProcessorTokenOptions{IdempotencyKey: "stripe-1"})
+	}},
+	{"/processor/token/create", func(c *Client) (interface{}, error) {
+		return c.CreateProcessorTokenWithOptions("access-sandbox-1", "blwVRY37nGfVX5lAkYzxsA4lkLRwZNuRVAV4j", "dwolla", ProcessorTokenOptions{IdempotencyKey: "processor-1"})
+	}},
+	{"/sandbox/item/fire_webhook", func(c *Client) (interface{}, error) {
+		return c.FireSandboxItemWebhookWithOptions("access-sandbox-1", SandboxItemFireWebhookCodes.DefaultUpdate, FireSandboxItemWebhookOptions{
+			WebhookType: WebhookTypes.Transactions,
+		})
+	}},
+	{"/sandbox/item/reset_login", func(c *Client) (interface{}, error) {
+		return c.ResetSandboxItem("access-sandbox-1")
+	}},
+	{"/sandbox/item/set_verification_status", func(c *Client) (interface{}, error) {
+		return c.SetSandboxItemVerificationStatus("access-sandbox-1", "blwVRY37nGfVX5lAkYzxsA4lkLRwZNuRVAV4j", "automatically_verified")
+	}},
+	{"/sandbox/public_token/create", func(c *Client) (interface{}, error) {
+		return c.CreateSandboxPublicToken("ins_109508", []string{"transactions"})
+	}},
+	{"/transactions/get", func(c *Client) (interface{}, error) {
+		return c.GetTransactions("access-sandbox-1", "2021-01-01", "2021-03-31")
+	}},
+	{"/transactions/refresh", func(c *Client) (interface{}, error) {
+		return c.RefreshTransactions("access-sandbox-1")
+	}},
+	{"/user/create", func(c *Client) (interface{}, error) {
+		return c.CreateUser("user-1")
+	}},
+	{"/webhook_verification_key/get", func(c *Client) (interface{}, error) {
+		return c.GetWebhookVerificationKey("6c5516e1-92dc-479e-a8ff-5a51992e0001")
+	}},
+}
+
+// split returns the endpoint the contract calls and the name of its variant,
+// if it is one.
+func (c contract) split() (endpoint, variant string) {
+	endpoint, variant, _ = strings.Cut(c.endpoint, "#")
+	return endpoint, variant
+}
+
+func contractDir(endpoint string) string {
+	return filepath.Join("testdata", "api", filepath.FromSlash(strings.TrimPrefix(endpoint, "/")))
+}
+
+func TestContracts(t *testing.T) {
+	for _, c := range contracts {
+		c := c
+		t.Run(strings.TrimPrefix(c.endpoint, "/"), func(t *testing.T) {
+			endpoint, variant := c.split()
+			dir := contractDir(endpoint)
+			pdf, pdfErr := os.ReadFile(filepath.Join(dir, "response.pdf"))
+			response, err := os.ReadFile(filepath.Join(dir, "response.json"))
+			if pdfErr != nil && err != nil {
+				// Request fixtures can be written before the response
+				// fixture exists.
+				assert.True(t, *updateGolden, "%s needs a response.json or response.pdf fixture", dir)
+				response = []byte("{}")
+			}
+
+			server, client := newFakeServer(t)
+			var sent []byte
+			server.handle(endpoint, func(body map[string]interface{}) (int, interface{}) {
+				sent, _ = json.MarshalIndent(body, "", "  ")
+				if pdfErr == nil {
+					return http.StatusOK, pdf
+				}
+				return http.StatusOK, json.RawMessage(response)
+			})
+
+			result, err := c.call(client)
+			assert.Nil(t, err)
+			assert.NotNil(t, sent, "%s was not called", endpoint)
+
+			requestPath := filepath.Join(dir, "request.json")
+			if variant != "" {
+				requestPath = filepath.Join(dir, "request_"+variant+".json")
+			}
+			if *updateGolden {
+				assert.Nil(t, os.MkdirAll(dir, 0o755))
+				assert.Nil(t, os.WriteFile(requestPath, append(sent, '\n'), 0o644))
+			} else {
+				expected, err := os.ReadFile(requestPath)
+				assert.Nil(t, err, "%s needs a %s fixture", dir, filepath.Base(requestPath))
+				assert.JSONEq(t, string(expected), string(sent), "request sent to %s", endpoint)
+			}
+
+			if pdfErr == nil {
+				assert.Equal(t, pdf, result)
+				return
+			}
+
+			// Decode the fixture on its own so that fields Plaid sends but
+			// the response type does not declare are caught.
+			decoded := reflect.New(reflect.TypeOf(result))
+			decoder := json.NewDecoder(bytes.NewReader(response))
+			decoder.DisallowUnknownFields()
+			assert.Nil(t, decoder.Decode(decoded.Interface()), "%s/response.json", dir)
+
+			var raw interface{}
+			assert.Nil(t, json.Unmarshal(response, &raw))
+			missing := missingFields(decoded.Elem().Type(), raw, "")
+			assert.Empty(t, missing, "%s/response.json lacks fields the response type declares", dir)
+		})
+	}
+}
+
+// missingFields lists the JSON fields declared by t that are absent from v,
+// the decoded JSON of a value of type t. Fields tagged omitempty may be
+// absent, and fields without a json tag are not part of the contract.
+func missingFields(t reflect.Type, v interface{}, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if v == nil || t == reflect.TypeOf(time.Time{}) {
+		return nil
+	}
+
+	var missing []string
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if field.Anonymous && tag == "" {
+				missing = append(missing, missingFields(field.Type, object, path)...)
+				continue
+			}
+			if field.PkgPath != "" || tag == "" || tag == "-" {
+				continue
+			}
+			name := strings.Split(tag, ",")[0]
+			value, ok := object[name]
+			if !ok {
+				if !strings.Contains(tag, ",omitempty") {
+					missing = append(missing, path+name)
+				}
+				continue
+			}
+			missing = append(missing, missingFields(field.Type, value, path+name+".")...)
+		}
+	case reflect.Slice:
+		array, _ := v.([]interface{})
+		for i, element := range array {
+			missing = append(missing, missingFields(t.Elem(), element, path+strconv.Itoa(i)+".")...)
+		}
+	}
+	return missing
+}
+
+// TestEveryEndpointHasContract fails when the package calls an endpoint that
+// has no entry in contracts, so that adding an endpoint requires adding its
+// fixtures.
+func TestEveryEndpointHasContract(t *testing.T) {
+	covered := map[string]bool{}
+	for _, c := range contracts {
+		endpoint, _ := c.split()
+		covered[endpoint] = true
+	}
+
+	files, err := filepath.Glob("*.go")
+	assert.Nil(t, err)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		assert.Nil(t, err)
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			selector, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			switch selector.Sel.Name {
+			case "Call", "CallRaw", "callIdempotent", "requestProcessorToken":
+			default:
+				return true
+			}
+			literal, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || literal.Kind != token.STRING {
+				return true
+			}
+			endpoint, err := strconv.Unquote(literal.Value)
+			assert.Nil(t, err)
+			if !strings.HasPrefix(endpoint, "/") {
+				endpoint = "/" + endpoint
+			}
+			assert.True(t, covered[endpoint], "%s calls %s, which has no entry in contracts", fset.Position(literal.Pos()), endpoint)
+			return true
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package plaid
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestValidateCountryCode(t *testing.T) {
+	for _, code := range []string{"GB", "US", "FR", "AX", "ZW"} {
+		assert.Nil(t, ValidateCountryCode(code), code)
+	}
+	for _, code := range []string{"", "gb", "UK", "GBR", "XK", "ZZ"} {
+		assert.NotNil(t, ValidateCountryCode(code), code)
+	}
+}
@@ -0,0 +1,227 @@
+package plaid
+
+import (
+	"fmt"
+	"math"
+)
+
+// Currency is a currency code as returned by Plaid. It is either an ISO 4217
+// code (e.g. "USD") or one of the unofficial codes Plaid uses for currencies
+// that have no ISO 4217 code (see UnofficialCurrencyCodes).
+type Currency string
+
+type currencyInfo struct {
+	// minorUnits is the number of digits after the decimal separator.
+	minorUnits int
+	official   bool
+}
+
+var currencies = map[Currency]currencyInfo{
+	"AED": {2, true}, "AFN": {2, true}, "ALL": {2, true}, "AMD": {2, true},
+	"ANG": {2, true}, "AOA": {2, true}, "ARS": {2, true}, "AUD": {2, true},
+	"AWG": {2, true}, "AZN": {2, true}, "BAM": {2, true}, "BBD": {2, true},
+	"BDT": {2, true}, "BGN": {2, true}, "BHD": {3, true}, "BIF": {0, true},
+	"BMD": {2, true}, "BND": {2, true}, "BOB": {2, true}, "BRL": {2, true},
+	"BSD": {2, true}, "BTN": {2, true}, "BWP": {2, true}, "BYN": {2, true},
+	"BZD": {2, true}, "CAD": {2, true}, "CDF": {2, true}, "CHF": {2, true},
+	"CLP": {0, true}, "CNY": {2, true}, "COP": {2, true}, "CRC": {2, true},
+	"CUP": {2, true}, "CVE": {2, true}, "CZK": {2, true}, "DJF": {0, true},
+	"DKK": {2, true}, "DOP": {2, true}, "DZD": {2, true}, "EGP": {2, true},
+	"ERN": {2, true}, "ETB": {2, true}, "EUR": {2, true}, "FJD": {2, true},
+	"FKP": {2, true}, "GBP": {2, true}, "GEL": {2, true}, "GHS": {2, true},
+	"GIP": {2, true}, "GMD": {2, true}, "GNF": {0, true}, "GTQ": {2, true},
+	"GYD": {2, true}, "HKD": {2, true}, "HNL": {2, true}, "HTG": {2, true},
+	"HUF": {2, true}, "IDR": {2, true}, "ILS": {2, true}, "INR": {2, true},
+	"IQD": {3, true}, "IRR": {2, true}, "ISK": {0, true}, "JMD": {2, true},
+	"JOD": {3, true}, "JPY": {0, true}, "KES": {2, true}, "KGS": {2, true},
+	"KHR": {2, true}, "KMF": {0, true}, "KPW": {2, true}, "KRW": {0, true},
+	"KWD": {3, true}, "KYD": {2, true}, "KZT": {2, true}, "LAK": {2, true},
+	"LBP": {2, true}, "LKR": {2, true}, "LRD": {2, true}, "LSL": {2, true},
+	"LYD": {3, true}, "MAD": {2, true}, "MDL": {2, true}, "MGA": {2, true},
+	"MKD": {2, true}, "MMK": {2, true}, "MNT": {2, true}, "MOP": {2, true},
+	"MRU": {2, true}, "MUR": {2, true}, "MVR": {2, true}, "MWK": {2, true},
+	"MXN": {2, true}, "MYR": {2, true}, "MZN": {2, true}, "NAD": {2, true},
+	"NGN": {2, true}, "NIO": {2, true}, "NOK": {2, true}, "NPR": {2, true},
+	"NZD": {2, true}, "OMR": {3, true}, "PAB": {2, true}, "PEN": {2, true},
+	"PGK": {2, true}, "PHP": {2, true}, "PKR": {2, true}, "PLN": {2, true},
+	"PYG": {0, true}, "QAR": {2, true}, "RON": {2, true}, "RSD": {2, true},
+	"RUB": {2, true}, "RWF": {0, true}, "SAR": {2, true}, "SBD": {2, true},
+	"SCR": {2, true}, "SDG": {2, true}, "SEK": {2, true}, "SGD": {2, true},
+	"SHP": {2, true}, "SLE": {2, true}, "SOS": {2, true}, "SRD": {2, true},
+	"SSP": {2, true}, "STN": {2, true}, "SVC": {2, true}, "SYP": {2, true},
+	"SZL": {2, true}, "THB": {2, true}, "TJS": {2, true}, "TMT": {2, true},
+	"TND": {3, true}, "TOP": {2, true}, "TRY": {2, true}, "TTD": {2, true},
+	"TWD": {2, true}, "TZS": {2, true}, "UAH": {2, true}, "UGX": {0, true},
+	"USD": {2, true}, "UYU": {2, true}, "UZS": {2, true}, "VES": {2, true},
+	"VND": {0, true}, "VUV": {0, true}, "WST": {2, true}, "XAF": {0, true},
+	"XCD": {2, true}, "XOF": {0, true}, "XPF": {0, true}, "YER": {2, true},
+	"ZAR": {2, true}, "ZMW": {2, true}, "ZWL": {2, true},
+
+	unofficialCurrencyCodeADA:  {6, false},
+	unofficialCurrencyCodeBAT:  {18, false},
+	unofficialCurrencyCodeBCH:  {8, false},
+	unofficialCurrencyCodeBNB:  {8, false},
+	unofficialCurrencyCodeBTC:  {8, false},
+	unofficialCurrencyCodeBTG:  {8, false},
+	unofficialCurrencyCodeCNH:  {2, false},
+	unofficialCurrencyCodeDASH: {8, false},
+	unofficialCurrencyCodeDOGE: {8, false},
+	unofficialCurrencyCodeETC:  {18, false},
+	unofficialCurrencyCodeETH:  {18, false},
+	unofficialCurrencyCodeGBX:  {2, false},
+	unofficialCurrencyCodeLSK:  {8, false},
+	unofficialCurrencyCodeNEO:  {0, false},
+	unofficialCurrencyCodeOMG:  {18, false},
+	unofficialCurrencyCodeQTUM: {8, false},
+	unofficialCurrencyCodeUSDT: {6, false},
+	unofficialCurrencyCodeXLM:  {7, false},
+	unofficialCurrencyCodeXMR:  {12, false},
+	unofficialCurrencyCodeXRP:  {6, false},
+	unofficialCurrencyCodeZEC:  {8, false},
+	unofficialCurrencyCodeZRX:  {18, false},
+}
+
+// Valid reports whether c is a known ISO 4217 or unofficial currency code.
+func (c Currency) Valid() bool {
+	_, ok := currencies[c]
+	return ok
+}
+
+// IsISO reports whether c is an ISO 4217 currency code.
+func (c Currency) IsISO() bool {
+	return currencies[c].official
+}
+
+// IsUnofficial reports whether c is one of Plaid's unofficial currency codes.
+func (c Currency) IsUnofficial() bool {
+	info, ok := currencies[c]
+	return ok && !info.official
+}
+
+// MinorUnits returns the number of decimal digits used by the currency, or 2
+// if the currency is unknown.
+func (c Currency) MinorUnits() int {
+	if info, ok := currencies[c]; ok {
+		return info.minorUnits
+	}
+	return 2
+}
+
+// Round rounds amount to the minor unit precision of the currency.
+func (c Currency) Round(amount float64) float64 {
+	scale := math.Pow10(c.MinorUnits())
+	return math.Round(amount*scale) / scale
+}
+
+// resolveCurrency returns whichever of the ISO and unofficial codes is set.
+// Plaid guarantees that at most one of them is non-null.
+func resolveCurrency(iso, unofficial Currency) Currency {
+	if iso != "" {
+		return iso
+	}
+	return unofficial
+}
+
+// Currency returns the currency of the balances.
+func (b AccountBalances) Currency() Currency {
+	return resolveCurrency(b.ISOCurrencyCode, b.UnofficialCurrencyCode)
+}
+
+// Currency returns the currency of the transaction.
+func (t Transaction) Currency() Currency {
+	return resolveCurrency(t.ISOCurrencyCode, t.UnofficialCurrencyCode)
+}
+
+// Currency returns the currency of the balance.
+func (b HistoricalBalance) Currency() Currency {
+	return resolveCurrency(b.ISOCurrencyCode, b.UnofficialCurrencyCode)
+}
+
+// Currency returns the currency of the transaction.
+func (t AssetReportTransaction) Currency() Currency {
+	return resolveCurrency(t.ISOCurrencyCode, t.UnofficialCurrencyCode)
+}
+
+// Currency returns the currency of the investment transaction.
+func (t InvestmentTransaction) Currency() Currency {
+	return resolveCurrency(t.ISOCurrencyCode, t.UnofficialCurrencyCode)
+}
+
+// Currency returns the currency of the holding.
+func (h Holding) Currency() Currency {
+	return resolveCurrency(h.ISOCurrencyCode, h.UnofficialCurrencyCode)
+}
+
+// Currency returns the currency the security's close price is quoted in.
+func (s Security) Currency() Currency {
+	return resolveCurrency(s.ISOCurrencyCode, s.UnofficialCurrencyCode)
+}
+
+// Converter converts amounts between currencies.
+type Converter interface {
+	Convert(amount float64, from, to Currency) (float64, error)
+}
+
+// StaticRateConverter is a Converter backed by a fixed table of exchange
+// rates. Rates holds the number of units of each currency that one unit of
+// Base buys; the rate of Base itself is implicitly 1.
+type StaticRateConverter struct {
+	Base  Currency
+	Rates map[Currency]float64
+}
+
+func (s StaticRateConverter) rate(c Currency) (float64, error) {
+	if c == s.Base {
+		return 1, nil
+	}
+	rate, ok := s.Rates[c]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("no exchange rate for currency %q", c)
+	}
+	return rate, nil
+}
+
+// Convert converts amount from one currency to another and rounds the result
+// to the minor unit precision of the target currency.
+func (s StaticRateConverter) Convert(amount float64, from, to Currency) (float64, error) {
+	if from == to {
+		return to.Round(amount), nil
+	}
+	fromRate, err := s.rate(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := s.rate(to)
+	if err != nil {
+		return 0, err
+	}
+	return to.Round(amount / fromRate * toRate), nil
+}
+
+// SumBalances returns the total current balance of accounts expressed in the
+// reporting currency to.
+func SumBalances(conv Converter, to Currency, accounts []Account) (float64, error) {
+	var total float64
+	for _, account := range accounts {
+		amount, err := conv.Convert(account.Balances.Current, account.Balances.Currency(), to)
+		if err != nil {
+			return 0, fmt.Errorf("account %s: %w", account.AccountID, err)
+		}
+		total += amount
+	}
+	return to.Round(total), nil
+}
+
+// SumHoldings returns the total institution value of holdings expressed in the
+// reporting currency to.
+func SumHoldings(conv Converter, to Currency, holdings []Holding) (float64, error) {
+	var total float64
+	for _, holding := range holdings {
+		amount, err := conv.Convert(holding.InstitutionValue, holding.Currency(), to)
+		if err != nil {
+			return 0, fmt.Errorf("holding %s in account %s: %w", holding.SecurityID, holding.AccountID, err)
+		}
+		total += amount
+	}
+	return to.Round(total), nil
+}
@@ -0,0 +1,100 @@
+package plaid
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestCurrency(t *testing.T) {
+	assert.True(t, Currency("USD").Valid())
+	assert.True(t, Currency("USD").IsISO())
+	assert.False(t, Currency("USD").IsUnofficial())
+	assert.Equal(t, 2, Currency("USD").MinorUnits())
+	assert.Equal(t, 0, Currency("JPY").MinorUnits())
+	assert.Equal(t, 3, Currency("KWD").MinorUnits())
+
+	assert.True(t, UnofficialCurrencyCodes.BTC.Valid())
+	assert.False(t, UnofficialCurrencyCodes.BTC.IsISO())
+	assert.True(t, UnofficialCurrencyCodes.BTC.IsUnofficial())
+	assert.Equal(t, 8, UnofficialCurrencyCodes.BTC.MinorUnits())
+
+	assert.False(t, Currency("XYZ").Valid())
+	assert.Equal(t, 2, Currency("XYZ").MinorUnits())
+
+	assert.Equal(t, 1.24, Currency("USD").Round(1.235000001))
+	assert.Equal(t, 124.0, Currency("JPY").Round(123.5))
+}
+
+func TestResolveCurrency(t *testing.T) {
+	assert.Equal(t, Currency("USD"), AccountBalances{ISOCurrencyCode: "USD"}.Currency())
+	assert.Equal(t, Currency("BTC"), Holding{UnofficialCurrencyCode: "BTC"}.Currency())
+	assert.Equal(t, Currency(""), Transaction{}.Currency())
+}
+
+func TestStaticRateConverter(t *testing.T) {
+	conv := StaticRateConverter{
+		Base: "USD",
+		Rates: map[Currency]float64{
+			"EUR": 0.5,
+			"JPY": 100,
+			"BTC": 0.0001,
+		},
+	}
+
+	amount, err := conv.Convert(10, "USD", "EUR")
+	assert.Nil(t, err)
+	assert.Equal(t, 5.0, amount)
+
+	amount, err = conv.Convert(5, "EUR", "JPY")
+	assert.Nil(t, err)
+	assert.Equal(t, 1000.0, amount)
+
+	amount, err = conv.Convert(1, "BTC", "USD")
+	assert.Nil(t, err)
+	assert.Equal(t, 10000.0, amount)
+
+	amount, err = conv.Convert(42, "GBP", "GBP")
+	assert.Nil(t, err)
+	assert.Equal(t, 42.0, amount)
+
+	amount, err = conv.Convert(12.345678, "JPY", "JPY")
+	assert.Nil(t, err)
+	assert.Equal(t, 12.0, amount)
+
+	_, err = conv.Convert(1, "GBP", "USD")
+	assert.NotNil(t, err)
+}
+
+func TestSumBalancesAndHoldings(t *testing.T) {
+	conv := StaticRateConverter{
+		Base:  "USD",
+		Rates: map[Currency]float64{"EUR": 0.5},
+	}
+
+	total, err := SumBalances(conv, "USD", []Account{
+		{AccountID: "a", Balances: AccountBalances{Current: 100, ISOCurrencyCode: "USD"}},
+		{AccountID: "b", Balances: AccountBalances{Current: 50, ISOCurrencyCode: "EUR"}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 200.0, total)
+
+	_, err = SumBalances(conv, "USD", []Account{
+		{AccountID: "c", Balances: AccountBalances{Current: 1, UnofficialCurrencyCode: "ETH"}},
+	})
+	assert.NotNil(t, err)
+
+	total, err = SumHoldings(conv, "EUR", []Holding{
+		{InstitutionValue: 10, ISOCurrencyCode: "USD"},
+		{InstitutionValue: 10, ISOCurrencyCode: "EUR"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 15.0, total)
+}
+
+func TestIsValidCode(t *testing.T) {
+	assert.True(t, IsValidCode("BTC"))
+	assert.True(t, IsValidCode(string(UnofficialCurrencyCodes.ZRX)))
+	assert.False(t, IsValidCode("USD"))
+	assert.False(t, IsValidCode(""))
+}
@@ -52,10 +52,21 @@ type createDepositSwitchRequest struct {
 }
 
 type createDepositSwitchResponse struct {
+	APIResponse
 	DepositSwitchID string `json:"deposit_switch_id"`
 }
 
+type CreateDepositSwitchOptions struct {
+	// IdempotencyKey deduplicates replayed calls through the client's
+	// IdempotencyStore.
+	IdempotencyKey string
+}
+
 func (c *Client) CreateDepositSwitch(targetAccountID string, targetAccessToken string) (resp createDepositSwitchResponse, err error) {
+	return c.CreateDepositSwitchWithOptions(targetAccountID, targetAccessToken, CreateDepositSwitchOptions{})
+}
+
+func (c *Client) CreateDepositSwitchWithOptions(targetAccountID string, targetAccessToken string, options CreateDepositSwitchOptions) (resp createDepositSwitchResponse, err error) {
 	if targetAccountID == "" {
 		return resp, errors.New("/deposit_switch/create - target account id must be specified")
 	}
@@ -73,7 +84,7 @@ func (c *Client) CreateDepositSwitch(targetAccountID string, targetAccessToken s
 		return resp, err
 	}
 
-	err = c.Call("/deposit_switch/create", jsonBody, &resp)
+	err = c.callIdempotent("/deposit_switch/create", options.IdempotencyKey, jsonBody, &resp)
 
 	return resp, err
 }
@@ -85,6 +96,7 @@ type createDepositSwitchTokenRequest struct {
 }
 
 type createDepositSwitchTokenResponse struct {
+	APIResponse
 	DepositSwitchToken               string `json:"deposit_switch_token"`
 	DepositSwitchTokenExpirationTime string `json:"deposit_switch_token_expiration_time"`
 }
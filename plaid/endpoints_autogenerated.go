@@ -0,0 +1,213 @@
+// NOTE - this file is auto-generated. DO NOT EDIT.
+
+package plaid
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// SandboxItemFireWebhookCode is the webhook code to fire.
+type SandboxItemFireWebhookCode string
+
+const (
+	sandboxItemFireWebhookCodeDefaultUpdate        SandboxItemFireWebhookCode = "DEFAULT_UPDATE"
+	sandboxItemFireWebhookCodeNewAccountsAvailable SandboxItemFireWebhookCode = "NEW_ACCOUNTS_AVAILABLE"
+)
+
+type sandboxItemFireWebhookCodes struct {
+	DefaultUpdate        SandboxItemFireWebhookCode
+	NewAccountsAvailable SandboxItemFireWebhookCode
+}
+
+var SandboxItemFireWebhookCodes sandboxItemFireWebhookCodes = sandboxItemFireWebhookCodes{
+	DefaultUpdate:        sandboxItemFireWebhookCodeDefaultUpdate,
+	NewAccountsAvailable: sandboxItemFireWebhookCodeNewAccountsAvailable,
+}
+
+// WebhookType is the type of a webhook.
+type WebhookType string
+
+const (
+	webhookTypeAuth                    WebhookType = "AUTH"
+	webhookTypeHoldings                WebhookType = "HOLDINGS"
+	webhookTypeInvestmentsTransactions WebhookType = "INVESTMENTS_TRANSACTIONS"
+	webhookTypeItem                    WebhookType = "ITEM"
+	webhookTypeLiabilities             WebhookType = "LIABILITIES"
+	webhookTypeTransactions            WebhookType = "TRANSACTIONS"
+)
+
+type webhookTypes struct {
+	Auth                    WebhookType
+	Holdings                WebhookType
+	InvestmentsTransactions WebhookType
+	Item                    WebhookType
+	Liabilities             WebhookType
+	Transactions            WebhookType
+}
+
+var WebhookTypes webhookTypes = webhookTypes{
+	Auth:                    webhookTypeAuth,
+	Holdings:                webhookTypeHoldings,
+	InvestmentsTransactions: webhookTypeInvestmentsTransactions,
+	Item:                    webhookTypeItem,
+	Liabilities:             webhookTypeLiabilities,
+	Transactions:            webhookTypeTransactions,
+}
+
+// Employer is an employer known to Plaid.
+type Employer struct {
+	EmployerID      string       `json:"employer_id"`
+	Name            string       `json:"name"`
+	Address         *AddressData `json:"address"`
+	ConfidenceScore float64      `json:"confidence_score"`
+}
+
+type searchEmployersRequest struct {
+	ClientID string   `json:"client_id"`
+	Secret   string   `json:"secret"`
+	Query    string   `json:"query"`
+	Products []string `json:"products"`
+}
+
+type SearchEmployersResponse struct {
+	APIResponse
+	Employers []Employer `json:"employers"`
+}
+
+// SearchEmployers searches Plaid's database of known employers, for use with
+// deposit switch.
+// See https://plaid.com/docs/api/employers/#employerssearch.
+func (c *Client) SearchEmployers(query string, products []string) (resp SearchEmployersResponse, err error) {
+	if query == "" {
+		return resp, errors.New("/employers/search - query must be specified")
+	}
+	if len(products) == 0 {
+		return resp, errors.New("/employers/search - products must be specified")
+	}
+
+	jsonBody, err := json.Marshal(searchEmployersRequest{
+		ClientID: c.clientID,
+		Secret:   c.secret,
+		Query:    query,
+		Products: products,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("/employers/search", jsonBody, &resp)
+	return resp, err
+}
+
+type getProcessorBalanceRequest struct {
+	ClientID       string `json:"client_id"`
+	Secret         string `json:"secret"`
+	ProcessorToken string `json:"processor_token"`
+}
+
+type GetProcessorBalanceResponse struct {
+	APIResponse
+	Account Account `json:"account"`
+}
+
+// GetProcessorBalance returns the real-time balance of the account a
+// processor token was created for.
+// See https://plaid.com/docs/api/processors/#processorbalanceget.
+func (c *Client) GetProcessorBalance(processorToken string) (resp GetProcessorBalanceResponse, err error) {
+	if processorToken == "" {
+		return resp, errors.New("/processor/balance/get - processor token must be specified")
+	}
+
+	jsonBody, err := json.Marshal(getProcessorBalanceRequest{
+		ClientID:       c.clientID,
+		Secret:         c.secret,
+		ProcessorToken: processorToken,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("/processor/balance/get", jsonBody, &resp)
+	return resp, err
+}
+
+type fireSandboxItemWebhookRequest struct {
+	ClientID    string                     `json:"client_id"`
+	Secret      string                     `json:"secret"`
+	AccessToken string                     `json:"access_token"`
+	WebhookType WebhookType                `json:"webhook_type,omitempty"`
+	WebhookCode SandboxItemFireWebhookCode `json:"webhook_code"`
+}
+
+type FireSandboxItemWebhookResponse struct {
+	APIResponse
+	WebhookFired bool `json:"webhook_fired"`
+}
+
+type FireSandboxItemWebhookOptions struct {
+	WebhookType WebhookType
+}
+
+// FireSandboxItemWebhook fires a webhook for an Item in the Sandbox
+// environment, so that webhook handling can be tested.
+// See https://plaid.com/docs/api/sandbox/#sandboxitemfire_webhook.
+func (c *Client) FireSandboxItemWebhook(accessToken string, webhookCode SandboxItemFireWebhookCode) (resp FireSandboxItemWebhookResponse, err error) {
+	return c.FireSandboxItemWebhookWithOptions(accessToken, webhookCode, FireSandboxItemWebhookOptions{})
+}
+
+func (c *Client) FireSandboxItemWebhookWithOptions(accessToken string, webhookCode SandboxItemFireWebhookCode, options FireSandboxItemWebhookOptions) (resp FireSandboxItemWebhookResponse, err error) {
+	if accessToken == "" {
+		return resp, errors.New("/sandbox/item/fire_webhook - access token must be specified")
+	}
+	if webhookCode == "" {
+		return resp, errors.New("/sandbox/item/fire_webhook - webhook code must be specified")
+	}
+
+	jsonBody, err := json.Marshal(fireSandboxItemWebhookRequest{
+		ClientID:    c.clientID,
+		Secret:      c.secret,
+		AccessToken: accessToken,
+		WebhookType: options.WebhookType,
+		WebhookCode: webhookCode,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("/sandbox/item/fire_webhook", jsonBody, &resp)
+	return resp, err
+}
+
+type createUserRequest struct {
+	ClientID     string `json:"client_id"`
+	Secret       string `json:"secret"`
+	ClientUserID string `json:"client_user_id"`
+}
+
+type CreateUserResponse struct {
+	APIResponse
+	UserToken string `json:"user_token"`
+	UserID    string `json:"user_id"`
+}
+
+// CreateUser creates a user token, which is used by products that act on a
+// user rather than an Item.
+// See https://plaid.com/docs/api/users/#usercreate.
+func (c *Client) CreateUser(clientUserID string) (resp CreateUserResponse, err error) {
+	if clientUserID == "" {
+		return resp, errors.New("/user/create - client user id must be specified")
+	}
+
+	jsonBody, err := json.Marshal(createUserRequest{
+		ClientID:     c.clientID,
+		Secret:       c.secret,
+		ClientUserID: clientUserID,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("/user/create", jsonBody, &resp)
+	return resp, err
+}
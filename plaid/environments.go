@@ -1,5 +1,7 @@
 package plaid
 
+import "fmt"
+
 type Environment string
 
 const (
@@ -22,3 +24,21 @@ func (c Environment) Valid() bool {
 	}
 	return false
 }
+
+// environmentNames are the names of the environments in configuration files
+// and command line flags.
+var environmentNames = map[string]Environment{
+	"sandbox":     Sandbox,
+	"development": Development,
+	"production":  Production,
+}
+
+// ParseEnvironment returns the environment with the given name: "sandbox",
+// "development" or "production".
+func ParseEnvironment(name string) (Environment, error) {
+	environment, ok := environmentNames[name]
+	if !ok {
+		return "", fmt.Errorf("environment - %q is not sandbox, development or production", name)
+	}
+	return environment, nil
+}
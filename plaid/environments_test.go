@@ -0,0 +1,20 @@
+package plaid
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestParseEnvironment(t *testing.T) {
+	environment, err := ParseEnvironment("sandbox")
+	assert.Nil(t, err)
+	assert.Equal(t, Sandbox, environment)
+
+	environment, err = ParseEnvironment("production")
+	assert.Nil(t, err)
+	assert.Equal(t, Production, environment)
+
+	_, err = ParseEnvironment("Sandbox")
+	assert.NotNil(t, err)
+}
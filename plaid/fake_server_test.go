@@ -0,0 +1,92 @@
+package plaid
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeHandler receives the decoded JSON request body of a call and returns
+// the status code and value to encode as the response. A []byte value is
+// written as is, as a PDF.
+type fakeHandler func(body map[string]interface{}) (int, interface{})
+
+// fakeServer is an in-process stand-in for the Plaid API used by tests that
+// must not depend on the sandbox.
+type fakeServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]fakeHandler
+	calls    map[string]int
+	versions map[string]string
+}
+
+func newFakeServer(t *testing.T) (*fakeServer, *Client) {
+	s := &fakeServer{
+		handlers: map[string]fakeHandler{},
+		calls:    map[string]int{},
+		versions: map[string]string{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+
+	client, err := NewClient(ClientOptions{
+		ClientID:    "client-id",
+		Secret:      "secret",
+		Environment: Environment(s.URL),
+		HTTPClient:  s.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, client
+}
+
+func (s *fakeServer) handle(endpoint string, h fakeHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[endpoint] = h
+}
+
+func (s *fakeServer) callCount(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[endpoint]
+}
+
+// apiVersion returns the Plaid-Version header of the last call to endpoint.
+func (s *fakeServer) apiVersion(endpoint string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.versions[endpoint]
+}
+
+func (s *fakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	h, ok := s.handlers[r.URL.Path]
+	s.calls[r.URL.Path]++
+	s.versions[r.URL.Path] = r.Header.Get("Plaid-Version")
+	s.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(Error{ErrorType: "INVALID_REQUEST", ErrorCode: "NOT_FOUND"})
+		return
+	}
+
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	status, resp := h(body)
+	if raw, ok := resp.([]byte); ok {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.WriteHeader(status)
+		_, _ = w.Write(raw)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,20 @@
+module github.com/perchcredit/plaid-go/plaid/gqlgen
+
+go 1.21
+
+require (
+	github.com/99designs/gqlgen v0.17.24
+	github.com/perchcredit/plaid-go/v6 v6.0.0
+	github.com/stretchr/testify v1.9.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/vektah/gqlparser/v2 v2.5.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// Builds inside this repository use the plaid package next to them. Modules
+// depending on plaid/gqlgen ignore this and use the version required above.
+replace github.com/perchcredit/plaid-go/v6 => ../..
@@ -0,0 +1,61 @@
+// Package gqlgen provides gqlgen marshalers for plaid types so they can be
+// bound directly as GraphQL scalars. It lives outside the core plaid package
+// so that services which don't use gqlgen don't inherit the dependency.
+//
+// Bind the scalars in gqlgen.yml, for example:
+//
+//	models:
+//	  Currency:
+//	    model: github.com/perchcredit/plaid-go/plaid/gqlgen.Currency
+//	  UnofficialCurrencyCode:
+//	    model: github.com/perchcredit/plaid-go/plaid/gqlgen.UnofficialCurrencyCode
+package gqlgen
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/perchcredit/plaid-go/v6/plaid"
+)
+
+// MarshalCurrency converts a plaid.Currency to a graphql string.
+func MarshalCurrency(c plaid.Currency) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(string(c)))
+	})
+}
+
+// UnmarshalCurrency converts a graphql string into a plaid.Currency.
+func UnmarshalCurrency(v interface{}) (plaid.Currency, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%T is not a currency code", v)
+	}
+	if !plaid.Currency(s).Valid() {
+		return "", fmt.Errorf("%q is not a valid currency code", s)
+	}
+	return plaid.Currency(s), nil
+}
+
+// MarshalUnofficialCurrencyCode converts one of plaid.UnofficialCurrencyCodes
+// to a graphql string.
+func MarshalUnofficialCurrencyCode(c plaid.Currency) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(string(c)))
+	})
+}
+
+// UnmarshalUnofficialCurrencyCode converts a graphql string into one of
+// plaid.UnofficialCurrencyCodes.
+func UnmarshalUnofficialCurrencyCode(v interface{}) (plaid.Currency, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%T is not an unofficial currency code", v)
+	}
+	if !plaid.IsValidCode(s) {
+		return "", fmt.Errorf("%q is not a valid unofficial currency code", s)
+	}
+	return plaid.Currency(s), nil
+}
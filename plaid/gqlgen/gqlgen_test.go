@@ -0,0 +1,38 @@
+package gqlgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestCurrency(t *testing.T) {
+	var buf bytes.Buffer
+	MarshalCurrency("USD").MarshalGQL(&buf)
+	assert.Equal(t, `"USD"`, buf.String())
+
+	c, err := UnmarshalCurrency("EUR")
+	assert.Nil(t, err)
+	assert.Equal(t, plaid.Currency("EUR"), c)
+
+	_, err = UnmarshalCurrency("NOPE")
+	assert.NotNil(t, err)
+
+	_, err = UnmarshalCurrency(42)
+	assert.NotNil(t, err)
+}
+
+func TestUnofficialCurrencyCode(t *testing.T) {
+	var buf bytes.Buffer
+	MarshalUnofficialCurrencyCode(plaid.UnofficialCurrencyCodes.BTC).MarshalGQL(&buf)
+	assert.Equal(t, `"BTC"`, buf.String())
+
+	c, err := UnmarshalUnofficialCurrencyCode("DOGE")
+	assert.Nil(t, err)
+	assert.Equal(t, plaid.UnofficialCurrencyCodes.DOGE, c)
+
+	_, err = UnmarshalUnofficialCurrencyCode("USD")
+	assert.NotNil(t, err)
+}
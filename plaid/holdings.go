@@ -6,21 +6,21 @@ import (
 )
 
 type Security struct {
-	SecurityID             string  `json:"security_id"`
-	CUSIP                  string  `json:"cusip"`
-	SEDOL                  string  `json:"sedol"`
-	ISIN                   string  `json:"isin"`
-	InstitutionSecurityID  string  `json:"institution_security_id"`
-	InstitutionID          string  `json:"institution_id"`
-	ProxySecurityID        string  `json:"proxy_security_id"`
-	Name                   string  `json:"name"`
-	TickerSymbol           string  `json:"ticker_symbol"`
-	IsCashEquivalent       bool    `json:"is_cash_equivalent"`
-	Type                   string  `json:"type"`
-	ClosePrice             float64 `json:"close_price"`
-	ClosePriceAsOf         string  `json:"close_price_as_of"`
-	ISOCurrencyCode        string  `json:"iso_currency_code"`
-	UnofficialCurrencyCode string  `json:"unofficial_currency_code"`
+	SecurityID             string   `json:"security_id"`
+	CUSIP                  string   `json:"cusip"`
+	SEDOL                  string   `json:"sedol"`
+	ISIN                   string   `json:"isin"`
+	InstitutionSecurityID  string   `json:"institution_security_id"`
+	InstitutionID          string   `json:"institution_id"`
+	ProxySecurityID        string   `json:"proxy_security_id"`
+	Name                   string   `json:"name"`
+	TickerSymbol           string   `json:"ticker_symbol"`
+	IsCashEquivalent       bool     `json:"is_cash_equivalent"`
+	Type                   string   `json:"type"`
+	ClosePrice             float64  `json:"close_price"`
+	ClosePriceAsOf         string   `json:"close_price_as_of"`
+	ISOCurrencyCode        Currency `json:"iso_currency_code"`
+	UnofficialCurrencyCode Currency `json:"unofficial_currency_code"`
 }
 
 type Holding struct {
@@ -33,8 +33,8 @@ type Holding struct {
 	InstitutionPriceAsOf string  `json:"institution_price_as_of"`
 	CostBasis            float64 `json:"cost_basis"`
 
-	ISOCurrencyCode        string `json:"iso_currency_code"`
-	UnofficialCurrencyCode string `json:"unofficial_currency_code"`
+	ISOCurrencyCode        Currency `json:"iso_currency_code"`
+	UnofficialCurrencyCode Currency `json:"unofficial_currency_code"`
 }
 
 type getHoldingsRequest struct {
@@ -45,7 +45,7 @@ type getHoldingsRequest struct {
 }
 
 type GetHoldingsOptions struct {
-	AccountIDs []string `json:"account_ids"`
+	AccountIDs []string `json:"account_ids,omitempty"`
 }
 
 type GetHoldingsResponse struct {
@@ -0,0 +1,178 @@
+package plaid
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// IdempotencyStore persists the responses of mutating calls made with an
+// idempotency key, so that a call replayed with the same key returns the
+// original response instead of, say, creating a second payment. Implementations
+// must be safe for concurrent use; entries should be shared by every process
+// that may replay a call.
+type IdempotencyStore interface {
+	// Get returns the entry stored under key, if any has been stored and has
+	// not expired.
+	Get(key string) (entry []byte, ok bool, err error)
+	// Put stores an entry under key until ttl has elapsed.
+	Put(key string, entry []byte, ttl time.Duration) error
+}
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is replayed with
+// a request that differs from the one it was first used with.
+var ErrIdempotencyKeyReused = errors.New("idempotency key - key was already used for a different request")
+
+// DefaultIdempotencyTTL is how long responses are kept when
+// ClientOptions.IdempotencyTTL is not set.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+type memoryIdempotencyEntry struct {
+	entry   []byte
+	expires time.Time
+}
+
+// MemoryIdempotencyStore is an IdempotencyStore that keeps entries in memory.
+// It only deduplicates calls made within a single process.
+type MemoryIdempotencyStore struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+}
+
+// NewMemoryIdempotencyStore creates an empty MemoryIdempotencyStore.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		now:     time.Now,
+		entries: map[string]memoryIdempotencyEntry{},
+	}
+}
+
+// Get implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Get(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !s.now().Before(e.expires) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	return e.entry, true, nil
+}
+
+// Put implements IdempotencyStore. Expired entries are removed as new ones
+// are stored.
+func (s *MemoryIdempotencyStore) Put(key string, entry []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for k, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = memoryIdempotencyEntry{entry: entry, expires: now.Add(ttl)}
+	return nil
+}
+
+// idempotencyEntry is what the client stores under an idempotency key.
+type idempotencyEntry struct {
+	RequestHash string          `json:"request_hash"`
+	Response    json.RawMessage `json:"response"`
+}
+
+// keyedLocks serializes calls sharing an idempotency key within a process.
+type keyedLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	refs int
+}
+
+func (l *keyedLocks) lock(key string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*keyedLock{}
+	}
+	k, ok := l.locks[key]
+	if !ok {
+		k = &keyedLock{}
+		l.locks[key] = k
+	}
+	k.refs++
+	l.mu.Unlock()
+
+	k.Lock()
+	return func() {
+		k.Unlock()
+		l.mu.Lock()
+		k.refs--
+		if k.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// callIdempotent is like Call for mutating endpoints. When key is set and the
+// client has an IdempotencyStore, a successful response is stored under the
+// key and returned again, without calling Plaid, for any call replaying the
+// key within the TTL. Failed calls are not stored, so they can be retried.
+// Replaying a key with a different request fails with ErrIdempotencyKeyReused.
+//
+// The deduplication is done by the client alone and only lasts as long as the
+// TTL; callIdempotent does not send key to Plaid. Of the endpoints it is used
+// for, only /payment_initiation/consent/payment/execute takes an
+// idempotency_key, which ExecutePaymentConsent puts in the request body.
+func (c *Client) callIdempotent(endpoint, key string, body []byte, v interface{}) error {
+	if key == "" || c.idempotencyStore == nil {
+		return c.Call(endpoint, body, v)
+	}
+
+	storeKey := endpoint + " " + key
+	unlock := c.idempotencyLocks.lock(storeKey)
+	defer unlock()
+
+	sum := sha256.Sum256(body)
+	requestHash := hex.EncodeToString(sum[:])
+
+	stored, ok, err := c.idempotencyStore.Get(storeKey)
+	if err != nil {
+		return err
+	}
+	if ok {
+		var entry idempotencyEntry
+		if err := json.Unmarshal(stored, &entry); err != nil {
+			return err
+		}
+		if entry.RequestHash != requestHash {
+			return ErrIdempotencyKeyReused
+		}
+		return json.Unmarshal(entry.Response, v)
+	}
+
+	if err := c.Call(endpoint, body, v); err != nil {
+		return err
+	}
+
+	// The call has succeeded at this point, so failing to store its response
+	// must not make the caller believe it should be retried.
+	response, err := json.Marshal(v)
+	if err == nil {
+		entry, err := json.Marshal(idempotencyEntry{RequestHash: requestHash, Response: response})
+		if err == nil {
+			_ = c.idempotencyStore.Put(storeKey, entry, c.idempotencyTTL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package plaid
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func newIdempotentFakeServer(t *testing.T) (*fakeServer, *Client, *MemoryIdempotencyStore) {
+	server, client := newFakeServer(t)
+	store := NewMemoryIdempotencyStore()
+	client.idempotencyStore = store
+	return server, client, store
+}
+
+func TestCreatePaymentIdempotency(t *testing.T) {
+	server, client, _ := newIdempotentFakeServer(t)
+	var mu sync.Mutex
+	created := 0
+	server.handle("/payment_initiation/payment/create", func(body map[string]interface{}) (int, interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		created++
+		return http.StatusOK, CreatePaymentResponse{
+			PaymentID: "payment-" + string(rune('0'+created)),
+			Status:    PaymentStatuses.InputNeeded,
+		}
+	})
+
+	amount := PaymentAmount{Currency: "GBP", Value: 12.34}
+	options := CreatePaymentOptions{IdempotencyKey: "order-1"}
+
+	// Concurrent replays of the same key are serialized and deduplicated.
+	var wg sync.WaitGroup
+	ids := make([]string, 5)
+	errs := make([]error, len(ids))
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := client.CreatePaymentWithOptions("recipient-1", "ref", amount, options)
+			ids[i], errs[i] = resp.PaymentID, err
+		}(i)
+	}
+	wg.Wait()
+	for i, id := range ids {
+		assert.Nil(t, errs[i])
+		assert.Equal(t, "payment-1", id)
+	}
+	assert.Equal(t, 1, server.callCount("/payment_initiation/payment/create"))
+
+	// A different request with the same key is rejected.
+	_, err := client.CreatePaymentWithOptions("recipient-1", "ref", PaymentAmount{Currency: "GBP", Value: 99}, options)
+	assert.Equal(t, ErrIdempotencyKeyReused, err)
+
+	// Other keys, and calls without a key, reach Plaid.
+	resp, err := client.CreatePaymentWithOptions("recipient-1", "ref", amount, CreatePaymentOptions{IdempotencyKey: "order-2"})
+	assert.Nil(t, err)
+	assert.Equal(t, "payment-2", resp.PaymentID)
+	resp, err = client.CreatePayment("recipient-1", "ref", amount, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "payment-3", resp.PaymentID)
+}
+
+func TestIdempotencyDoesNotStoreFailures(t *testing.T) {
+	server, client, _ := newIdempotentFakeServer(t)
+	fail := true
+	server.handle("/processor/token/create", func(body map[string]interface{}) (int, interface{}) {
+		if fail {
+			return http.StatusInternalServerError, Error{ErrorType: "API_ERROR", ErrorCode: "INTERNAL_SERVER_ERROR"}
+		}
+		return http.StatusOK, ProcessorTokenResponse{ProcessorToken: "processor-token"}
+	})
+
+	options := ProcessorTokenOptions{IdempotencyKey: "account-1"}
+	_, err := client.CreateProcessorTokenWithOptions("access-token", "account-1", "dwolla", options)
+	assert.NotNil(t, err)
+
+	fail = false
+	for i := 0; i < 2; i++ {
+		resp, err := client.CreateProcessorTokenWithOptions("access-token", "account-1", "dwolla", options)
+		assert.Nil(t, err)
+		assert.Equal(t, "processor-token", resp.ProcessorToken)
+	}
+	assert.Equal(t, 2, server.callCount("/processor/token/create"))
+}
+
+func TestIdempotencyKeysAreScopedByEndpoint(t *testing.T) {
+	server, client, _ := newIdempotentFakeServer(t)
+	server.handle("/payment_initiation/recipient/create", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, CreatePaymentRecipientResponse{RecipientID: "recipient-1"}
+	})
+	server.handle("/deposit_switch/create", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, createDepositSwitchResponse{DepositSwitchID: "switch-1"}
+	})
+
+	for i := 0; i < 2; i++ {
+		recipient, err := client.CreatePaymentRecipient("John Doe", OptionalRecipientCreateParams{IdempotencyKey: "key"})
+		assert.Nil(t, err)
+		assert.Equal(t, "recipient-1", recipient.RecipientID)
+
+		depositSwitch, err := client.CreateDepositSwitchWithOptions("account-1", "access-token", CreateDepositSwitchOptions{IdempotencyKey: "key"})
+		assert.Nil(t, err)
+		assert.Equal(t, "switch-1", depositSwitch.DepositSwitchID)
+	}
+	assert.Equal(t, 1, server.callCount("/payment_initiation/recipient/create"))
+	assert.Equal(t, 1, server.callCount("/deposit_switch/create"))
+}
+
+func TestMemoryIdempotencyStoreExpiry(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	now := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	assert.Nil(t, store.Put("a", []byte("1"), time.Hour))
+	entry, ok, err := store.Get("a")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), entry)
+
+	now = now.Add(time.Hour)
+	_, ok, _ = store.Get("a")
+	assert.False(t, ok)
+
+	assert.Nil(t, store.Put("b", []byte("2"), time.Minute))
+	now = now.Add(time.Minute)
+	assert.Nil(t, store.Put("c", []byte("3"), time.Minute))
+	assert.Len(t, store.entries, 1)
+}
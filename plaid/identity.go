@@ -75,3 +75,97 @@ func (c *Client) GetIdentity(accessToken string) (resp GetIdentityResponse, err
 	err = c.Call("/identity/get", jsonBody, &resp)
 	return resp, err
 }
+
+// IdentityMatchUser holds the identity data on file for a user, to be compared
+// against the data held by the financial institution. Only the fields that
+// are set are scored.
+type IdentityMatchUser struct {
+	LegalName    string       `json:"legal_name,omitempty"`
+	PhoneNumber  string       `json:"phone_number,omitempty"`
+	EmailAddress string       `json:"email_address,omitempty"`
+	Address      *AddressData `json:"address,omitempty"`
+}
+
+type getIdentityMatchRequestOptions struct {
+	AccountIDs []string `json:"account_ids,omitempty"`
+}
+
+type getIdentityMatchRequest struct {
+	ClientID    string                          `json:"client_id"`
+	Secret      string                          `json:"secret"`
+	AccessToken string                          `json:"access_token"`
+	User        IdentityMatchUser               `json:"user"`
+	Options     *getIdentityMatchRequestOptions `json:"options,omitempty"`
+}
+
+// IdentityMatchScore is a score from 0 to 100, where 100 is a perfect match.
+type IdentityMatchScore struct {
+	Score int `json:"score"`
+}
+
+type NameMatchScore struct {
+	Score                      int  `json:"score"`
+	IsFirstNameOrLastNameMatch bool `json:"is_first_name_or_last_name_match"`
+	IsNicknameMatch            bool `json:"is_nickname_match"`
+	IsBusinessNameDetected     bool `json:"is_business_name_detected"`
+}
+
+type AddressMatchScore struct {
+	Score             int  `json:"score"`
+	IsPostalCodeMatch bool `json:"is_postal_code_match"`
+}
+
+// AccountWithIdentityMatch is an account with the match scores of each field
+// of the IdentityMatchUser. A score is nil when the field was not provided or
+// the institution holds no data for it.
+type AccountWithIdentityMatch struct {
+	Account
+	LegalName    *NameMatchScore     `json:"legal_name"`
+	PhoneNumber  *IdentityMatchScore `json:"phone_number"`
+	EmailAddress *IdentityMatchScore `json:"email_address"`
+	Address      *AddressMatchScore  `json:"address"`
+}
+
+type GetIdentityMatchResponse struct {
+	APIResponse
+	Accounts []AccountWithIdentityMatch `json:"accounts"`
+	Item     Item                       `json:"item"`
+}
+
+type GetIdentityMatchOptions struct {
+	AccountIDs []string
+}
+
+// GetIdentityMatchWithOptions scores how closely the identity data on file
+// with the financial institution matches the given user.
+// See https://plaid.com/docs/api/products/identity/#identitymatch.
+func (c *Client) GetIdentityMatchWithOptions(accessToken string, user IdentityMatchUser, options GetIdentityMatchOptions) (resp GetIdentityMatchResponse, err error) {
+	if accessToken == "" {
+		return resp, errors.New("/identity/match - access token must be specified")
+	}
+
+	req := getIdentityMatchRequest{
+		ClientID:    c.clientID,
+		Secret:      c.secret,
+		AccessToken: accessToken,
+		User:        user,
+	}
+	if len(options.AccountIDs) > 0 {
+		req.Options = &getIdentityMatchRequestOptions{AccountIDs: options.AccountIDs}
+	}
+
+	jsonBody, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("/identity/match", jsonBody, &resp)
+	return resp, err
+}
+
+// GetIdentityMatch scores how closely the identity data on file with the
+// financial institution matches the given user.
+// See https://plaid.com/docs/api/products/identity/#identitymatch.
+func (c *Client) GetIdentityMatch(accessToken string, user IdentityMatchUser) (resp GetIdentityMatchResponse, err error) {
+	return c.GetIdentityMatchWithOptions(accessToken, user, GetIdentityMatchOptions{})
+}
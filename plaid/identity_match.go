@@ -0,0 +1,420 @@
+package plaid
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// FieldMatch is the result of comparing one field of two identities.
+type FieldMatch struct {
+	// Score ranges from 0 to 100, where 100 is a perfect match.
+	Score int
+	// Compared is false when either identity has no usable data for the
+	// field, in which case Score is zero.
+	Compared bool
+}
+
+// MatchResult holds the per-field scores computed by MatchIdentity.
+type MatchResult struct {
+	Name    FieldMatch
+	Email   FieldMatch
+	Phone   FieldMatch
+	Address FieldMatch
+}
+
+func (r MatchResult) fields() []FieldMatch {
+	return []FieldMatch{r.Name, r.Email, r.Phone, r.Address}
+}
+
+// Score returns the average score of the fields that could be compared, or
+// zero if none could.
+func (r MatchResult) Score() int {
+	total, count := 0, 0
+	for _, f := range r.fields() {
+		if f.Compared {
+			total += f.Score
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return int(math.Round(float64(total) / float64(count)))
+}
+
+// Passes reports whether the names could be compared and every compared
+// field scored at least min. It is meant to gate actions, such as funding
+// from an account, on the account owner being the expected person.
+func (r MatchResult) Passes(min int) bool {
+	if !r.Name.Compared {
+		return false
+	}
+	for _, f := range r.fields() {
+		if f.Compared && f.Score < min {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchIdentity scores how closely the identity data we hold for a customer
+// matches an identity returned by /identity/get. Each field is scored as the
+// best match between any of the values on either side.
+//
+// Names are compared regardless of word order, titles and common nicknames;
+// emails ignore case, "+" tags and, for Gmail, dots; phone numbers are
+// compared in E.164 form; and street addresses are compared after expanding
+// common abbreviations. For a server side comparison, see GetIdentityMatch.
+func MatchIdentity(ours, theirs Identity) MatchResult {
+	var result MatchResult
+
+	for _, a := range ours.Names {
+		for _, b := range theirs.Names {
+			result.Name = best(result.Name, scoreNames(a, b))
+		}
+	}
+	for _, a := range ours.Emails {
+		for _, b := range theirs.Emails {
+			result.Email = best(result.Email, scoreEmails(a.Data, b.Data))
+		}
+	}
+	for _, a := range ours.PhoneNumbers {
+		for _, b := range theirs.PhoneNumbers {
+			result.Phone = best(result.Phone, scorePhoneNumbers(a.Data, b.Data))
+		}
+	}
+	for _, a := range ours.Addresses {
+		for _, b := range theirs.Addresses {
+			result.Address = best(result.Address, scoreAddresses(a.Data, b.Data))
+		}
+	}
+	return result
+}
+
+func best(a, b FieldMatch) FieldMatch {
+	if !a.Compared || (b.Compared && b.Score > a.Score) {
+		return b
+	}
+	return a
+}
+
+func percent(f float64) int {
+	return int(math.Round(math.Max(0, math.Min(1, f)) * 100))
+}
+
+// tokenize lower-cases s and splits it into words, dropping punctuation.
+func tokenize(s string) []string {
+	s = strings.ReplaceAll(strings.ToLower(s), "'", "")
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '#'
+	})
+}
+
+// matchTokens pairs every token of a with the most similar unused token of b
+// and returns the sum of the similarities.
+func matchTokens(a, b []string, similarity func(a, b string) float64) float64 {
+	used := make([]bool, len(b))
+	total := 0.0
+	for _, x := range a {
+		bestIndex, bestScore := -1, 0.0
+		for j, y := range b {
+			if used[j] {
+				continue
+			}
+			if s := similarity(x, y); s > bestScore {
+				bestIndex, bestScore = j, s
+			}
+		}
+		if bestIndex >= 0 {
+			used[bestIndex] = true
+			total += bestScore
+		}
+	}
+	return total
+}
+
+// editSimilarity returns 1 minus the edit distance of a and b divided by the
+// length of the longer string. Swapping two adjacent characters counts as a
+// single edit.
+func editSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return 1 - float64(d[len(ra)][len(rb)])/float64(max(len(ra), len(rb)))
+}
+
+// nameAffixes are titles and suffixes ignored when comparing names.
+var nameAffixes = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "miss": true, "mx": true, "dr": true,
+	"prof": true, "jr": true, "sr": true, "ii": true, "iii": true, "iv": true,
+	"phd": true, "md": true, "esq": true,
+}
+
+// nicknames maps common English nicknames to the given name they are short
+// for.
+var nicknames = map[string]string{
+	"abby": "abigail", "alex": "alexander", "andy": "andrew", "drew": "andrew",
+	"tony": "anthony", "ben": "benjamin", "benny": "benjamin",
+	"chuck": "charles", "charlie": "charles", "chris": "christopher",
+	"cindy": "cynthia", "dan": "daniel", "danny": "daniel", "dave": "david",
+	"deb": "deborah", "debbie": "deborah", "don": "donald", "ed": "edward",
+	"eddie": "edward", "ted": "edward", "liz": "elizabeth", "lizzie": "elizabeth",
+	"beth": "elizabeth", "betty": "elizabeth", "eliza": "elizabeth",
+	"fred": "frederick", "greg": "gregory", "hank": "henry", "harry": "henry",
+	"jim": "james", "jimmy": "james", "jamie": "james", "jeff": "jeffrey",
+	"jen": "jennifer", "jenny": "jennifer", "jerry": "gerald", "jon": "john",
+	"johnny": "john", "jack": "john", "joe": "joseph", "joey": "joseph",
+	"kate": "katherine", "katie": "katherine", "kathy": "katherine",
+	"cathy": "katherine", "catherine": "katherine", "ken": "kenneth",
+	"larry": "lawrence", "mandy": "amanda", "maggie": "margaret",
+	"meg": "margaret", "peggy": "margaret", "matt": "matthew", "mike": "michael",
+	"mikey": "michael", "nick": "nicholas", "pam": "pamela", "pat": "patricia",
+	"patty": "patricia", "trish": "patricia", "pete": "peter", "phil": "philip",
+	"becky": "rebecca", "rick": "richard", "ricky": "richard", "dick": "richard",
+	"bob": "robert", "bobby": "robert", "rob": "robert", "robbie": "robert",
+	"ron": "ronald", "sam": "samuel", "sandy": "sandra", "steve": "steven",
+	"stephen": "steven", "sue": "susan", "susie": "susan", "tom": "thomas",
+	"tommy": "thomas", "tim": "timothy", "vicky": "victoria", "bill": "william",
+	"billy": "william", "will": "william", "willy": "william",
+}
+
+func nameTokens(name string) []string {
+	var tokens []string
+	for _, t := range tokenize(name) {
+		if nameAffixes[t] {
+			continue
+		}
+		if canonical, ok := nicknames[t]; ok {
+			t = canonical
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
+func nameTokenSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	// An initial matches the names it abbreviates.
+	if (len(a) == 1 || len(b) == 1) && a[0] == b[0] {
+		return 0.75
+	}
+	if s := editSimilarity(a, b); s >= 0.75 {
+		return s
+	}
+	return 0
+}
+
+// scoreNames compares two names word by word, ignoring their order. Words
+// present in only one of the names, such as a middle name, cost a small
+// penalty; a single matching word is never enough for a high score.
+func scoreNames(a, b string) FieldMatch {
+	ta, tb := nameTokens(a), nameTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return FieldMatch{}
+	}
+	if len(ta) > len(tb) {
+		ta, tb = tb, ta
+	}
+	matched := matchTokens(ta, tb, nameTokenSimilarity)
+	score := matched/float64(max(len(ta), 2)) - 0.05*float64(len(tb)-len(ta))
+	return FieldMatch{Score: percent(score), Compared: true}
+}
+
+// normalizeEmail lower-cases an email address, drops any "+" tag and, for
+// Gmail addresses, the dots in the local part.
+func normalizeEmail(s string) (local, domain string) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	at := strings.LastIndex(s, "@")
+	if at <= 0 || at == len(s)-1 {
+		return "", ""
+	}
+	local, domain = s[:at], s[at+1:]
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		domain = "gmail.com"
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	return local, domain
+}
+
+func scoreEmails(a, b string) FieldMatch {
+	localA, domainA := normalizeEmail(a)
+	localB, domainB := normalizeEmail(b)
+	if localA == "" || localB == "" {
+		return FieldMatch{}
+	}
+	switch {
+	case localA == localB && domainA == domainB:
+		return FieldMatch{Score: 100, Compared: true}
+	case localA == localB:
+		return FieldMatch{Score: 50, Compared: true}
+	}
+	return FieldMatch{Compared: true}
+}
+
+// NormalizePhoneNumber returns s in E.164 form, e.g. "+14155550123". Numbers
+// without a country code are assumed to be North American. Extensions are
+// dropped. An empty string is returned if s is not a valid phone number.
+func NormalizePhoneNumber(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, sep := range []string{"ext", "x", ";"} {
+		if i := strings.Index(s, sep); i >= 0 {
+			s = s[:i]
+		}
+	}
+
+	international := strings.HasPrefix(s, "+")
+	var digits strings.Builder
+	for _, r := range strings.TrimPrefix(s, "+") {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case strings.ContainsRune(" -().", r):
+		default:
+			return ""
+		}
+	}
+	d := digits.String()
+
+	if !international {
+		switch {
+		case strings.HasPrefix(d, "011"):
+			international, d = true, d[3:]
+		case strings.HasPrefix(d, "00"):
+			international, d = true, d[2:]
+		case len(d) == 10:
+			d = "1" + d
+		case len(d) == 11 && d[0] == '1':
+		default:
+			return ""
+		}
+	}
+	if len(d) < 8 || len(d) > 15 || d[0] == '0' {
+		return ""
+	}
+	return "+" + d
+}
+
+func scorePhoneNumbers(a, b string) FieldMatch {
+	na, nb := NormalizePhoneNumber(a), NormalizePhoneNumber(b)
+	if na == "" || nb == "" {
+		return FieldMatch{}
+	}
+	if na == nb {
+		return FieldMatch{Score: 100, Compared: true}
+	}
+	return FieldMatch{Compared: true}
+}
+
+// streetAbbreviations maps the words of a street address to the USPS
+// abbreviations they are compared as.
+var streetAbbreviations = map[string]string{
+	"street": "st", "avenue": "ave", "av": "ave", "road": "rd",
+	"boulevard": "blvd", "drive": "dr", "lane": "ln", "court": "ct",
+	"place": "pl", "circle": "cir", "terrace": "ter", "highway": "hwy",
+	"parkway": "pkwy", "square": "sq", "trail": "trl", "way": "wy",
+	"expressway": "expy", "freeway": "fwy", "center": "ctr", "plaza": "plz",
+	"north": "n", "south": "s", "east": "e", "west": "w", "northeast": "ne",
+	"northwest": "nw", "southeast": "se", "southwest": "sw",
+	"apartment": "unit", "apt": "unit", "suite": "unit", "ste": "unit",
+	"#": "unit", "floor": "fl", "building": "bldg",
+}
+
+func streetTokens(street string) []string {
+	// Separate "#" from a following unit number, as in "#4".
+	tokens := tokenize(strings.ReplaceAll(street, "#", " # "))
+	for i, t := range tokens {
+		if abbreviation, ok := streetAbbreviations[t]; ok {
+			tokens[i] = abbreviation
+		}
+	}
+	return tokens
+}
+
+func streetTokenSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	// House and unit numbers must match exactly.
+	if unicode.IsDigit(rune(a[0])) || unicode.IsDigit(rune(b[0])) {
+		return 0
+	}
+	if s := editSimilarity(a, b); s >= 0.8 {
+		return s
+	}
+	return 0
+}
+
+func normalizePostalCode(s string) string {
+	s = strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(s))
+	// Compare US ZIP+4 codes by their five digit ZIP code.
+	if len(s) == 9 && isDigits(s) {
+		return s[:5]
+	}
+	return s
+}
+
+// scoreAddresses weighs the street at 60%, the postal code at 25%, the city
+// at 10% and the region at 5%. Components missing from either address are
+// left out. Streets with different house numbers score zero, and addresses in
+// different countries never match.
+func scoreAddresses(a, b AddressData) FieldMatch {
+	ta, tb := streetTokens(a.Street), streetTokens(b.Street)
+	if len(ta) == 0 || len(tb) == 0 {
+		return FieldMatch{}
+	}
+	if a.Country != "" && b.Country != "" && !strings.EqualFold(a.Country, b.Country) {
+		return FieldMatch{Compared: true}
+	}
+
+	score := 0.0
+	if ta[0] == tb[0] || !unicode.IsDigit(rune(ta[0][0])) || !unicode.IsDigit(rune(tb[0][0])) {
+		score = 0.6 * 2 * matchTokens(ta, tb, streetTokenSimilarity) / float64(len(ta)+len(tb))
+	}
+	weight := 0.6
+	compare := func(w float64, x, y string, equal func(x, y string) bool) {
+		if x == "" || y == "" {
+			return
+		}
+		weight += w
+		if equal(x, y) {
+			score += w
+		}
+	}
+	compare(0.25, a.PostalCode, b.PostalCode, func(x, y string) bool {
+		return normalizePostalCode(x) == normalizePostalCode(y)
+	})
+	compare(0.1, a.City, b.City, func(x, y string) bool {
+		return strings.Join(tokenize(x), " ") == strings.Join(tokenize(y), " ")
+	})
+	compare(0.05, a.Region, b.Region, func(x, y string) bool {
+		return strings.EqualFold(strings.TrimSpace(x), strings.TrimSpace(y))
+	})
+	return FieldMatch{Score: percent(score / weight), Compared: true}
+}
@@ -0,0 +1,191 @@
+package plaid
+
+import (
+	"net/http"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestScoreNames(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		minScore int
+		maxScore int
+	}{
+		{"John Doe", "John Doe", 100, 100},
+		{"John Doe", "DOE, JOHN", 100, 100},
+		{"Robert Smith", "Bob Smith", 100, 100},
+		{"Mr. William Brown Jr.", "Bill Brown", 100, 100},
+		{"John Quincy Doe", "John Doe", 90, 99},
+		{"J. Doe", "John Doe", 80, 95},
+		{"Jon Doe", "John Doe", 100, 100},
+		{"Jonh Doe", "John Doe", 80, 95},
+		{"John Doe", "John Smith", 40, 60},
+		{"John", "John Smith", 0, 50},
+		{"Jane Roe", "John Doe", 0, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			m := scoreNames(tt.a, tt.b)
+			assert.True(t, m.Compared)
+			assert.True(t, m.Score >= tt.minScore && m.Score <= tt.maxScore, "score %d", m.Score)
+		})
+	}
+
+	assert.False(t, scoreNames("", "John Doe").Compared)
+}
+
+func TestScoreEmails(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		score int
+	}{
+		{"jane.doe@example.com", "Jane.Doe@Example.com", 100},
+		{"jane.doe+bank@example.com", "jane.doe@example.com", 100},
+		{"jane.doe@gmail.com", "janedoe@googlemail.com", 100},
+		{"jane.doe@example.com", "janedoe@example.com", 0},
+		{"jane.doe@example.com", "jane.doe@example.org", 50},
+		{"jane.doe@example.com", "john@example.com", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			m := scoreEmails(tt.a, tt.b)
+			assert.True(t, m.Compared)
+			assert.Equal(t, tt.score, m.Score)
+		})
+	}
+
+	assert.False(t, scoreEmails("not an email", "jane@example.com").Compared)
+}
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"4155550123", "+14155550123"},
+		{"(415) 555-0123", "+14155550123"},
+		{"1-415-555-0123", "+14155550123"},
+		{"+1 415.555.0123", "+14155550123"},
+		{"415-555-0123 ext. 12", "+14155550123"},
+		{"415-555-0123x12", "+14155550123"},
+		{"+44 20 7946 0958", "+442079460958"},
+		{"011 44 20 7946 0958", "+442079460958"},
+		{"0044 20 7946 0958", "+442079460958"},
+		{"555-0123", ""},
+		{"415-555-CALL", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizePhoneNumber(tt.in))
+		})
+	}
+}
+
+func TestScoreAddresses(t *testing.T) {
+	ours := AddressData{Street: "2992 Cameron Road, Apartment 4", City: "Malakoff", Region: "NY", PostalCode: "14236", Country: "US"}
+
+	tests := []struct {
+		name     string
+		theirs   AddressData
+		minScore int
+		maxScore int
+	}{
+		{"abbreviated", AddressData{Street: "2992 CAMERON RD #4", City: "MALAKOFF", Region: "ny", PostalCode: "14236-1234", Country: "US"}, 100, 100},
+		{"unit missing", AddressData{Street: "2992 Cameron Rd", City: "Malakoff", Region: "NY", PostalCode: "14236"}, 80, 95},
+		{"no postal code", AddressData{Street: "2992 Cameron Rd Apt 4", City: "Malakoff"}, 100, 100},
+		{"different house number", AddressData{Street: "2993 Cameron Rd Apt 4", City: "Malakoff", Region: "NY", PostalCode: "14236"}, 40, 40},
+		{"different address", AddressData{Street: "100 Market Street", City: "San Francisco", Region: "CA", PostalCode: "94105"}, 0, 15},
+		{"different country", AddressData{Street: "2992 Cameron Rd Apt 4", City: "Malakoff", PostalCode: "14236", Country: "CA"}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := scoreAddresses(ours, tt.theirs)
+			assert.True(t, m.Compared)
+			assert.True(t, m.Score >= tt.minScore && m.Score <= tt.maxScore, "score %d", m.Score)
+		})
+	}
+
+	assert.False(t, scoreAddresses(ours, AddressData{City: "Malakoff"}).Compared)
+}
+
+func TestMatchIdentity(t *testing.T) {
+	ours := Identity{
+		Names:        []string{"Alberta Charleson"},
+		Emails:       []Email{{Data: "accountholder0@example.com"}},
+		PhoneNumbers: []PhoneNumber{{Data: "(111) 222-3333"}},
+		Addresses: []Address{{Data: AddressData{
+			Street: "2992 Cameron Road", City: "Malakoff", Region: "NY", PostalCode: "14236", Country: "US",
+		}}},
+	}
+	theirs := Identity{
+		Names: []string{"Alberta Bobbeth Charleson"},
+		Emails: []Email{
+			{Data: "extraordinarily.long.email.username.123456@reallylonghostname.com"},
+			{Data: "accountholder0@example.com"},
+		},
+		PhoneNumbers: []PhoneNumber{{Data: "1112223333"}, {Data: "1112224444"}},
+		Addresses: []Address{{Data: AddressData{
+			Street: "2992 Cameron Rd", City: "Malakoff", Region: "NY", PostalCode: "14236", Country: "US",
+		}}},
+	}
+
+	result := MatchIdentity(ours, theirs)
+	assert.Equal(t, 95, result.Name.Score)
+	assert.Equal(t, 100, result.Email.Score)
+	assert.Equal(t, 100, result.Phone.Score)
+	assert.Equal(t, 100, result.Address.Score)
+	assert.Equal(t, 99, result.Score())
+	assert.True(t, result.Passes(90))
+
+	theirs.Names = []string{"John Smith"}
+	result = MatchIdentity(ours, theirs)
+	assert.False(t, result.Passes(90))
+
+	result = MatchIdentity(Identity{Emails: ours.Emails}, theirs)
+	assert.False(t, result.Name.Compared)
+	assert.False(t, result.Passes(0))
+	assert.Equal(t, 100, result.Score())
+}
+
+func TestGetIdentityMatch(t *testing.T) {
+	server, client := newFakeServer(t)
+	var request map[string]interface{}
+	server.handle("/identity/match", func(body map[string]interface{}) (int, interface{}) {
+		request = body
+		return http.StatusOK, map[string]interface{}{
+			"accounts": []map[string]interface{}{{
+				"account_id":    "account-1",
+				"legal_name":    map[string]interface{}{"score": 90, "is_nickname_match": true},
+				"phone_number":  map[string]interface{}{"score": 100},
+				"email_address": nil,
+				"address":       map[string]interface{}{"score": 80, "is_postal_code_match": true},
+			}},
+		}
+	})
+
+	_, err := client.GetIdentityMatch("", IdentityMatchUser{})
+	assert.NotNil(t, err)
+
+	resp, err := client.GetIdentityMatchWithOptions("access-token", IdentityMatchUser{
+		LegalName:   "Alberta Charleson",
+		PhoneNumber: "+11112223333",
+	}, GetIdentityMatchOptions{AccountIDs: []string{"account-1"}})
+	assert.Nil(t, err)
+
+	user := request["user"].(map[string]interface{})
+	assert.Equal(t, "Alberta Charleson", user["legal_name"])
+	assert.NotContains(t, user, "email_address")
+	assert.NotContains(t, user, "address")
+	assert.Equal(t, []interface{}{"account-1"}, request["options"].(map[string]interface{})["account_ids"])
+
+	assert.Len(t, resp.Accounts, 1)
+	account := resp.Accounts[0]
+	assert.Equal(t, "account-1", account.AccountID)
+	assert.Equal(t, 90, account.LegalName.Score)
+	assert.True(t, account.LegalName.IsNicknameMatch)
+	assert.Nil(t, account.EmailAddress)
+	assert.True(t, account.Address.IsPostalCodeMatch)
+}
@@ -0,0 +1,37 @@
+package plaid
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+// TestImportsStandardLibraryOnly guards against the core package picking up
+// third-party dependencies; adapters for other libraries belong in their own
+// subpackages (see plaid/gqlgen).
+func TestImportsStandardLibraryOnly(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	assert.Nil(t, err)
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ImportsOnly)
+		assert.Nil(t, err)
+		for _, spec := range f.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			assert.Nil(t, err)
+			// Standard library import paths never contain a dot in their
+			// first element.
+			first := strings.SplitN(path, "/", 2)[0]
+			assert.False(t, strings.Contains(first, "."), "%s imports non-standard package %q", file, path)
+		}
+	}
+}
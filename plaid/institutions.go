@@ -52,7 +52,7 @@ type InstitutionStatusBreakdown struct {
 	Success          float64 `json:"success"`
 	ErrorPlaid       float64 `json:"error_plaid"`
 	ErrorInstitution float64 `json:"error_institution"`
-	RefreshInterval  string  `json:"refresh_interval"` // only applicable to TransactionsUpdates status
+	RefreshInterval  string  `json:"refresh_interval,omitempty"` // only applicable to TransactionsUpdates status
 }
 
 type Credential struct {
@@ -71,10 +71,10 @@ type getInstitutionsRequest struct {
 }
 
 type GetInstitutionsOptions struct {
-	Products                []string `json:"products"`
-	IncludeOptionalMetadata bool     `json:"include_optional_metadata"`
-	OAuth                   *bool    `json:"oauth"`
-	RoutingNumbers          []string `json:"routing_numbers"`
+	Products                []string `json:"products,omitempty"`
+	IncludeOptionalMetadata bool     `json:"include_optional_metadata,omitempty"`
+	OAuth                   *bool    `json:"oauth,omitempty"`
+	RoutingNumbers          []string `json:"routing_numbers,omitempty"`
 }
 
 type GetInstitutionsResponse struct {
@@ -92,8 +92,8 @@ type getInstitutionByIDRequest struct {
 }
 
 type GetInstitutionByIDOptions struct {
-	IncludeOptionalMetadata bool `json:"include_optional_metadata"`
-	IncludeStatus           bool `json:"include_status"`
+	IncludeOptionalMetadata bool `json:"include_optional_metadata,omitempty"`
+	IncludeStatus           bool `json:"include_status,omitempty"`
 }
 
 type GetInstitutionByIDResponse struct {
@@ -111,9 +111,9 @@ type searchInstitutionsRequest struct {
 }
 
 type SearchInstitutionsOptions struct {
-	IncludeOptionalMetadata bool                   `json:"include_optional_metadata"`
-	AccountFilter           map[string]interface{} `json:"account_filter"`
-	OAuth                   *bool                  `json:"oauth"`
+	IncludeOptionalMetadata bool                   `json:"include_optional_metadata,omitempty"`
+	AccountFilter           map[string]interface{} `json:"account_filter,omitempty"`
+	OAuth                   *bool                  `json:"oauth,omitempty"`
 }
 
 type SearchInstitutionsResponse struct {
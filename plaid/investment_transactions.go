@@ -11,16 +11,16 @@ type InvestmentTransaction struct {
 	SecurityID              string `json:"security_id"`
 	CancelTransactionID     string `json:"cancel_transaction_id"`
 
-	Date                   string  `json:"date"`
-	Name                   string  `json:"name"`
-	Quantity               float64 `json:"quantity"`
-	Amount                 float64 `json:"amount"`
-	Price                  float64 `json:"price"`
-	Fees                   float64 `json:"fees"`
-	Type                   string  `json:"type"`
-	Subtype                string  `json:"subtype"`
-	ISOCurrencyCode        string  `json:"iso_currency_code"`
-	UnofficialCurrencyCode string  `json:"unofficial_currency_code"`
+	Date                   string   `json:"date"`
+	Name                   string   `json:"name"`
+	Quantity               float64  `json:"quantity"`
+	Amount                 float64  `json:"amount"`
+	Price                  float64  `json:"price"`
+	Fees                   float64  `json:"fees"`
+	Type                   string   `json:"type"`
+	Subtype                string   `json:"subtype"`
+	ISOCurrencyCode        Currency `json:"iso_currency_code"`
+	UnofficialCurrencyCode Currency `json:"unofficial_currency_code"`
 }
 
 type GetInvestmentTransactionsResponse struct {
@@ -50,9 +50,9 @@ type getInvestmentTransactionsRequest struct {
 }
 
 type getInvestmentTransactionsRequestOptions struct {
-	AccountIDs []string `json:"account_ids"`
-	Count      int      `json:"count"`
-	Offset     int      `json:"offset"`
+	AccountIDs []string `json:"account_ids,omitempty"`
+	Count      int      `json:"count,omitempty"`
+	Offset     int      `json:"offset,omitempty"`
 }
 
 // GetInvestmentTransactionsWithOptions retrieves user-authorized investment transaction data for investment-type accounts.
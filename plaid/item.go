@@ -116,6 +116,7 @@ type importItemRequest struct {
 
 // ImportItemResponse is the type of the response returned by item/import.
 type ImportItemResponse struct {
+	APIResponse
 	AccessToken string `json:"access_token"`
 }
 
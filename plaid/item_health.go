@@ -0,0 +1,432 @@
+package plaid
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type ItemHealthStatus string
+
+const (
+	itemHealthStatusHealthy           ItemHealthStatus = "HEALTHY"
+	itemHealthStatusDegraded          ItemHealthStatus = "DEGRADED"
+	itemHealthStatusLoginRequired     ItemHealthStatus = "LOGIN_REQUIRED"
+	itemHealthStatusPendingExpiration ItemHealthStatus = "PENDING_EXPIRATION"
+	itemHealthStatusInstitutionDown   ItemHealthStatus = "INSTITUTION_DOWN"
+	itemHealthStatusPermissionRevoked ItemHealthStatus = "PERMISSION_REVOKED"
+)
+
+type itemHealthStatuses struct {
+	Healthy ItemHealthStatus
+	// Degraded Items are working, but their last update failed or they
+	// reported an error that the user cannot fix.
+	Degraded ItemHealthStatus
+	// LoginRequired Items need the user to log in again through Link's
+	// update mode.
+	LoginRequired ItemHealthStatus
+	// PendingExpiration Items have a consent that expires soon, and should
+	// be sent through update mode before it does.
+	PendingExpiration ItemHealthStatus
+	// InstitutionDown Items are failing because of their institution, and
+	// usually recover without any action.
+	InstitutionDown ItemHealthStatus
+	// PermissionRevoked Items had their access revoked by the user at the
+	// institution. Update mode cannot repair them; they must be linked
+	// again as new Items.
+	PermissionRevoked ItemHealthStatus
+}
+
+var ItemHealthStatuses itemHealthStatuses = itemHealthStatuses{
+	Healthy:           itemHealthStatusHealthy,
+	Degraded:          itemHealthStatusDegraded,
+	LoginRequired:     itemHealthStatusLoginRequired,
+	PendingExpiration: itemHealthStatusPendingExpiration,
+	InstitutionDown:   itemHealthStatusInstitutionDown,
+	PermissionRevoked: itemHealthStatusPermissionRevoked,
+}
+
+// NeedsUpdateMode reports whether an Item in this status should be sent
+// through Link's update mode.
+func (s ItemHealthStatus) NeedsUpdateMode() bool {
+	return s == itemHealthStatusLoginRequired || s == itemHealthStatusPendingExpiration
+}
+
+// loginRequiredErrors are the ITEM_ERROR codes the user fixes by logging in
+// again.
+var loginRequiredErrors = map[string]bool{
+	"INSUFFICIENT_CREDENTIALS": true,
+	"INVALID_CREDENTIALS":      true,
+	"INVALID_MFA":              true,
+	"INVALID_UPDATED_USERNAME": true,
+	"ITEM_LOCKED":              true,
+	"ITEM_LOGIN_REQUIRED":      true,
+	"USER_SETUP_REQUIRED":      true,
+}
+
+// ItemHealth is the health of an Item at a point in time.
+type ItemHealth struct {
+	ItemID string
+	Status ItemHealthStatus
+	// Error is the Item's error, if it has one.
+	Error *Error
+	// ConsentExpirationTime is when the Item's consent expires, if it does.
+	ConsentExpirationTime time.Time
+	CheckedAt             time.Time
+}
+
+// ItemHealthOptions configures how Items are classified.
+type ItemHealthOptions struct {
+	// ExpirationWarning is how long before its consent expires that an Item
+	// is PendingExpiration. Defaults to seven days.
+	ExpirationWarning time.Duration
+}
+
+// EvaluateItemHealth classifies an Item from the response to GetItem.
+func EvaluateItemHealth(resp GetItemResponse, now time.Time, options ItemHealthOptions) ItemHealth {
+	if options.ExpirationWarning == 0 {
+		options.ExpirationWarning = 7 * 24 * time.Hour
+	}
+
+	health := ItemHealth{
+		ItemID:                resp.Item.ItemID,
+		Status:                itemHealthStatusHealthy,
+		ConsentExpirationTime: resp.Item.ConsentExpirationTime,
+		CheckedAt:             now,
+	}
+
+	if resp.Item.Error.ErrorCode != "" {
+		err := resp.Item.Error
+		health.Error = &err
+		health.Status = classifyItemError(err)
+		return health
+	}
+
+	if expires := resp.Item.ConsentExpirationTime; !expires.IsZero() {
+		if !now.Before(expires) {
+			health.Status = itemHealthStatusLoginRequired
+			return health
+		}
+		if expires.Sub(now) <= options.ExpirationWarning {
+			health.Status = itemHealthStatusPendingExpiration
+			return health
+		}
+	}
+
+	for _, product := range []ProductStatus{resp.Status.Transactions, resp.Status.Investments} {
+		if product.LastFailedUpdate.After(product.LastSuccessfulUpdate) {
+			health.Status = itemHealthStatusDegraded
+		}
+	}
+	return health
+}
+
+func classifyItemError(err Error) ItemHealthStatus {
+	switch {
+	case loginRequiredErrors[err.ErrorCode]:
+		return itemHealthStatusLoginRequired
+	case err.ErrorCode == "PENDING_EXPIRATION":
+		return itemHealthStatusPendingExpiration
+	case err.ErrorType == "INSTITUTION_ERROR":
+		return itemHealthStatusInstitutionDown
+	case err.ErrorCode == "USER_PERMISSION_REVOKED" || err.ErrorCode == "ACCESS_NOT_GRANTED":
+		return itemHealthStatusPermissionRevoked
+	}
+	return itemHealthStatusDegraded
+}
+
+// Reauth is an Item sent through update mode to repair its health.
+type Reauth struct {
+	ItemID     string
+	LinkToken  string
+	Expiration time.Time
+	StartedAt  time.Time
+	// CompletedAt is set once the Item is healthy again.
+	CompletedAt time.Time
+}
+
+// ItemHealthMonitorOptions configures an ItemHealthMonitor.
+type ItemHealthMonitorOptions struct {
+	ItemHealthOptions
+
+	// PollInterval is how often Run checks every watched Item with GetItem.
+	// Leave it at zero to only follow Items through webhooks.
+	PollInterval time.Duration
+
+	// Reauth configures the update mode link tokens created by StartReauth.
+	// The client name and country codes are required.
+	Reauth ReauthOptions
+
+	// OnChange is called when the status of an Item changes.
+	OnChange func(previous ItemHealthStatus, health ItemHealth)
+	// OnReauthComplete is called when an Item sent through update mode is
+	// healthy again.
+	OnReauthComplete func(Reauth)
+	// OnError is called by Run when checking an Item fails.
+	OnError func(itemID string, err error)
+}
+
+// ReauthOptions configures the update mode link tokens created for Items.
+type ReauthOptions struct {
+	ClientName   string
+	CountryCodes []LinkCountryCode
+	Language     LinkLanguage
+	Webhook      string
+	RedirectURI  string
+}
+
+type watchedItem struct {
+	accessToken string
+	health      ItemHealth
+	reauth      *Reauth
+}
+
+// ItemHealthMonitor follows the health of a set of Items through GetItem and
+// ITEM webhooks, and sends Items that need the user to log in again through
+// Link's update mode.
+type ItemHealthMonitor struct {
+	client  *Client
+	options ItemHealthMonitorOptions
+	now     func() time.Time
+
+	mu    sync.Mutex
+	items map[string]*watchedItem
+}
+
+// NewItemHealthMonitor creates an ItemHealthMonitor.
+func NewItemHealthMonitor(client *Client, options ItemHealthMonitorOptions) *ItemHealthMonitor {
+	return &ItemHealthMonitor{
+		client:  client,
+		options: options,
+		now:     time.Now,
+		items:   map[string]*watchedItem{},
+	}
+}
+
+// Watch adds an Item to the set of monitored Items.
+func (m *ItemHealthMonitor) Watch(itemID, accessToken string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if item, ok := m.items[itemID]; ok {
+		item.accessToken = accessToken
+		return
+	}
+	m.items[itemID] = &watchedItem{accessToken: accessToken}
+}
+
+// Unwatch removes an Item from the set of monitored Items.
+func (m *ItemHealthMonitor) Unwatch(itemID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, itemID)
+}
+
+// Health returns the last recorded health of a watched Item. It is false
+// until the Item has been checked or a webhook received for it.
+func (m *ItemHealthMonitor) Health(itemID string) (ItemHealth, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok := m.items[itemID]
+	if !ok || item.health.Status == "" {
+		return ItemHealth{}, false
+	}
+	return item.health, true
+}
+
+// Check fetches a watched Item with GetItem and records its health.
+func (m *ItemHealthMonitor) Check(itemID string) (ItemHealth, error) {
+	m.mu.Lock()
+	item, ok := m.items[itemID]
+	var accessToken string
+	if ok {
+		accessToken = item.accessToken
+	}
+	m.mu.Unlock()
+	if !ok {
+		return ItemHealth{}, errors.New("item health monitor - item " + itemID + " is not watched")
+	}
+
+	resp, err := m.client.GetItem(accessToken)
+	if err != nil {
+		return ItemHealth{}, err
+	}
+	health := EvaluateItemHealth(resp, m.now(), m.options.ItemHealthOptions)
+	health.ItemID = itemID
+	m.record(health)
+	return health, nil
+}
+
+// HandleWebhook records the health carried by an ITEM webhook: ERROR,
+// PENDING_EXPIRATION, USER_PERMISSION_REVOKED or LOGIN_REPAIRED. Other
+// webhooks and unwatched Items are ignored, and false is returned.
+func (m *ItemHealthMonitor) HandleWebhook(webhook Webhook) (ItemHealth, bool) {
+	if webhook.WebhookType != "ITEM" {
+		return ItemHealth{}, false
+	}
+
+	health := ItemHealth{ItemID: webhook.ItemID, CheckedAt: m.now()}
+	switch webhook.WebhookCode {
+	case "ERROR":
+		if webhook.Error == nil {
+			return ItemHealth{}, false
+		}
+		health.Error = webhook.Error
+		health.Status = classifyItemError(*webhook.Error)
+	case "PENDING_EXPIRATION":
+		health.Status = itemHealthStatusPendingExpiration
+		health.ConsentExpirationTime = webhook.ConsentExpirationTime
+	case "USER_PERMISSION_REVOKED":
+		health.Error = webhook.Error
+		health.Status = itemHealthStatusPermissionRevoked
+	case "LOGIN_REPAIRED":
+		health.Status = itemHealthStatusHealthy
+	default:
+		return ItemHealth{}, false
+	}
+
+	m.mu.Lock()
+	_, ok := m.items[webhook.ItemID]
+	m.mu.Unlock()
+	if !ok {
+		return ItemHealth{}, false
+	}
+
+	m.record(health)
+	return health, true
+}
+
+// record stores the health of an Item, completing its reauth if it is
+// healthy again, and reports the change.
+func (m *ItemHealthMonitor) record(health ItemHealth) {
+	m.mu.Lock()
+	item, ok := m.items[health.ItemID]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	previous := item.health.Status
+	item.health = health
+
+	var completed *Reauth
+	if item.reauth != nil && health.Status == itemHealthStatusHealthy {
+		item.reauth.CompletedAt = health.CheckedAt
+		r := *item.reauth
+		completed = &r
+		item.reauth = nil
+	}
+	m.mu.Unlock()
+
+	if previous != health.Status && m.options.OnChange != nil {
+		m.options.OnChange(previous, health)
+	}
+	if completed != nil && m.options.OnReauthComplete != nil {
+		m.options.OnReauthComplete(*completed)
+	}
+}
+
+// StartReauth creates an update mode link token for a watched Item that
+// needs the user to log in again, and tracks the Item until it is healthy.
+// Open Link with the token for the given user, then call Check once Link
+// succeeds, or wait for the LOGIN_REPAIRED webhook.
+func (m *ItemHealthMonitor) StartReauth(itemID string, user LinkTokenUser) (Reauth, error) {
+	m.mu.Lock()
+	item, ok := m.items[itemID]
+	var accessToken string
+	var status ItemHealthStatus
+	if ok {
+		accessToken, status = item.accessToken, item.health.Status
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return Reauth{}, errors.New("item health monitor - item " + itemID + " is not watched")
+	}
+	if !status.NeedsUpdateMode() {
+		return Reauth{}, errors.New("item health monitor - item " + itemID + " does not need update mode")
+	}
+
+	options := m.options.Reauth
+	builder := UpdateModeLinkToken(options.ClientName, user, accessToken).
+		CountryCodes(options.CountryCodes...).
+		Webhook(options.Webhook)
+	if options.Language != "" {
+		builder.Language(options.Language)
+	}
+	if options.RedirectURI != "" {
+		builder.RedirectURI(options.RedirectURI)
+	}
+	configs, err := builder.Build()
+	if err != nil {
+		return Reauth{}, err
+	}
+
+	resp, err := m.client.CreateLinkToken(configs)
+	if err != nil {
+		return Reauth{}, err
+	}
+
+	reauth := Reauth{
+		ItemID:     itemID,
+		LinkToken:  resp.LinkToken,
+		Expiration: resp.Expiration,
+		StartedAt:  m.now(),
+	}
+	m.mu.Lock()
+	if item, ok := m.items[itemID]; ok {
+		item.reauth = &reauth
+	}
+	m.mu.Unlock()
+	return reauth, nil
+}
+
+// PendingReauths returns the Items sent through update mode that are not yet
+// healthy again.
+func (m *ItemHealthMonitor) PendingReauths() []Reauth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var pending []Reauth
+	for _, item := range m.items {
+		if item.reauth != nil {
+			pending = append(pending, *item.reauth)
+		}
+	}
+	return pending
+}
+
+// Run checks every watched Item each PollInterval until ctx is done. Failures
+// are reported to OnError.
+func (m *ItemHealthMonitor) Run(ctx context.Context) error {
+	if m.options.PollInterval <= 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ticker := time.NewTicker(m.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		m.checkAll()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *ItemHealthMonitor) checkAll() {
+	m.mu.Lock()
+	itemIDs := make([]string, 0, len(m.items))
+	for itemID := range m.items {
+		itemIDs = append(itemIDs, itemID)
+	}
+	m.mu.Unlock()
+
+	for _, itemID := range itemIDs {
+		if _, err := m.Check(itemID); err != nil && m.options.OnError != nil {
+			m.options.OnError(itemID, err)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package plaid
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestEvaluateItemHealth(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		resp   GetItemResponse
+		status ItemHealthStatus
+	}{
+		{"healthy", GetItemResponse{Item: Item{ItemID: "item-1"}}, ItemHealthStatuses.Healthy},
+		{"login required", GetItemResponse{Item: Item{Error: Error{ErrorType: "ITEM_ERROR", ErrorCode: "ITEM_LOGIN_REQUIRED"}}}, ItemHealthStatuses.LoginRequired},
+		{"pending expiration error", GetItemResponse{Item: Item{Error: Error{ErrorType: "ITEM_ERROR", ErrorCode: "PENDING_EXPIRATION"}}}, ItemHealthStatuses.PendingExpiration},
+		{"institution down", GetItemResponse{Item: Item{Error: Error{ErrorType: "INSTITUTION_ERROR", ErrorCode: "INSTITUTION_DOWN"}}}, ItemHealthStatuses.InstitutionDown},
+		{"permission revoked", GetItemResponse{Item: Item{Error: Error{ErrorType: "ITEM_ERROR", ErrorCode: "USER_PERMISSION_REVOKED"}}}, ItemHealthStatuses.PermissionRevoked},
+		{"other error", GetItemResponse{Item: Item{Error: Error{ErrorType: "ITEM_ERROR", ErrorCode: "PRODUCTS_NOT_SUPPORTED"}}}, ItemHealthStatuses.Degraded},
+		{"consent expires soon", GetItemResponse{Item: Item{ConsentExpirationTime: now.Add(72 * time.Hour)}}, ItemHealthStatuses.PendingExpiration},
+		{"consent expires later", GetItemResponse{Item: Item{ConsentExpirationTime: now.Add(30 * 24 * time.Hour)}}, ItemHealthStatuses.Healthy},
+		{"consent expired", GetItemResponse{Item: Item{ConsentExpirationTime: now.Add(-time.Hour)}}, ItemHealthStatuses.LoginRequired},
+		{"last update failed", GetItemResponse{Status: ItemStatus{Transactions: ProductStatus{
+			LastSuccessfulUpdate: now.Add(-48 * time.Hour),
+			LastFailedUpdate:     now.Add(-time.Hour),
+		}}}, ItemHealthStatuses.Degraded},
+		{"recovered", GetItemResponse{Status: ItemStatus{Transactions: ProductStatus{
+			LastSuccessfulUpdate: now.Add(-time.Hour),
+			LastFailedUpdate:     now.Add(-48 * time.Hour),
+		}}}, ItemHealthStatuses.Healthy},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			health := EvaluateItemHealth(c.resp, now, ItemHealthOptions{})
+			assert.Equal(t, c.status, health.Status)
+			assert.Equal(t, c.resp.Item.Error.ErrorCode != "", health.Error != nil)
+		})
+	}
+
+	health := EvaluateItemHealth(GetItemResponse{Item: Item{ConsentExpirationTime: now.Add(72 * time.Hour)}}, now, ItemHealthOptions{
+		ExpirationWarning: 24 * time.Hour,
+	})
+	assert.Equal(t, ItemHealthStatuses.Healthy, health.Status)
+}
+
+func newItemHealthMonitor(t *testing.T) (*fakeServer, *ItemHealthMonitor, *[]Reauth) {
+	server, client := newFakeServer(t)
+	var completed []Reauth
+	monitor := NewItemHealthMonitor(client, ItemHealthMonitorOptions{
+		Reauth: ReauthOptions{
+			ClientName:   "Plaid Test",
+			CountryCodes: []LinkCountryCode{LinkCountryCodes.US},
+		},
+		OnReauthComplete: func(r Reauth) {
+			completed = append(completed, r)
+		},
+	})
+	monitor.Watch("item-1", "access-sandbox-1")
+	return server, monitor, &completed
+}
+
+func TestItemHealthMonitorReauth(t *testing.T) {
+	server, monitor, completed := newItemHealthMonitor(t)
+
+	itemErr := Error{ErrorType: "ITEM_ERROR", ErrorCode: "ITEM_LOGIN_REQUIRED"}
+	server.handle("/item/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, GetItemResponse{Item: Item{ItemID: "item-1", Error: itemErr}}
+	})
+	var linkTokenBody map[string]interface{}
+	server.handle("/link/token/create", func(body map[string]interface{}) (int, interface{}) {
+		linkTokenBody = body
+		return http.StatusOK, CreateLinkTokenResponse{LinkToken: "link-sandbox-1"}
+	})
+
+	_, err := monitor.StartReauth("item-1", LinkTokenUser{ClientUserID: "user-1"})
+	assert.NotNil(t, err, "an Item that has not been checked does not need update mode")
+
+	health, err := monitor.Check("item-1")
+	assert.Nil(t, err)
+	assert.Equal(t, ItemHealthStatuses.LoginRequired, health.Status)
+	assert.True(t, health.Status.NeedsUpdateMode())
+
+	reauth, err := monitor.StartReauth("item-1", LinkTokenUser{ClientUserID: "user-1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "link-sandbox-1", reauth.LinkToken)
+	assert.Equal(t, "access-sandbox-1", linkTokenBody["access_token"])
+	assert.Nil(t, linkTokenBody["products"])
+	assert.Len(t, monitor.PendingReauths(), 1)
+
+	itemErr = Error{}
+	health, err = monitor.Check("item-1")
+	assert.Nil(t, err)
+	assert.Equal(t, ItemHealthStatuses.Healthy, health.Status)
+	assert.Empty(t, monitor.PendingReauths())
+	assert.Len(t, *completed, 1)
+	assert.Equal(t, "item-1", (*completed)[0].ItemID)
+	assert.False(t, (*completed)[0].CompletedAt.IsZero())
+}
+
+func TestItemHealthMonitorWebhooks(t *testing.T) {
+	server, monitor, completed := newItemHealthMonitor(t)
+	server.handle("/link/token/create", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, CreateLinkTokenResponse{LinkToken: "link-sandbox-1"}
+	})
+
+	expires := time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC)
+	webhook, err := ParseWebhook([]byte(`{
+		"webhook_type": "ITEM",
+		"webhook_code": "PENDING_EXPIRATION",
+		"item_id": "item-1",
+		"consent_expiration_time": "2021-03-08T00:00:00Z"
+	}`))
+	assert.Nil(t, err)
+	health, ok := monitor.HandleWebhook(webhook)
+	assert.True(t, ok)
+	assert.Equal(t, ItemHealthStatuses.PendingExpiration, health.Status)
+	assert.Equal(t, expires, health.ConsentExpirationTime)
+
+	_, err = monitor.StartReauth("item-1", LinkTokenUser{ClientUserID: "user-1"})
+	assert.Nil(t, err)
+
+	health, ok = monitor.HandleWebhook(Webhook{WebhookType: "ITEM", WebhookCode: "LOGIN_REPAIRED", ItemID: "item-1"})
+	assert.True(t, ok)
+	assert.Equal(t, ItemHealthStatuses.Healthy, health.Status)
+	assert.Len(t, *completed, 1)
+
+	health, ok = monitor.HandleWebhook(Webhook{WebhookType: "ITEM", WebhookCode: "USER_PERMISSION_REVOKED", ItemID: "item-1",
+		Error: &Error{ErrorType: "ITEM_ERROR", ErrorCode: "USER_PERMISSION_REVOKED"}})
+	assert.True(t, ok)
+	assert.Equal(t, ItemHealthStatuses.PermissionRevoked, health.Status)
+	_, err = monitor.StartReauth("item-1", LinkTokenUser{ClientUserID: "user-1"})
+	assert.NotNil(t, err)
+
+	health, ok = monitor.HandleWebhook(Webhook{WebhookType: "ITEM", WebhookCode: "ERROR", ItemID: "item-1",
+		Error: &Error{ErrorType: "INSTITUTION_ERROR", ErrorCode: "INSTITUTION_NOT_RESPONDING"}})
+	assert.True(t, ok)
+	assert.Equal(t, ItemHealthStatuses.InstitutionDown, health.Status)
+
+	health, ok = monitor.HandleWebhook(Webhook{WebhookType: "ITEM", WebhookCode: "ERROR", ItemID: "item-1",
+		Error: &Error{ErrorType: "ITEM_ERROR", ErrorCode: "PENDING_EXPIRATION"}})
+	assert.True(t, ok)
+	assert.Equal(t, ItemHealthStatuses.PendingExpiration, health.Status)
+
+	recorded, ok := monitor.Health("item-1")
+	assert.True(t, ok)
+	assert.Equal(t, health, recorded)
+
+	_, ok = monitor.HandleWebhook(Webhook{WebhookType: "ITEM", WebhookCode: "LOGIN_REPAIRED", ItemID: "item-2"})
+	assert.False(t, ok)
+	_, ok = monitor.HandleWebhook(Webhook{WebhookType: "TRANSACTIONS", WebhookCode: "DEFAULT_UPDATE", ItemID: "item-1"})
+	assert.False(t, ok)
+}
+
+func TestItemHealthMonitorCheckWhileWatching(t *testing.T) {
+	server, monitor, _ := newItemHealthMonitor(t)
+	server.handle("/item/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, GetItemResponse{Item: Item{ItemID: "item-1"}}
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			monitor.Watch("item-1", "access-sandbox-2")
+		}
+	}()
+	var errs []error
+	for i := 0; i < 50; i++ {
+		if _, err := monitor.Check("item-1"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	wg.Wait()
+	assert.Empty(t, errs)
+}
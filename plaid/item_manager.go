@@ -0,0 +1,484 @@
+package plaid
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ItemRecord is what an ItemManager knows about an Item: where it was linked,
+// the webhook Plaid calls for it, and the tokens that depend on it and must
+// be cleaned up with it.
+type ItemRecord struct {
+	ItemID        string    `json:"item_id"`
+	InstitutionID string    `json:"institution_id"`
+	Webhook       string    `json:"webhook"`
+	CreatedAt     time.Time `json:"created_at"`
+	// AssetReportTokens are the asset reports created from the Item, which
+	// are removed along with it.
+	AssetReportTokens []string `json:"asset_report_tokens,omitempty"`
+	// ProcessorTokens are the processor tokens created for the Item's
+	// accounts. Plaid invalidates them when the Item is removed.
+	ProcessorTokens []string `json:"processor_tokens,omitempty"`
+}
+
+// ItemStore persists ItemRecords. Implementations must be safe for concurrent
+// use.
+type ItemStore interface {
+	// GetItem returns the record of an Item, if one has been stored.
+	GetItem(itemID string) (record ItemRecord, ok bool, err error)
+	// PutItem stores a record, replacing any record of the same Item.
+	PutItem(record ItemRecord) error
+	// DeleteItem deletes the record of an Item.
+	DeleteItem(itemID string) error
+	// ListItems returns every stored record.
+	ListItems() ([]ItemRecord, error)
+}
+
+// MemoryItemStore is an ItemStore that keeps records in memory.
+type MemoryItemStore struct {
+	mu      sync.Mutex
+	records map[string]ItemRecord
+}
+
+// NewMemoryItemStore creates an empty MemoryItemStore.
+func NewMemoryItemStore() *MemoryItemStore {
+	return &MemoryItemStore{records: map[string]ItemRecord{}}
+}
+
+// GetItem implements ItemStore.
+func (s *MemoryItemStore) GetItem(itemID string) (ItemRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[itemID]
+	return record.clone(), ok, nil
+}
+
+// PutItem implements ItemStore.
+func (s *MemoryItemStore) PutItem(record ItemRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.ItemID] = record.clone()
+	return nil
+}
+
+// DeleteItem implements ItemStore.
+func (s *MemoryItemStore) DeleteItem(itemID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, itemID)
+	return nil
+}
+
+// ListItems implements ItemStore. Records are sorted by item ID.
+func (s *MemoryItemStore) ListItems() ([]ItemRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := make([]ItemRecord, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record.clone())
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ItemID < records[j].ItemID })
+	return records, nil
+}
+
+func (r ItemRecord) clone() ItemRecord {
+	r.AssetReportTokens = append([]string(nil), r.AssetReportTokens...)
+	r.ProcessorTokens = append([]string(nil), r.ProcessorTokens...)
+	return r
+}
+
+// ItemManager coordinates the lifecycle of Items: it records them when their
+// public tokens are exchanged, keeps their webhooks up to date, tracks the
+// asset reports and processor tokens created from them, and cleans all of it
+// up when they are removed. Access tokens are kept in the VaultClient's vault.
+type ItemManager struct {
+	client *VaultClient
+	store  ItemStore
+	now    func() time.Time
+
+	items keyedLocks
+}
+
+// NewItemManager returns an ItemManager that calls Plaid with client and
+// records Items in store.
+func NewItemManager(client *VaultClient, store ItemStore) *ItemManager {
+	return &ItemManager{client: client, store: store, now: time.Now}
+}
+
+// Item returns the record of an Item.
+func (m *ItemManager) Item(itemID string) (ItemRecord, error) {
+	record, ok, err := m.store.GetItem(itemID)
+	if err != nil {
+		return record, err
+	}
+	if !ok {
+		return record, fmt.Errorf("item manager - item %s is not recorded", itemID)
+	}
+	return record, nil
+}
+
+// Exchange exchanges a public token from Link, stores the access token in the
+// vault and records the new Item. The Item is recorded even if its details
+// cannot be retrieved, so that it is not lost; its institution and webhook are
+// then left empty.
+func (m *ItemManager) Exchange(publicToken string) (ItemRecord, error) {
+	resp, err := m.client.ExchangePublicToken(publicToken)
+	if err != nil {
+		return ItemRecord{}, err
+	}
+
+	record := ItemRecord{ItemID: resp.ItemID, CreatedAt: m.now()}
+	item, err := m.client.GetItem(resp.ItemID)
+	if err == nil {
+		record.InstitutionID = item.Item.InstitutionID
+		record.Webhook = item.Item.Webhook
+	}
+	if putErr := m.store.PutItem(record); putErr != nil {
+		return record, putErr
+	}
+	return record, err
+}
+
+// update applies fn to the record of an Item and stores the result, with
+// updates to the same Item serialized.
+func (m *ItemManager) update(itemID string, fn func(*ItemRecord) error) (ItemRecord, error) {
+	unlock := m.items.lock(itemID)
+	defer unlock()
+
+	record, err := m.Item(itemID)
+	if err != nil {
+		return record, err
+	}
+	if err := fn(&record); err != nil {
+		return record, err
+	}
+	return record, m.store.PutItem(record)
+}
+
+// SetWebhook changes the webhook Plaid calls for an Item.
+func (m *ItemManager) SetWebhook(itemID, webhook string) (ItemRecord, error) {
+	return m.update(itemID, func(record *ItemRecord) error {
+		return m.client.WithAccessToken(itemID, func(accessToken string) error {
+			resp, err := m.client.Client().UpdateItemWebhook(accessToken, webhook)
+			if err != nil {
+				return err
+			}
+			record.Webhook = resp.Item.Webhook
+			return nil
+		})
+	})
+}
+
+// ItemResult is the outcome of a bulk operation for one Item.
+type ItemResult struct {
+	ItemID string
+	// Skipped is set when the Item did not need to be changed.
+	Skipped bool
+	Err     error
+}
+
+// UpdateWebhooks sets the webhook of every recorded Item to the one returned
+// by webhook, skipping Items whose webhook would not change. An Item that
+// fails does not stop the others; its error is reported in its result.
+func (m *ItemManager) UpdateWebhooks(webhook func(ItemRecord) string) ([]ItemResult, error) {
+	records, err := m.store.ListItems()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ItemResult, 0, len(records))
+	for _, record := range records {
+		result := ItemResult{ItemID: record.ItemID}
+		if next := webhook(record); next == record.Webhook {
+			result.Skipped = true
+		} else {
+			_, result.Err = m.SetWebhook(record.ItemID, next)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// WebhookHost returns a function for UpdateWebhooks that moves webhooks to
+// another host, such as "hooks.example.com" or "hooks.example.com:8443",
+// keeping their scheme, path and query. Items without a webhook are left
+// without one.
+func WebhookHost(host string) func(ItemRecord) string {
+	return func(record ItemRecord) string {
+		if record.Webhook == "" {
+			return ""
+		}
+		u, err := url.Parse(record.Webhook)
+		if err != nil {
+			return record.Webhook
+		}
+		u.Host = host
+		return u.String()
+	}
+}
+
+// TrackAssetReport records an asset report created from an Item, so that it
+// is removed along with the Item.
+func (m *ItemManager) TrackAssetReport(itemID, assetReportToken string) (ItemRecord, error) {
+	return m.update(itemID, func(record *ItemRecord) error {
+		record.AssetReportTokens = appendUnique(record.AssetReportTokens, assetReportToken)
+		return nil
+	})
+}
+
+// UntrackAssetReport forgets an asset report that was removed separately.
+func (m *ItemManager) UntrackAssetReport(itemID, assetReportToken string) (ItemRecord, error) {
+	return m.update(itemID, func(record *ItemRecord) error {
+		record.AssetReportTokens = removeString(record.AssetReportTokens, assetReportToken)
+		return nil
+	})
+}
+
+// TrackProcessorToken records a processor token created for one of an Item's
+// accounts.
+func (m *ItemManager) TrackProcessorToken(itemID, processorToken string) (ItemRecord, error) {
+	return m.update(itemID, func(record *ItemRecord) error {
+		record.ProcessorTokens = appendUnique(record.ProcessorTokens, processorToken)
+		return nil
+	})
+}
+
+// RemoveItem removes an Item's asset reports and then the Item itself from
+// Plaid, which also invalidates its processor tokens, and deletes its access
+// token and record. Asset reports are forgotten as they are removed, so a
+// removal that fails part way can be retried. Asset reports and Items Plaid no
+// longer knows about are cleaned up as if they had been removed.
+func (m *ItemManager) RemoveItem(itemID string) error {
+	unlock := m.items.lock(itemID)
+	defer unlock()
+
+	record, err := m.Item(itemID)
+	if err != nil {
+		return err
+	}
+	return m.remove(record, true)
+}
+
+// remove cleans up an Item, removing it from Plaid if removeFromPlaid is set.
+// It must be called with the Item's lock held.
+func (m *ItemManager) remove(record ItemRecord, removeFromPlaid bool) error {
+	for len(record.AssetReportTokens) > 0 {
+		token := record.AssetReportTokens[0]
+		if _, err := m.client.Client().RemoveAssetReport(token); err != nil && !isAssetReportGone(err) {
+			return err
+		}
+		record.AssetReportTokens = record.AssetReportTokens[1:]
+		if err := m.store.PutItem(record); err != nil {
+			return err
+		}
+	}
+
+	if removeFromPlaid {
+		_, err := m.client.RemoveItem(record.ItemID)
+		if err != nil && !errors.Is(err, ErrAccessTokenNotFound) && !isItemGone(err) {
+			return err
+		}
+	}
+	if err := m.client.Vault().DeleteAccessToken(record.ItemID); err != nil {
+		return err
+	}
+	return m.store.DeleteItem(record.ItemID)
+}
+
+// isItemGone reports whether err means Plaid no longer has the Item.
+func isItemGone(err error) bool {
+	var plaidErr Error
+	if !errors.As(err, &plaidErr) {
+		return false
+	}
+	return plaidErr.ErrorCode == "ITEM_NOT_FOUND" || plaidErr.ErrorCode == "INVALID_ACCESS_TOKEN"
+}
+
+// isAssetReportGone reports whether err means Plaid no longer has the asset
+// report, for example because it was removed without the ItemManager.
+func isAssetReportGone(err error) bool {
+	var plaidErr Error
+	if !errors.As(err, &plaidErr) {
+		return false
+	}
+	return plaidErr.ErrorCode == "ASSET_REPORT_NOT_FOUND" || plaidErr.ErrorCode == "INVALID_ASSET_REPORT_TOKEN"
+}
+
+type OrphanReason string
+
+const (
+	orphanReasonMissingAccessToken OrphanReason = "MISSING_ACCESS_TOKEN"
+	orphanReasonMissingRecord      OrphanReason = "MISSING_RECORD"
+	orphanReasonItemNotFound       OrphanReason = "ITEM_NOT_FOUND"
+)
+
+type orphanReasons struct {
+	// MissingAccessToken Items are recorded but have no access token in the
+	// vault, so they cannot be called or removed.
+	MissingAccessToken OrphanReason
+	// MissingRecord Items have an access token in the vault but no record,
+	// for example because they were exchanged without the ItemManager.
+	// Cleaning one up records it, so that it is managed from then on.
+	MissingRecord OrphanReason
+	// ItemNotFound Items were removed from Plaid, or their access tokens
+	// were invalidated, without going through the ItemManager.
+	ItemNotFound OrphanReason
+}
+
+var OrphanReasons orphanReasons = orphanReasons{
+	MissingAccessToken: orphanReasonMissingAccessToken,
+	MissingRecord:      orphanReasonMissingRecord,
+	ItemNotFound:       orphanReasonItemNotFound,
+}
+
+// OrphanedItem is an Item whose record, access token and state in Plaid do
+// not agree.
+type OrphanedItem struct {
+	ItemRecord
+	Reason OrphanReason
+	// Err is set when the Item could not be cleaned up.
+	Err error
+}
+
+// ReconcileOptions configures Reconcile.
+type ReconcileOptions struct {
+	// DryRun only lists orphaned Items, without cleaning them up.
+	DryRun bool
+}
+
+// ReconcileReport is the result of Reconcile.
+type ReconcileReport struct {
+	Orphans []OrphanedItem
+	// Errors holds the Items that could not be checked, for example because
+	// Plaid was unavailable.
+	Errors []ItemResult
+}
+
+// Reconcile checks every recorded Item, and every Item the vault has an
+// access token for, against the vault, the store and Plaid, and returns those
+// that are orphaned. Unless DryRun is set, orphaned Items that Plaid still has
+// are recorded, and the others have their asset reports removed and their
+// access tokens and records deleted.
+func (m *ItemManager) Reconcile(options ReconcileOptions) (report ReconcileReport, err error) {
+	records, err := m.store.ListItems()
+	if err != nil {
+		return report, err
+	}
+	itemIDs, err := m.client.Vault().ItemIDs()
+	if err != nil {
+		return report, err
+	}
+
+	recorded := map[string]bool{}
+	for _, record := range records {
+		recorded[record.ItemID] = true
+		orphan, err := m.reconcile(record.ItemID, options)
+		report.add(record.ItemID, orphan, err)
+	}
+	for _, itemID := range itemIDs {
+		if recorded[itemID] {
+			continue
+		}
+		orphan, err := m.reconcileUnrecorded(itemID, options)
+		report.add(itemID, orphan, err)
+	}
+	return report, nil
+}
+
+func (r *ReconcileReport) add(itemID string, orphan *OrphanedItem, err error) {
+	switch {
+	case err != nil:
+		r.Errors = append(r.Errors, ItemResult{ItemID: itemID, Err: err})
+	case orphan != nil:
+		r.Orphans = append(r.Orphans, *orphan)
+	}
+}
+
+func (m *ItemManager) reconcile(itemID string, options ReconcileOptions) (*OrphanedItem, error) {
+	unlock := m.items.lock(itemID)
+	defer unlock()
+
+	// The record is read again in case it changed since it was listed.
+	record, ok, err := m.store.GetItem(itemID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var reason OrphanReason
+	_, err = m.client.GetItem(record.ItemID)
+	switch {
+	case err == nil:
+		return nil, nil
+	case errors.Is(err, ErrAccessTokenNotFound):
+		reason = OrphanReasons.MissingAccessToken
+	case isItemGone(err):
+		reason = OrphanReasons.ItemNotFound
+	default:
+		return nil, err
+	}
+
+	orphan := &OrphanedItem{ItemRecord: record, Reason: reason}
+	if !options.DryRun {
+		orphan.Err = m.remove(record, false)
+	}
+	return orphan, nil
+}
+
+// reconcileUnrecorded checks an Item the vault has an access token for but
+// the store has no record of.
+func (m *ItemManager) reconcileUnrecorded(itemID string, options ReconcileOptions) (*OrphanedItem, error) {
+	unlock := m.items.lock(itemID)
+	defer unlock()
+
+	// The Item may have been recorded since the store was listed.
+	if _, ok, err := m.store.GetItem(itemID); err != nil || ok {
+		return nil, err
+	}
+
+	record := ItemRecord{ItemID: itemID}
+	item, err := m.client.GetItem(itemID)
+	switch {
+	case err == nil:
+		record.InstitutionID = item.Item.InstitutionID
+		record.Webhook = item.Item.Webhook
+		orphan := &OrphanedItem{ItemRecord: record, Reason: OrphanReasons.MissingRecord}
+		if !options.DryRun {
+			record.CreatedAt = m.now()
+			orphan.Err = m.store.PutItem(record)
+		}
+		return orphan, nil
+	case errors.Is(err, ErrAccessTokenNotFound):
+		// The access token was deleted since the vault was listed.
+		return nil, nil
+	case isItemGone(err):
+		orphan := &OrphanedItem{ItemRecord: record, Reason: OrphanReasons.ItemNotFound}
+		if !options.DryRun {
+			orphan.Err = m.remove(record, false)
+		}
+		return orphan, nil
+	}
+	return nil, err
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func removeString(values []string, value string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != value {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,248 @@
+package plaid
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+// fakeItems backs the item endpoints of a fakeServer with Items keyed by
+// access token.
+type fakeItems struct {
+	mu           sync.Mutex
+	items        map[string]*Item
+	assetReports map[string]bool
+}
+
+func newItemManagerFixture(t *testing.T) (*fakeServer, *fakeItems, *ItemManager) {
+	server, client := newFakeServer(t)
+	f := &fakeItems{
+		items:        map[string]*Item{},
+		assetReports: map[string]bool{},
+	}
+	itemNotFound := map[string]interface{}{"error_type": "ITEM_ERROR", "error_code": "ITEM_NOT_FOUND"}
+
+	server.handle("/item/public_token/exchange", func(body map[string]interface{}) (int, interface{}) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		itemID := "item-" + body["public_token"].(string)
+		accessToken := "access-" + body["public_token"].(string)
+		f.items[accessToken] = &Item{ItemID: itemID, InstitutionID: "ins_1", Webhook: "https://old.example.com/plaid"}
+		return http.StatusOK, map[string]interface{}{"access_token": accessToken, "item_id": itemID}
+	})
+	server.handle("/item/get", func(body map[string]interface{}) (int, interface{}) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		item, ok := f.items[body["access_token"].(string)]
+		if !ok {
+			return http.StatusBadRequest, itemNotFound
+		}
+		return http.StatusOK, map[string]interface{}{"item": item}
+	})
+	server.handle("/item/webhook/update", func(body map[string]interface{}) (int, interface{}) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		item, ok := f.items[body["access_token"].(string)]
+		if !ok {
+			return http.StatusBadRequest, itemNotFound
+		}
+		item.Webhook = body["webhook"].(string)
+		return http.StatusOK, map[string]interface{}{"item": item}
+	})
+	server.handle("/item/remove", func(body map[string]interface{}) (int, interface{}) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		accessToken := body["access_token"].(string)
+		if _, ok := f.items[accessToken]; !ok {
+			return http.StatusBadRequest, itemNotFound
+		}
+		delete(f.items, accessToken)
+		return http.StatusOK, map[string]interface{}{}
+	})
+	server.handle("/asset_report/remove", func(body map[string]interface{}) (int, interface{}) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		token := body["asset_report_token"].(string)
+		if !f.assetReports[token] {
+			return http.StatusBadRequest, map[string]interface{}{"error_type": "INVALID_INPUT", "error_code": "INVALID_ASSET_REPORT_TOKEN"}
+		}
+		delete(f.assetReports, token)
+		return http.StatusOK, map[string]interface{}{"removed": true}
+	})
+
+	vault := NewMemoryTokenVault(newTestCipher(t))
+	manager := NewItemManager(NewVaultClient(client, vault), NewMemoryItemStore())
+	manager.now = func() time.Time { return time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC) }
+	return server, f, manager
+}
+
+func TestItemManagerExchange(t *testing.T) {
+	_, _, manager := newItemManagerFixture(t)
+
+	record, err := manager.Exchange("1")
+	assert.NoError(t, err)
+	assert.Equal(t, ItemRecord{
+		ItemID:        "item-1",
+		InstitutionID: "ins_1",
+		Webhook:       "https://old.example.com/plaid",
+		CreatedAt:     time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+	}, record)
+
+	stored, err := manager.Item("item-1")
+	assert.NoError(t, err)
+	assert.Equal(t, record, stored)
+
+	_, err = manager.Item("item-2")
+	assert.Error(t, err)
+}
+
+func TestItemManagerUpdateWebhooks(t *testing.T) {
+	server, _, manager := newItemManagerFixture(t)
+	for _, publicToken := range []string{"1", "2"} {
+		_, err := manager.Exchange(publicToken)
+		assert.NoError(t, err)
+	}
+	_, err := manager.SetWebhook("item-2", "https://new.example.com/plaid")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, server.callCount("/item/webhook/update"))
+
+	results, err := manager.UpdateWebhooks(WebhookHost("new.example.com"))
+	assert.NoError(t, err)
+	assert.Equal(t, []ItemResult{
+		{ItemID: "item-1"},
+		{ItemID: "item-2", Skipped: true},
+	}, results)
+	assert.Equal(t, 2, server.callCount("/item/webhook/update"))
+
+	record, err := manager.Item("item-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://new.example.com/plaid", record.Webhook)
+
+	// A failing Item does not stop the others.
+	assert.NoError(t, manager.client.Vault().DeleteAccessToken("item-1"))
+	results, err = manager.UpdateWebhooks(func(ItemRecord) string { return "https://other.example.com/plaid" })
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(results[0].Err, ErrAccessTokenNotFound))
+	assert.NoError(t, results[1].Err)
+}
+
+func TestWebhookHost(t *testing.T) {
+	move := WebhookHost("hooks.example.com:8443")
+	assert.Equal(t, "https://hooks.example.com:8443/plaid?env=prod", move(ItemRecord{Webhook: "https://api.example.com/plaid?env=prod"}))
+	assert.Equal(t, "", move(ItemRecord{}))
+}
+
+func TestItemManagerRemoveItem(t *testing.T) {
+	server, f, manager := newItemManagerFixture(t)
+	_, err := manager.Exchange("1")
+	assert.NoError(t, err)
+
+	f.assetReports["assets-1"] = true
+	_, err = manager.TrackAssetReport("item-1", "assets-1")
+	assert.NoError(t, err)
+	_, err = manager.TrackAssetReport("item-1", "assets-2")
+	assert.NoError(t, err)
+	record, err := manager.TrackProcessorToken("item-1", "processor-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"assets-1", "assets-2"}, record.AssetReportTokens)
+	assert.Equal(t, []string{"processor-1"}, record.ProcessorTokens)
+
+	// assets-2 is unknown to Plaid, so it is already gone and does not stop
+	// the removal.
+	assert.NoError(t, manager.RemoveItem("item-1"))
+	assert.Empty(t, f.items)
+	assert.Empty(t, f.assetReports)
+	assert.Equal(t, 2, server.callCount("/asset_report/remove"))
+	_, err = manager.Item("item-1")
+	assert.Error(t, err)
+	_, err = manager.client.Vault().AccessToken("item-1")
+	assert.True(t, errors.Is(err, ErrAccessTokenNotFound))
+
+	// Any other failure to remove an asset report stops the removal and
+	// leaves the Item in place.
+	_, err = manager.Exchange("2")
+	assert.NoError(t, err)
+	f.assetReports["assets-3"] = true
+	_, err = manager.TrackAssetReport("item-2", "assets-3")
+	assert.NoError(t, err)
+	server.handle("/asset_report/remove", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusInternalServerError, map[string]interface{}{"error_type": "API_ERROR", "error_code": "INTERNAL_SERVER_ERROR"}
+	})
+	assert.Error(t, manager.RemoveItem("item-2"))
+	record, err = manager.Item("item-2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"assets-3"}, record.AssetReportTokens)
+	assert.Equal(t, 1, server.callCount("/item/remove"))
+}
+
+func TestItemManagerReconcile(t *testing.T) {
+	_, f, manager := newItemManagerFixture(t)
+	for _, publicToken := range []string{"1", "2", "3"} {
+		_, err := manager.Exchange(publicToken)
+		assert.NoError(t, err)
+	}
+	// item-2 was removed behind the manager's back, and item-3 lost its
+	// access token.
+	delete(f.items, "access-2")
+	assert.NoError(t, manager.client.Vault().DeleteAccessToken("item-3"))
+	// item-4 was exchanged without the manager, and item-5 too before being
+	// removed from Plaid.
+	f.items["access-4"] = &Item{ItemID: "item-4", InstitutionID: "ins_4", Webhook: "https://old.example.com/plaid"}
+	assert.NoError(t, manager.client.Vault().PutAccessToken("item-4", "access-4"))
+	assert.NoError(t, manager.client.Vault().PutAccessToken("item-5", "access-5"))
+
+	report, err := manager.Reconcile(ReconcileOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.Len(t, report.Orphans, 4)
+	assert.Equal(t, "item-2", report.Orphans[0].ItemID)
+	assert.Equal(t, OrphanReasons.ItemNotFound, report.Orphans[0].Reason)
+	assert.Equal(t, "item-3", report.Orphans[1].ItemID)
+	assert.Equal(t, OrphanReasons.MissingAccessToken, report.Orphans[1].Reason)
+	assert.Equal(t, "item-4", report.Orphans[2].ItemID)
+	assert.Equal(t, OrphanReasons.MissingRecord, report.Orphans[2].Reason)
+	assert.Equal(t, "ins_4", report.Orphans[2].InstitutionID)
+	assert.Equal(t, "item-5", report.Orphans[3].ItemID)
+	assert.Equal(t, OrphanReasons.ItemNotFound, report.Orphans[3].Reason)
+
+	records, err := manager.store.ListItems()
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+
+	report, err = manager.Reconcile(ReconcileOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, report.Orphans, 4)
+	for _, orphan := range report.Orphans {
+		assert.NoError(t, orphan.Err)
+	}
+	records, err = manager.store.ListItems()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "item-1", records[0].ItemID)
+	assert.Equal(t, "item-4", records[1].ItemID)
+	assert.Equal(t, "https://old.example.com/plaid", records[1].Webhook)
+	for _, itemID := range []string{"item-2", "item-5"} {
+		_, err = manager.client.Vault().AccessToken(itemID)
+		assert.True(t, errors.Is(err, ErrAccessTokenNotFound))
+	}
+
+	report, err = manager.Reconcile(ReconcileOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, report.Orphans)
+}
+
+func TestMemoryItemStoreCopiesRecords(t *testing.T) {
+	store := NewMemoryItemStore()
+	record := ItemRecord{ItemID: "item-1", ProcessorTokens: []string{"processor-1"}}
+	assert.NoError(t, store.PutItem(record))
+	record.ProcessorTokens[0] = "changed"
+
+	stored, ok, err := store.GetItem("item-1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"processor-1"}, stored.ProcessorTokens)
+}
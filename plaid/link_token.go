@@ -12,6 +12,40 @@ type LinkTokenUser struct {
 	EmailAddress             string    `json:"email_address,omitempty"`
 	PhoneNumberVerifiedTime  time.Time `json:"phone_number_verified_time,omitempty"`
 	EmailAddressVerifiedTime time.Time `json:"email_address_verified_time,omitempty"`
+
+	// Address, DateOfBirth and SSN prefill the forms of products that verify
+	// the user's identity, such as income verification.
+	Address *LinkTokenUserAddress `json:"address,omitempty"`
+	// DateOfBirth is formatted as YYYY-MM-DD.
+	DateOfBirth string `json:"date_of_birth,omitempty"`
+	SSN         string `json:"ssn,omitempty"`
+}
+
+type LinkTokenUserAddress struct {
+	Street     string `json:"street"`
+	Street2    string `json:"street2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// MarshalJSON leaves out the verification times when they are not set, which
+// omitempty does not do for time.Time values.
+func (u LinkTokenUser) MarshalJSON() ([]byte, error) {
+	type linkTokenUser LinkTokenUser
+	v := struct {
+		linkTokenUser
+		PhoneNumberVerifiedTime  *time.Time `json:"phone_number_verified_time,omitempty"`
+		EmailAddressVerifiedTime *time.Time `json:"email_address_verified_time,omitempty"`
+	}{linkTokenUser: linkTokenUser(u)}
+	if !u.PhoneNumberVerifiedTime.IsZero() {
+		v.PhoneNumberVerifiedTime = &u.PhoneNumberVerifiedTime
+	}
+	if !u.EmailAddressVerifiedTime.IsZero() {
+		v.EmailAddressVerifiedTime = &u.EmailAddressVerifiedTime
+	}
+	return json.Marshal(v)
 }
 
 type CrossAppItemAdd struct {
@@ -19,24 +53,98 @@ type CrossAppItemAdd struct {
 	ForeignID              string `json:"foreign_id,omitempty"`
 }
 
+// PaymentInitiation configures Link to authorise either a payment or a
+// payment consent. Exactly one of PaymentID and ConsentID must be set.
 type PaymentInitiation struct {
-	PaymentID string `json:"payment_id"`
+	PaymentID string `json:"payment_id,omitempty"`
+	ConsentID string `json:"consent_id,omitempty"`
+}
+
+// LinkTokenDepositSwitch configures Link for a deposit switch.
+type LinkTokenDepositSwitch struct {
+	DepositSwitchID string `json:"deposit_switch_id"`
+}
+
+// LinkTokenAuth enables the alternative flows Auth can use to verify accounts
+// at institutions that do not support instant verification.
+type LinkTokenAuth struct {
+	AutomatedMicrodepositsEnabled bool `json:"automated_microdeposits_enabled,omitempty"`
+	InstantMatchEnabled           bool `json:"instant_match_enabled,omitempty"`
+	SameDayMicrodepositsEnabled   bool `json:"same_day_microdeposits_enabled,omitempty"`
+}
+
+// LinkTokenInstitutionData narrows down the institutions shown in Link.
+type LinkTokenInstitutionData struct {
+	RoutingNumber string `json:"routing_number,omitempty"`
+}
+
+// LinkTokenUpdate configures update mode.
+type LinkTokenUpdate struct {
+	// AccountSelectionEnabled lets the user change which of the Item's
+	// accounts are shared.
+	AccountSelectionEnabled bool `json:"account_selection_enabled"`
+}
+
+// LinkTokenIncomeVerification configures Link for an income verification
+// created with the income product.
+type LinkTokenIncomeVerification struct {
+	IncomeVerificationID string   `json:"income_verification_id"`
+	AssetReportID        string   `json:"asset_report_id,omitempty"`
+	AccessTokens         []string `json:"access_tokens,omitempty"`
+}
+
+// LinkTokenEUConfig configures Link for institutions in the EU.
+type LinkTokenEUConfig struct {
+	// Headless hides Plaid's branding from the OAuth screens of EU
+	// institutions.
+	Headless bool `json:"headless"`
+}
+
+// LinkTokenAccountFilters limits the accounts a user can select in Link to
+// the given subtypes. Account types without a filter are not shown.
+type LinkTokenAccountFilters struct {
+	Depository *DepositoryAccountFilter `json:"depository,omitempty"`
+	Credit     *CreditAccountFilter     `json:"credit,omitempty"`
+	Loan       *LoanAccountFilter       `json:"loan,omitempty"`
+	Investment *InvestmentAccountFilter `json:"investment,omitempty"`
+}
+
+type DepositoryAccountFilter struct {
+	AccountSubtypes []DepositorySubtype `json:"account_subtypes"`
+}
+
+type CreditAccountFilter struct {
+	AccountSubtypes []CreditSubtype `json:"account_subtypes"`
+}
+
+type LoanAccountFilter struct {
+	AccountSubtypes []LoanSubtype `json:"account_subtypes"`
+}
+
+type InvestmentAccountFilter struct {
+	AccountSubtypes []InvestmentSubtype `json:"account_subtypes"`
 }
 
 type LinkTokenConfigs struct {
-	User                  *LinkTokenUser                  `json:"user"`
-	ClientName            string                          `json:"client_name"`
-	Products              []string                        `json:"products,omitempty"`
-	AccessToken           string                          `json:"access_token,omitempty"`
-	CountryCodes          []string                        `json:"country_codes,omitempty"`
-	Webhook               string                          `json:"webhook,omitempty"`
-	AccountFilters        *map[string]map[string][]string `json:"account_filters,omitempty"`
-	CrossAppItemAdd       *CrossAppItemAdd                `json:"cross_app_item_add,omitempty"`
-	PaymentInitiation     *PaymentInitiation              `json:"payment_initiation,omitempty"`
-	Language              string                          `json:"language,omitempty"`
-	LinkCustomizationName string                          `json:"link_customization_name,omitempty"`
-	RedirectUri           string                          `json:"redirect_uri,omitempty"`
-	AndroidPackageName    string                          `json:"android_package_name,omitempty"`
+	User                  *LinkTokenUser               `json:"user"`
+	ClientName            string                       `json:"client_name"`
+	Products              []string                     `json:"products,omitempty"`
+	AccessToken           string                       `json:"access_token,omitempty"`
+	CountryCodes          []string                     `json:"country_codes,omitempty"`
+	Webhook               string                       `json:"webhook,omitempty"`
+	AccountFilters        *LinkTokenAccountFilters     `json:"account_filters,omitempty"`
+	CrossAppItemAdd       *CrossAppItemAdd             `json:"cross_app_item_add,omitempty"`
+	PaymentInitiation     *PaymentInitiation           `json:"payment_initiation,omitempty"`
+	DepositSwitch         *LinkTokenDepositSwitch      `json:"deposit_switch,omitempty"`
+	IncomeVerification    *LinkTokenIncomeVerification `json:"income_verification,omitempty"`
+	Auth                  *LinkTokenAuth               `json:"auth,omitempty"`
+	InstitutionData       *LinkTokenInstitutionData    `json:"institution_data,omitempty"`
+	Update                *LinkTokenUpdate             `json:"update,omitempty"`
+	EUConfig              *LinkTokenEUConfig           `json:"eu_config,omitempty"`
+	Language              string                       `json:"language,omitempty"`
+	LinkCustomizationName string                       `json:"link_customization_name,omitempty"`
+	RedirectUri           string                       `json:"redirect_uri,omitempty"`
+	AndroidPackageName    string                       `json:"android_package_name,omitempty"`
 }
 
 type createLinkTokenRequest struct {
@@ -58,20 +166,75 @@ type CreateLinkTokenResponse struct {
 }
 
 type GetLinkTokenMetadataResponse struct {
-	InitialProducts []string                        `json:"initial_products"`
-	Webhook         string                          `json:"webhook"`
-	CountryCodes    []string                        `json:"country_codes"`
-	Language        string                          `json:"language"`
-	AccountFilters  *map[string]map[string][]string `json:"account_filters"`
-	RedirectURI     string                          `json:"redirect_uri"`
-	ClientName      string                          `json:"client_name"`
+	InitialProducts []string                  `json:"initial_products"`
+	Webhook         string                    `json:"webhook"`
+	CountryCodes    []string                  `json:"country_codes"`
+	Language        string                    `json:"language"`
+	InstitutionData *LinkTokenInstitutionData `json:"institution_data"`
+	AccountFilters  *LinkTokenAccountFilters  `json:"account_filters"`
+	RedirectURI     string                    `json:"redirect_uri"`
+	ClientName      string                    `json:"client_name"`
+}
+
+// LinkSession is a session in which the user opened Link with a link token.
+// FinishedAt, and one of OnSuccess and OnExit, are set once the user has
+// finished with Link.
+type LinkSession struct {
+	LinkSessionID string              `json:"link_session_id"`
+	StartedAt     time.Time           `json:"started_at"`
+	FinishedAt    *time.Time          `json:"finished_at"`
+	OnSuccess     *LinkSessionSuccess `json:"on_success"`
+	OnExit        *LinkSessionExit    `json:"on_exit"`
+}
+
+type LinkSessionInstitution struct {
+	Name          string `json:"name"`
+	InstitutionID string `json:"institution_id"`
 }
+
+type LinkSessionAccount struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Mask               string `json:"mask"`
+	Type               string `json:"type"`
+	Subtype            string `json:"subtype"`
+	VerificationStatus string `json:"verification_status"`
+}
+
+// LinkSessionSuccess is the result of a session in which the user linked an
+// Item, as passed to Link's onSuccess callback.
+type LinkSessionSuccess struct {
+	PublicToken string                     `json:"public_token"`
+	Metadata    LinkSessionSuccessMetadata `json:"metadata"`
+}
+
+type LinkSessionSuccessMetadata struct {
+	Institution   *LinkSessionInstitution `json:"institution"`
+	Accounts      []LinkSessionAccount    `json:"accounts"`
+	LinkSessionID string                  `json:"link_session_id"`
+}
+
+// LinkSessionExit is the result of a session the user left without linking
+// an Item, as passed to Link's onExit callback.
+type LinkSessionExit struct {
+	Error    *Error                  `json:"error"`
+	Metadata LinkSessionExitMetadata `json:"metadata"`
+}
+
+type LinkSessionExitMetadata struct {
+	Institution   *LinkSessionInstitution `json:"institution"`
+	Status        string                  `json:"status"`
+	LinkSessionID string                  `json:"link_session_id"`
+	RequestID     string                  `json:"request_id"`
+}
+
 type GetLinkTokenResponse struct {
 	APIResponse
-	LinkToken  string                       `json:"link_token"`
-	CreatedAt  time.Time                    `json:"created_at"`
-	Expiration time.Time                    `json:"expiration"`
-	Metadata   GetLinkTokenMetadataResponse `json:"metadata"`
+	LinkToken    string                       `json:"link_token"`
+	CreatedAt    time.Time                    `json:"created_at"`
+	Expiration   time.Time                    `json:"expiration"`
+	Metadata     GetLinkTokenMetadataResponse `json:"metadata"`
+	LinkSessions []LinkSession                `json:"link_sessions"`
 }
 
 func (c *Client) CreateLinkToken(configs LinkTokenConfigs) (resp CreateLinkTokenResponse, err error) {
@@ -0,0 +1,642 @@
+package plaid
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+type Product string
+
+const (
+	productAssets            Product = "assets"
+	productAuth              Product = "auth"
+	productDepositSwitch     Product = "deposit_switch"
+	productIdentity          Product = "identity"
+	productIncome            Product = "income"
+	productInvestments       Product = "investments"
+	productLiabilities       Product = "liabilities"
+	productPaymentInitiation Product = "payment_initiation"
+	productTransactions      Product = "transactions"
+)
+
+type products struct {
+	Assets            Product
+	Auth              Product
+	DepositSwitch     Product
+	Identity          Product
+	Income            Product
+	Investments       Product
+	Liabilities       Product
+	PaymentInitiation Product
+	Transactions      Product
+}
+
+var Products products = products{
+	Assets:            productAssets,
+	Auth:              productAuth,
+	DepositSwitch:     productDepositSwitch,
+	Identity:          productIdentity,
+	Income:            productIncome,
+	Investments:       productInvestments,
+	Liabilities:       productLiabilities,
+	PaymentInitiation: productPaymentInitiation,
+	Transactions:      productTransactions,
+}
+
+// LinkCountryCode is a country that Link supports institutions in.
+type LinkCountryCode string
+
+const (
+	linkCountryCodeCA LinkCountryCode = "CA"
+	linkCountryCodeES LinkCountryCode = "ES"
+	linkCountryCodeFR LinkCountryCode = "FR"
+	linkCountryCodeGB LinkCountryCode = "GB"
+	linkCountryCodeIE LinkCountryCode = "IE"
+	linkCountryCodeNL LinkCountryCode = "NL"
+	linkCountryCodeUS LinkCountryCode = "US"
+)
+
+type linkCountryCodes struct {
+	CA LinkCountryCode
+	ES LinkCountryCode
+	FR LinkCountryCode
+	GB LinkCountryCode
+	IE LinkCountryCode
+	NL LinkCountryCode
+	US LinkCountryCode
+}
+
+var LinkCountryCodes linkCountryCodes = linkCountryCodes{
+	CA: linkCountryCodeCA,
+	ES: linkCountryCodeES,
+	FR: linkCountryCodeFR,
+	GB: linkCountryCodeGB,
+	IE: linkCountryCodeIE,
+	NL: linkCountryCodeNL,
+	US: linkCountryCodeUS,
+}
+
+// LinkLanguage is a language that Link can be displayed in.
+type LinkLanguage string
+
+const (
+	linkLanguageEnglish LinkLanguage = "en"
+	linkLanguageFrench  LinkLanguage = "fr"
+	linkLanguageSpanish LinkLanguage = "es"
+	linkLanguageDutch   LinkLanguage = "nl"
+)
+
+type linkLanguages struct {
+	English LinkLanguage
+	French  LinkLanguage
+	Spanish LinkLanguage
+	Dutch   LinkLanguage
+}
+
+var LinkLanguages linkLanguages = linkLanguages{
+	English: linkLanguageEnglish,
+	French:  linkLanguageFrench,
+	Spanish: linkLanguageSpanish,
+	Dutch:   linkLanguageDutch,
+}
+
+type DepositorySubtype string
+
+const (
+	depositorySubtypeChecking DepositorySubtype = "checking"
+	depositorySubtypeSavings  DepositorySubtype = "savings"
+)
+
+type depositorySubtypes struct {
+	Checking DepositorySubtype
+	Savings  DepositorySubtype
+}
+
+var DepositorySubtypes depositorySubtypes = depositorySubtypes{
+	Checking: depositorySubtypeChecking,
+	Savings:  depositorySubtypeSavings,
+}
+
+type CreditSubtype string
+
+const (
+	creditSubtypeCreditCard CreditSubtype = "credit card"
+)
+
+type creditSubtypes struct {
+	CreditCard CreditSubtype
+}
+
+var CreditSubtypes creditSubtypes = creditSubtypes{
+	CreditCard: creditSubtypeCreditCard,
+}
+
+type LoanSubtype string
+
+const (
+	loanSubtypeAuto         LoanSubtype = "auto"
+	loanSubtypeCommercial   LoanSubtype = "commercial"
+	loanSubtypeConstruction LoanSubtype = "construction"
+	loanSubtypeConsumer     LoanSubtype = "consumer"
+	loanSubtypeHomeEquity   LoanSubtype = "home equity"
+	loanSubtypeLineOfCredit LoanSubtype = "line of credit"
+	loanSubtypeLoan         LoanSubtype = "loan"
+	loanSubtypeMortgage     LoanSubtype = "mortgage"
+	loanSubtypeOverdraft    LoanSubtype = "overdraft"
+	loanSubtypeStudent      LoanSubtype = "student"
+)
+
+type loanSubtypes struct {
+	Auto         LoanSubtype
+	Commercial   LoanSubtype
+	Construction LoanSubtype
+	Consumer     LoanSubtype
+	HomeEquity   LoanSubtype
+	LineOfCredit LoanSubtype
+	Loan         LoanSubtype
+	Mortgage     LoanSubtype
+	Overdraft    LoanSubtype
+	Student      LoanSubtype
+}
+
+var LoanSubtypes loanSubtypes = loanSubtypes{
+	Auto:         loanSubtypeAuto,
+	Commercial:   loanSubtypeCommercial,
+	Construction: loanSubtypeConstruction,
+	Consumer:     loanSubtypeConsumer,
+	HomeEquity:   loanSubtypeHomeEquity,
+	LineOfCredit: loanSubtypeLineOfCredit,
+	Loan:         loanSubtypeLoan,
+	Mortgage:     loanSubtypeMortgage,
+	Overdraft:    loanSubtypeOverdraft,
+	Student:      loanSubtypeStudent,
+}
+
+func (s LoanSubtype) valid() bool {
+	switch s {
+	case loanSubtypeAuto, loanSubtypeCommercial, loanSubtypeConstruction, loanSubtypeConsumer,
+		loanSubtypeHomeEquity, loanSubtypeLineOfCredit, loanSubtypeLoan, loanSubtypeMortgage,
+		loanSubtypeOverdraft, loanSubtypeStudent:
+		return true
+	}
+	return false
+}
+
+type InvestmentSubtype string
+
+const (
+	investmentSubtype401a       InvestmentSubtype = "401a"
+	investmentSubtype401k       InvestmentSubtype = "401k"
+	investmentSubtype403b       InvestmentSubtype = "403B"
+	investmentSubtype457b       InvestmentSubtype = "457b"
+	investmentSubtype529        InvestmentSubtype = "529"
+	investmentSubtypeBrokerage  InvestmentSubtype = "brokerage"
+	investmentSubtypeHSA        InvestmentSubtype = "hsa"
+	investmentSubtypeIRA        InvestmentSubtype = "ira"
+	investmentSubtypeMutualFund InvestmentSubtype = "mutual fund"
+	investmentSubtypePension    InvestmentSubtype = "pension"
+	investmentSubtypeRetirement InvestmentSubtype = "retirement"
+	investmentSubtypeRoth       InvestmentSubtype = "roth"
+	investmentSubtypeRoth401k   InvestmentSubtype = "roth 401k"
+	investmentSubtypeSEPIRA     InvestmentSubtype = "sep ira"
+	investmentSubtypeSimpleIRA  InvestmentSubtype = "simple ira"
+	investmentSubtypeStockPlan  InvestmentSubtype = "stock plan"
+)
+
+type investmentSubtypes struct {
+	Plan401a   InvestmentSubtype
+	Plan401k   InvestmentSubtype
+	Plan403b   InvestmentSubtype
+	Plan457b   InvestmentSubtype
+	Plan529    InvestmentSubtype
+	Brokerage  InvestmentSubtype
+	HSA        InvestmentSubtype
+	IRA        InvestmentSubtype
+	MutualFund InvestmentSubtype
+	Pension    InvestmentSubtype
+	Retirement InvestmentSubtype
+	Roth       InvestmentSubtype
+	Roth401k   InvestmentSubtype
+	SEPIRA     InvestmentSubtype
+	SimpleIRA  InvestmentSubtype
+	StockPlan  InvestmentSubtype
+}
+
+var InvestmentSubtypes investmentSubtypes = investmentSubtypes{
+	Plan401a:   investmentSubtype401a,
+	Plan401k:   investmentSubtype401k,
+	Plan403b:   investmentSubtype403b,
+	Plan457b:   investmentSubtype457b,
+	Plan529:    investmentSubtype529,
+	Brokerage:  investmentSubtypeBrokerage,
+	HSA:        investmentSubtypeHSA,
+	IRA:        investmentSubtypeIRA,
+	MutualFund: investmentSubtypeMutualFund,
+	Pension:    investmentSubtypePension,
+	Retirement: investmentSubtypeRetirement,
+	Roth:       investmentSubtypeRoth,
+	Roth401k:   investmentSubtypeRoth401k,
+	SEPIRA:     investmentSubtypeSEPIRA,
+	SimpleIRA:  investmentSubtypeSimpleIRA,
+	StockPlan:  investmentSubtypeStockPlan,
+}
+
+func (s InvestmentSubtype) valid() bool {
+	switch s {
+	case investmentSubtype401a, investmentSubtype401k, investmentSubtype403b, investmentSubtype457b,
+		investmentSubtype529, investmentSubtypeBrokerage, investmentSubtypeHSA, investmentSubtypeIRA,
+		investmentSubtypeMutualFund, investmentSubtypePension, investmentSubtypeRetirement,
+		investmentSubtypeRoth, investmentSubtypeRoth401k, investmentSubtypeSEPIRA,
+		investmentSubtypeSimpleIRA, investmentSubtypeStockPlan:
+		return true
+	}
+	return false
+}
+
+// AccountFilters limits the accounts a user can select in Link to the given
+// subtypes. Account types that are not filtered are not shown.
+type AccountFilters struct {
+	depository []DepositorySubtype
+	credit     []CreditSubtype
+	loan       []LoanSubtype
+	investment []InvestmentSubtype
+}
+
+// NewAccountFilters returns filters that show no accounts until subtypes are
+// added to them.
+func NewAccountFilters() *AccountFilters {
+	return &AccountFilters{}
+}
+
+// Depository shows depository accounts of the given subtypes.
+func (f *AccountFilters) Depository(subtypes ...DepositorySubtype) *AccountFilters {
+	f.depository = append(f.depository, subtypes...)
+	return f
+}
+
+// Credit shows credit accounts of the given subtypes.
+func (f *AccountFilters) Credit(subtypes ...CreditSubtype) *AccountFilters {
+	f.credit = append(f.credit, subtypes...)
+	return f
+}
+
+// Loan shows loan accounts of the given subtypes.
+func (f *AccountFilters) Loan(subtypes ...LoanSubtype) *AccountFilters {
+	f.loan = append(f.loan, subtypes...)
+	return f
+}
+
+// Investment shows investment accounts of the given subtypes.
+func (f *AccountFilters) Investment(subtypes ...InvestmentSubtype) *AccountFilters {
+	f.investment = append(f.investment, subtypes...)
+	return f
+}
+
+func (f *AccountFilters) validate() error {
+	if len(f.depository) == 0 && len(f.credit) == 0 && len(f.loan) == 0 && len(f.investment) == 0 {
+		return errors.New("link token - account filters must include at least one subtype")
+	}
+	for _, subtype := range f.depository {
+		if subtype != depositorySubtypeChecking && subtype != depositorySubtypeSavings {
+			return fmt.Errorf("link token - unknown depository subtype %q", subtype)
+		}
+	}
+	for _, subtype := range f.credit {
+		if subtype != creditSubtypeCreditCard {
+			return fmt.Errorf("link token - unknown credit subtype %q", subtype)
+		}
+	}
+	for _, subtype := range f.loan {
+		if !subtype.valid() {
+			return fmt.Errorf("link token - unknown loan subtype %q", subtype)
+		}
+	}
+	for _, subtype := range f.investment {
+		if !subtype.valid() {
+			return fmt.Errorf("link token - unknown investment subtype %q", subtype)
+		}
+	}
+	return nil
+}
+
+// configs returns the filters as LinkTokenConfigs.AccountFilters.
+func (f *AccountFilters) configs() *LinkTokenAccountFilters {
+	var filters LinkTokenAccountFilters
+	if len(f.depository) > 0 {
+		filters.Depository = &DepositoryAccountFilter{AccountSubtypes: f.depository}
+	}
+	if len(f.credit) > 0 {
+		filters.Credit = &CreditAccountFilter{AccountSubtypes: f.credit}
+	}
+	if len(f.loan) > 0 {
+		filters.Loan = &LoanAccountFilter{AccountSubtypes: f.loan}
+	}
+	if len(f.investment) > 0 {
+		filters.Investment = &InvestmentAccountFilter{AccountSubtypes: f.investment}
+	}
+	return &filters
+}
+
+type linkMode string
+
+const (
+	linkModeNewItem           linkMode = "new item"
+	linkModeUpdate            linkMode = "update mode"
+	linkModePaymentInitiation linkMode = "payment initiation"
+	linkModeDepositSwitch     linkMode = "deposit switch"
+	linkModeIncome            linkMode = "income verification"
+)
+
+// LinkTokenBuilder builds the LinkTokenConfigs for one of Link's modes,
+// checking them for combinations Plaid would reject. Start from
+// NewItemLinkToken, UpdateModeLinkToken, PaymentInitiationLinkToken,
+// PaymentConsentLinkToken, DepositSwitchLinkToken or
+// IncomeVerificationLinkToken, then call Build:
+//
+//	configs, err := plaid.NewItemLinkToken("My App", user, plaid.Products.Auth).
+//		CountryCodes(plaid.LinkCountryCodes.US).
+//		AccountFilters(plaid.NewAccountFilters().Depository(plaid.DepositorySubtypes.Checking)).
+//		Build()
+type LinkTokenBuilder struct {
+	mode           linkMode
+	configs        LinkTokenConfigs
+	products       []Product
+	countryCodes   []LinkCountryCode
+	language       LinkLanguage
+	accountFilters *AccountFilters
+}
+
+func newLinkTokenBuilder(mode linkMode, clientName string, user LinkTokenUser) *LinkTokenBuilder {
+	return &LinkTokenBuilder{
+		mode: mode,
+		configs: LinkTokenConfigs{
+			User:       &user,
+			ClientName: clientName,
+		},
+		language: linkLanguageEnglish,
+	}
+}
+
+// NewItemLinkToken starts a link token for linking a new Item with the given
+// products.
+func NewItemLinkToken(clientName string, user LinkTokenUser, products ...Product) *LinkTokenBuilder {
+	b := newLinkTokenBuilder(linkModeNewItem, clientName, user)
+	b.products = products
+	return b
+}
+
+// UpdateModeLinkToken starts a link token for repairing the login of an
+// existing Item. Update mode uses the Item's products, so none are given.
+func UpdateModeLinkToken(clientName string, user LinkTokenUser, accessToken string) *LinkTokenBuilder {
+	b := newLinkTokenBuilder(linkModeUpdate, clientName, user)
+	b.configs.AccessToken = accessToken
+	return b
+}
+
+// PaymentInitiationLinkToken starts a link token for authorising a payment
+// created with CreatePayment.
+func PaymentInitiationLinkToken(clientName string, user LinkTokenUser, paymentID string) *LinkTokenBuilder {
+	b := newLinkTokenBuilder(linkModePaymentInitiation, clientName, user)
+	b.products = []Product{productPaymentInitiation}
+	b.configs.PaymentInitiation = &PaymentInitiation{PaymentID: paymentID}
+	return b
+}
+
+// PaymentConsentLinkToken starts a link token for authorising a payment
+// consent created with CreatePaymentConsent.
+func PaymentConsentLinkToken(clientName string, user LinkTokenUser, consentID string) *LinkTokenBuilder {
+	b := newLinkTokenBuilder(linkModePaymentInitiation, clientName, user)
+	b.products = []Product{productPaymentInitiation}
+	b.configs.PaymentInitiation = &PaymentInitiation{ConsentID: consentID}
+	return b
+}
+
+// DepositSwitchLinkToken starts a link token for a deposit switch created with
+// CreateDepositSwitch.
+func DepositSwitchLinkToken(clientName string, user LinkTokenUser, depositSwitchID string) *LinkTokenBuilder {
+	b := newLinkTokenBuilder(linkModeDepositSwitch, clientName, user)
+	b.products = []Product{productDepositSwitch}
+	b.configs.DepositSwitch = &LinkTokenDepositSwitch{DepositSwitchID: depositSwitchID}
+	return b
+}
+
+// IncomeVerificationLinkToken starts a link token for an income verification.
+func IncomeVerificationLinkToken(clientName string, user LinkTokenUser, incomeVerificationID string) *LinkTokenBuilder {
+	b := newLinkTokenBuilder(linkModeIncome, clientName, user)
+	b.products = []Product{productIncome}
+	b.configs.IncomeVerification = &LinkTokenIncomeVerification{IncomeVerificationID: incomeVerificationID}
+	return b
+}
+
+// CountryCodes sets the countries whose institutions are shown in Link.
+func (b *LinkTokenBuilder) CountryCodes(countryCodes ...LinkCountryCode) *LinkTokenBuilder {
+	b.countryCodes = countryCodes
+	return b
+}
+
+// Language sets the language Link is displayed in. It defaults to English.
+func (b *LinkTokenBuilder) Language(language LinkLanguage) *LinkTokenBuilder {
+	b.language = language
+	return b
+}
+
+func (b *LinkTokenBuilder) Webhook(webhook string) *LinkTokenBuilder {
+	b.configs.Webhook = webhook
+	return b
+}
+
+// RedirectURI sets the URI Link returns to after an OAuth institution's flow
+// on the web or iOS. It must be registered in the Plaid dashboard.
+func (b *LinkTokenBuilder) RedirectURI(redirectURI string) *LinkTokenBuilder {
+	b.configs.RedirectUri = redirectURI
+	return b
+}
+
+// AndroidPackageName sets the app Link returns to after an OAuth
+// institution's flow on Android, in place of a redirect URI.
+func (b *LinkTokenBuilder) AndroidPackageName(packageName string) *LinkTokenBuilder {
+	b.configs.AndroidPackageName = packageName
+	return b
+}
+
+func (b *LinkTokenBuilder) LinkCustomizationName(name string) *LinkTokenBuilder {
+	b.configs.LinkCustomizationName = name
+	return b
+}
+
+// Auth enables Auth's micro-deposit and instant match flows. It can only be
+// used when linking a new Item with the auth product.
+func (b *LinkTokenBuilder) Auth(auth LinkTokenAuth) *LinkTokenBuilder {
+	b.configs.Auth = &auth
+	return b
+}
+
+// RoutingNumber limits Link to the institutions with a routing number.
+func (b *LinkTokenBuilder) RoutingNumber(routingNumber string) *LinkTokenBuilder {
+	b.configs.InstitutionData = &LinkTokenInstitutionData{RoutingNumber: routingNumber}
+	return b
+}
+
+// AccountSelectionEnabled lets the user change which accounts an Item shares.
+// It can only be used in update mode.
+func (b *LinkTokenBuilder) AccountSelectionEnabled() *LinkTokenBuilder {
+	b.configs.Update = &LinkTokenUpdate{AccountSelectionEnabled: true}
+	return b
+}
+
+func (b *LinkTokenBuilder) EUConfig(config LinkTokenEUConfig) *LinkTokenBuilder {
+	b.configs.EUConfig = &config
+	return b
+}
+
+// AccountFilters limits the accounts the user can select. They can only be
+// used when linking a new Item.
+func (b *LinkTokenBuilder) AccountFilters(filters *AccountFilters) *LinkTokenBuilder {
+	b.accountFilters = filters
+	return b
+}
+
+// Build validates the link token and returns the configs to pass to
+// CreateLinkToken.
+func (b *LinkTokenBuilder) Build() (LinkTokenConfigs, error) {
+	if err := b.validate(); err != nil {
+		return LinkTokenConfigs{}, err
+	}
+
+	configs := b.configs
+	for _, product := range b.products {
+		configs.Products = append(configs.Products, string(product))
+	}
+	for _, countryCode := range b.countryCodes {
+		configs.CountryCodes = append(configs.CountryCodes, string(countryCode))
+	}
+	configs.Language = string(b.language)
+	if b.accountFilters != nil {
+		configs.AccountFilters = b.accountFilters.configs()
+	}
+	return configs, nil
+}
+
+func (b *LinkTokenBuilder) validate() error {
+	if b.configs.ClientName == "" {
+		return errors.New("link token - client name must be specified")
+	}
+	if b.configs.User.ClientUserID == "" {
+		return errors.New("link token - user client user id must be specified")
+	}
+
+	switch b.mode {
+	case linkModeNewItem:
+		if len(b.products) == 0 {
+			return errors.New("link token - at least one product must be specified")
+		}
+		for _, product := range b.products {
+			switch product {
+			case productAssets, productAuth, productIdentity, productInvestments,
+				productLiabilities, productTransactions:
+			case productPaymentInitiation, productDepositSwitch, productIncome:
+				return fmt.Errorf("link token - %s has its own link token, it cannot be combined with other products", product)
+			default:
+				return fmt.Errorf("link token - unknown product %q", product)
+			}
+		}
+	case linkModeUpdate:
+		if b.configs.AccessToken == "" {
+			return errors.New("link token - update mode access token must be specified")
+		}
+	case linkModePaymentInitiation:
+		if b.configs.PaymentInitiation.PaymentID == "" && b.configs.PaymentInitiation.ConsentID == "" {
+			return errors.New("link token - payment initiation payment or consent id must be specified")
+		}
+		// Payment initiation is always authorised through the bank's OAuth
+		// flow, which Link has to be able to return from.
+		if b.configs.RedirectUri == "" && b.configs.AndroidPackageName == "" {
+			return errors.New("link token - payment initiation requires a redirect uri or android package name")
+		}
+	case linkModeDepositSwitch:
+		if b.configs.DepositSwitch.DepositSwitchID == "" {
+			return errors.New("link token - deposit switch id must be specified")
+		}
+	case linkModeIncome:
+		if b.configs.IncomeVerification.IncomeVerificationID == "" {
+			return errors.New("link token - income verification id must be specified")
+		}
+	}
+
+	if b.configs.Auth != nil && !b.hasProduct(productAuth) {
+		return errors.New("link token - auth flows can only be used with the auth product")
+	}
+	if b.configs.Update != nil && b.mode != linkModeUpdate {
+		return fmt.Errorf("link token - account selection cannot be enabled in %s", b.mode)
+	}
+
+	if b.accountFilters != nil {
+		if b.mode != linkModeNewItem {
+			return fmt.Errorf("link token - account filters cannot be used in %s", b.mode)
+		}
+		if err := b.accountFilters.validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(b.countryCodes) == 0 {
+		return errors.New("link token - at least one country code must be specified")
+	}
+	for _, countryCode := range b.countryCodes {
+		switch countryCode {
+		case linkCountryCodeUS, linkCountryCodeCA:
+			if b.mode == linkModePaymentInitiation {
+				return fmt.Errorf("link token - payment initiation is not available in %s", countryCode)
+			}
+		case linkCountryCodeGB, linkCountryCodeES, linkCountryCodeFR, linkCountryCodeIE, linkCountryCodeNL:
+		default:
+			return fmt.Errorf("link token - unknown country code %q", countryCode)
+		}
+		if b.mode == linkModeDepositSwitch && countryCode != linkCountryCodeUS {
+			return fmt.Errorf("link token - deposit switch is not available in %s", countryCode)
+		}
+	}
+
+	switch b.language {
+	case linkLanguageEnglish, linkLanguageFrench, linkLanguageSpanish, linkLanguageDutch:
+	default:
+		return fmt.Errorf("link token - unknown language %q", b.language)
+	}
+
+	if b.configs.RedirectUri != "" && b.configs.AndroidPackageName != "" {
+		return errors.New("link token - only one of redirect uri and android package name can be specified")
+	}
+	if b.configs.RedirectUri != "" {
+		if err := validateRedirectURI(b.configs.RedirectUri); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *LinkTokenBuilder) hasProduct(product Product) bool {
+	for _, p := range b.products {
+		if p == product {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRedirectURI checks a redirect URI meets Plaid's OAuth requirements:
+// it must use https, other than for localhost during development, and must
+// not have a query string or fragment.
+func validateRedirectURI(redirectURI string) error {
+	u, err := url.Parse(redirectURI)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("link token - redirect uri %q must be an absolute url", redirectURI)
+	}
+	if u.Scheme != "https" && !(u.Scheme == "http" && u.Hostname() == "localhost") {
+		return fmt.Errorf("link token - redirect uri %q must use https", redirectURI)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("link token - redirect uri %q must not have a query or fragment", redirectURI)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package plaid
+
+import (
+	"encoding/json"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+var builderUser = LinkTokenUser{ClientUserID: "user-1"}
+
+func TestNewItemLinkToken(t *testing.T) {
+	configs, err := NewItemLinkToken("Plaid Test", builderUser, Products.Auth, Products.Transactions).
+		CountryCodes(LinkCountryCodes.US, LinkCountryCodes.CA).
+		Webhook("https://www.example.com/webhook").
+		RedirectURI("https://www.example.com/oauth").
+		AccountFilters(NewAccountFilters().
+			Depository(DepositorySubtypes.Checking, DepositorySubtypes.Savings).
+			Credit(CreditSubtypes.CreditCard).
+			Loan(LoanSubtypes.Student, LoanSubtypes.Mortgage).
+			Investment(InvestmentSubtypes.Plan401k, InvestmentSubtypes.Plan403b)).
+		Build()
+	assert.Nil(t, err)
+
+	b, err := json.Marshal(configs)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"user": {"client_user_id": "user-1"},
+		"client_name": "Plaid Test",
+		"products": ["auth", "transactions"],
+		"country_codes": ["US", "CA"],
+		"language": "en",
+		"webhook": "https://www.example.com/webhook",
+		"redirect_uri": "https://www.example.com/oauth",
+		"account_filters": {
+			"depository": {"account_subtypes": ["checking", "savings"]},
+			"credit": {"account_subtypes": ["credit card"]},
+			"loan": {"account_subtypes": ["student", "mortgage"]},
+			"investment": {"account_subtypes": ["401k", "403B"]}
+		}
+	}`, string(b))
+}
+
+func TestLinkTokenModes(t *testing.T) {
+	configs, err := UpdateModeLinkToken("Plaid Test", builderUser, "access-sandbox-1").
+		CountryCodes(LinkCountryCodes.US).
+		Language(LinkLanguages.Spanish).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "access-sandbox-1", configs.AccessToken)
+	assert.Empty(t, configs.Products)
+	assert.Equal(t, "es", configs.Language)
+
+	configs, err = PaymentInitiationLinkToken("Plaid Test", builderUser, "payment-id-sandbox-1").
+		CountryCodes(LinkCountryCodes.GB).
+		RedirectURI("https://www.example.com/oauth").
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"payment_initiation"}, configs.Products)
+	assert.Equal(t, "payment-id-sandbox-1", configs.PaymentInitiation.PaymentID)
+
+	configs, err = PaymentConsentLinkToken("Plaid Test", builderUser, "consent-id-sandbox-1").
+		CountryCodes(LinkCountryCodes.GB).
+		AndroidPackageName("com.example.app").
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "consent-id-sandbox-1", configs.PaymentInitiation.ConsentID)
+
+	configs, err = DepositSwitchLinkToken("Plaid Test", builderUser, "deposit-switch-id-1").
+		CountryCodes(LinkCountryCodes.US).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"deposit_switch"}, configs.Products)
+
+	b, err := json.Marshal(configs)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"deposit_switch":{"deposit_switch_id":"deposit-switch-id-1"}`)
+}
+
+func TestLinkTokenBuilderOptions(t *testing.T) {
+	configs, err := NewItemLinkToken("Plaid Test", builderUser, Products.Auth).
+		CountryCodes(LinkCountryCodes.US).
+		Auth(LinkTokenAuth{SameDayMicrodepositsEnabled: true, InstantMatchEnabled: true}).
+		RoutingNumber("011401533").
+		Build()
+	assert.Nil(t, err)
+	assert.True(t, configs.Auth.SameDayMicrodepositsEnabled)
+	assert.Equal(t, "011401533", configs.InstitutionData.RoutingNumber)
+
+	configs, err = UpdateModeLinkToken("Plaid Test", builderUser, "access-sandbox-1").
+		CountryCodes(LinkCountryCodes.US).
+		AccountSelectionEnabled().
+		Build()
+	assert.Nil(t, err)
+	assert.True(t, configs.Update.AccountSelectionEnabled)
+
+	configs, err = IncomeVerificationLinkToken("Plaid Test", builderUser, "income-verification-id-1").
+		CountryCodes(LinkCountryCodes.US).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"income"}, configs.Products)
+	assert.Equal(t, "income-verification-id-1", configs.IncomeVerification.IncomeVerificationID)
+
+	configs, err = PaymentInitiationLinkToken("Plaid Test", builderUser, "payment-id-sandbox-1").
+		CountryCodes(LinkCountryCodes.FR).
+		RedirectURI("https://www.example.com/oauth").
+		EUConfig(LinkTokenEUConfig{Headless: true}).
+		Build()
+	assert.Nil(t, err)
+	assert.True(t, configs.EUConfig.Headless)
+
+	_, err = NewItemLinkToken("Plaid Test", builderUser, Products.Transactions).
+		CountryCodes(LinkCountryCodes.US).
+		Auth(LinkTokenAuth{SameDayMicrodepositsEnabled: true}).
+		Build()
+	assert.Equal(t, "link token - auth flows can only be used with the auth product", err.Error())
+
+	_, err = NewItemLinkToken("Plaid Test", builderUser, Products.Auth).
+		CountryCodes(LinkCountryCodes.US).
+		AccountSelectionEnabled().
+		Build()
+	assert.Equal(t, "link token - account selection cannot be enabled in new item", err.Error())
+
+	_, err = NewItemLinkToken("Plaid Test", builderUser, Products.Auth, Products.Income).
+		CountryCodes(LinkCountryCodes.US).
+		Build()
+	assert.Equal(t, "link token - income has its own link token, it cannot be combined with other products", err.Error())
+}
+
+func TestLinkTokenBuilderValidation(t *testing.T) {
+	us := LinkCountryCodes.US
+	cases := []struct {
+		name    string
+		builder *LinkTokenBuilder
+		err     string
+	}{
+		{"no client name", NewItemLinkToken("", builderUser, Products.Auth).CountryCodes(us),
+			"link token - client name must be specified"},
+		{"no user", NewItemLinkToken("Plaid Test", LinkTokenUser{}, Products.Auth).CountryCodes(us),
+			"link token - user client user id must be specified"},
+		{"no products", NewItemLinkToken("Plaid Test", builderUser).CountryCodes(us),
+			"link token - at least one product must be specified"},
+		{"combined payment initiation", NewItemLinkToken("Plaid Test", builderUser, Products.Auth, Products.PaymentInitiation).CountryCodes(us),
+			"link token - payment_initiation has its own link token, it cannot be combined with other products"},
+		{"unknown product", NewItemLinkToken("Plaid Test", builderUser, Product("bogus")).CountryCodes(us),
+			`link token - unknown product "bogus"`},
+		{"no country codes", NewItemLinkToken("Plaid Test", builderUser, Products.Auth),
+			"link token - at least one country code must be specified"},
+		{"unknown country code", NewItemLinkToken("Plaid Test", builderUser, Products.Auth).CountryCodes("XX"),
+			`link token - unknown country code "XX"`},
+		{"unknown language", NewItemLinkToken("Plaid Test", builderUser, Products.Auth).CountryCodes(us).Language("de"),
+			`link token - unknown language "de"`},
+		{"update mode without access token", UpdateModeLinkToken("Plaid Test", builderUser, "").CountryCodes(us),
+			"link token - update mode access token must be specified"},
+		{"update mode with account filters", UpdateModeLinkToken("Plaid Test", builderUser, "access-sandbox-1").CountryCodes(us).
+			AccountFilters(NewAccountFilters().Depository(DepositorySubtypes.Checking)),
+			"link token - account filters cannot be used in update mode"},
+		{"empty account filters", NewItemLinkToken("Plaid Test", builderUser, Products.Auth).CountryCodes(us).
+			AccountFilters(NewAccountFilters()),
+			"link token - account filters must include at least one subtype"},
+		{"unknown loan subtype", NewItemLinkToken("Plaid Test", builderUser, Products.Liabilities).CountryCodes(us).
+			AccountFilters(NewAccountFilters().Loan("boat")),
+			`link token - unknown loan subtype "boat"`},
+		{"unknown investment subtype", NewItemLinkToken("Plaid Test", builderUser, Products.Investments).CountryCodes(us).
+			AccountFilters(NewAccountFilters().Investment("401K")),
+			`link token - unknown investment subtype "401K"`},
+		{"payment initiation without redirect", PaymentInitiationLinkToken("Plaid Test", builderUser, "payment-id-sandbox-1").CountryCodes(LinkCountryCodes.GB),
+			"link token - payment initiation requires a redirect uri or android package name"},
+		{"payment initiation in US", PaymentInitiationLinkToken("Plaid Test", builderUser, "payment-id-sandbox-1").CountryCodes(us).
+			RedirectURI("https://www.example.com/oauth"),
+			"link token - payment initiation is not available in US"},
+		{"deposit switch in GB", DepositSwitchLinkToken("Plaid Test", builderUser, "deposit-switch-id-1").CountryCodes(LinkCountryCodes.GB),
+			"link token - deposit switch is not available in GB"},
+		{"redirect uri and package name", NewItemLinkToken("Plaid Test", builderUser, Products.Auth).CountryCodes(us).
+			RedirectURI("https://www.example.com/oauth").AndroidPackageName("com.example.app"),
+			"link token - only one of redirect uri and android package name can be specified"},
+		{"http redirect uri", NewItemLinkToken("Plaid Test", builderUser, Products.Auth).CountryCodes(us).
+			RedirectURI("http://www.example.com/oauth"),
+			`link token - redirect uri "http://www.example.com/oauth" must use https`},
+		{"redirect uri with query", NewItemLinkToken("Plaid Test", builderUser, Products.Auth).CountryCodes(us).
+			RedirectURI("https://www.example.com/oauth?state=1"),
+			`link token - redirect uri "https://www.example.com/oauth?state=1" must not have a query or fragment`},
+		{"relative redirect uri", NewItemLinkToken("Plaid Test", builderUser, Products.Auth).CountryCodes(us).
+			RedirectURI("/oauth"),
+			`link token - redirect uri "/oauth" must be an absolute url`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := c.builder.Build()
+			assert.NotNil(t, err)
+			assert.Equal(t, c.err, err.Error())
+		})
+	}
+
+	_, err := NewItemLinkToken("Plaid Test", builderUser, Products.Auth).CountryCodes(us).
+		RedirectURI("http://localhost:3000/oauth").Build()
+	assert.Nil(t, err)
+}
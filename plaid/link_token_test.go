@@ -1,6 +1,7 @@
 package plaid
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -41,9 +42,9 @@ func TestCreateLinkTokenOptional(t *testing.T) {
 		Language:              "en",
 		Webhook:               "https://webhook-uri.com",
 		LinkCustomizationName: "default",
-		AccountFilters: &map[string]map[string][]string{
-			"depository": {
-				"account_subtypes": {"checking", "savings"},
+		AccountFilters: &LinkTokenAccountFilters{
+			Depository: &DepositoryAccountFilter{
+				AccountSubtypes: []DepositorySubtype{DepositorySubtypes.Checking, DepositorySubtypes.Savings},
 			},
 		},
 	})
@@ -68,9 +69,9 @@ func TestCreateLinkTokenThenGet(t *testing.T) {
 		Products:     []string{"auth"},
 		CountryCodes: []string{"US"},
 		Webhook:      "https://webhook-uri.com",
-		AccountFilters: &map[string]map[string][]string{
-			"depository": {
-				"account_subtypes": {"checking", "savings"},
+		AccountFilters: &LinkTokenAccountFilters{
+			Depository: &DepositoryAccountFilter{
+				AccountSubtypes: []DepositorySubtype{DepositorySubtypes.Checking, DepositorySubtypes.Savings},
 			},
 		},
 		Language:              "en",
@@ -97,3 +98,18 @@ func TestCreateLinkTokenThenGet(t *testing.T) {
 	assert.NotZero(t, getLinkTokenResp.Expiration)
 	assert.NotZero(t, getLinkTokenResp.CreatedAt)
 }
+
+func TestLinkTokenUserOmitsUnsetVerificationTimes(t *testing.T) {
+	encoded, err := json.Marshal(LinkTokenUser{ClientUserID: "user-1"})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"client_user_id": "user-1"}`, string(encoded))
+
+	verified := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	encoded, err = json.Marshal(LinkTokenUser{ClientUserID: "user-1", PhoneNumber: "+14155555555", PhoneNumberVerifiedTime: verified})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"client_user_id": "user-1",
+		"phone_number": "+14155555555",
+		"phone_number_verified_time": "2021-03-01T12:00:00Z"
+	}`, string(encoded))
+}
@@ -0,0 +1,300 @@
+// Package linkserver serves a minimal Link page for linking Items during local
+// development, so that Link flows can be tried without writing a web page.
+//
+// The page creates a link token with CreateLinkToken and opens Link with it.
+// When the user links an Item, Link's onSuccess callback posts the public
+// token and metadata back to the server, which exchanges the public token
+// for an access token and reports the Item. In sandbox mode the server skips
+// Link entirely and creates the public token with CreateSandboxPublicToken,
+// so that it can run in tests without a browser.
+package linkserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+)
+
+// Item is an Item linked through the server.
+type Item struct {
+	AccessToken   string          `json:"access_token"`
+	ItemID        string          `json:"item_id"`
+	InstitutionID string          `json:"institution_id,omitempty"`
+	LinkedAt      time.Time       `json:"linked_at"`
+	Metadata      json.RawMessage `json:"metadata,omitempty"`
+}
+
+// Options configures a Server.
+type Options struct {
+	// LinkToken configures the link tokens the page opens Link with.
+	LinkToken plaid.LinkTokenConfigs
+
+	// Sandbox links Items with CreateSandboxPublicToken instead of Link.
+	Sandbox bool
+	// SandboxInstitutionID is the institution sandbox Items are created at.
+	// It defaults to First Platypus Bank.
+	SandboxInstitutionID string
+
+	// Output, if set, has a line written to it for every linked Item.
+	Output io.Writer
+	// File, if set, has every linked Item appended to it as a line of JSON.
+	// It is created readable by its owner only, as it holds access tokens.
+	File string
+	// OnItem, if set, is called with every linked Item, for example to store
+	// it elsewhere.
+	OnItem func(Item) error
+}
+
+// DefaultSandboxInstitutionID is First Platypus Bank, the sandbox institution
+// that supports every product.
+const DefaultSandboxInstitutionID = "ins_109508"
+
+// Server links Items, either through Link or in sandbox mode directly.
+type Server struct {
+	client  *plaid.Client
+	options Options
+
+	mu    sync.Mutex
+	items chan Item
+}
+
+// New returns a server that links Items with client.
+func New(client *plaid.Client, options Options) *Server {
+	if options.SandboxInstitutionID == "" {
+		options.SandboxInstitutionID = DefaultSandboxInstitutionID
+	}
+	return &Server{
+		client:  client,
+		options: options,
+		items:   make(chan Item, 1),
+	}
+}
+
+// Run links one Item and returns it. In sandbox mode the Item is linked
+// straight away; otherwise the Link page is served on addr until an Item is
+// linked through it or ctx is done.
+func (s *Server) Run(ctx context.Context, addr string) (Item, error) {
+	if s.options.Sandbox {
+		return s.LinkSandboxItem()
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return Item{}, err
+	}
+	server := &http.Server{Handler: s}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(listener)
+	}()
+	if s.options.Output != nil {
+		fmt.Fprintf(s.options.Output, "open http://%s to link an Item\n", listener.Addr())
+	}
+
+	// Shutdown rather than Close, so that the callback that linked the Item
+	// gets its response.
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+	select {
+	case item := <-s.items:
+		return item, nil
+	case err := <-errs:
+		return Item{}, err
+	case <-ctx.Done():
+		return Item{}, ctx.Err()
+	}
+}
+
+// LinkSandboxItem links an Item with CreateSandboxPublicToken, using the
+// products of the link token configs.
+func (s *Server) LinkSandboxItem() (Item, error) {
+	products := s.options.LinkToken.Products
+	if len(products) == 0 {
+		return Item{}, errors.New("linkserver - at least one product must be specified")
+	}
+
+	token, err := s.client.CreateSandboxPublicToken(s.options.SandboxInstitutionID, products)
+	if err != nil {
+		return Item{}, err
+	}
+	return s.exchange(token.PublicToken, s.options.SandboxInstitutionID, nil)
+}
+
+// ServeHTTP serves the Link page at /, link tokens for it at /link_token and
+// Link's onSuccess results at /callback.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = page.Execute(w, nil)
+	case "/link_token":
+		s.serveLinkToken(w, r)
+	case "/callback":
+		s.serveCallback(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveLinkToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp, err := s.client.CreateLinkToken(s.options.LinkToken)
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"link_token": resp.LinkToken})
+}
+
+// callback is what the page posts from Link's onSuccess callback.
+type callback struct {
+	PublicToken string          `json:"public_token"`
+	Metadata    json.RawMessage `json:"metadata"`
+}
+
+func (s *Server) serveCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body callback
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.PublicToken == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "public_token must be specified"})
+		return
+	}
+
+	var metadata struct {
+		Institution struct {
+			InstitutionID string `json:"institution_id"`
+		} `json:"institution"`
+	}
+	_ = json.Unmarshal(body.Metadata, &metadata)
+
+	item, err := s.exchange(body.PublicToken, metadata.Institution.InstitutionID, body.Metadata)
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+
+	select {
+	case s.items <- item:
+	default:
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"item_id": item.ItemID})
+}
+
+// exchange exchanges a public token for an access token and reports the Item.
+func (s *Server) exchange(publicToken string, institutionID string, metadata json.RawMessage) (Item, error) {
+	resp, err := s.client.ExchangePublicToken(publicToken)
+	if err != nil {
+		return Item{}, err
+	}
+
+	item := Item{
+		AccessToken:   resp.AccessToken,
+		ItemID:        resp.ItemID,
+		InstitutionID: institutionID,
+		LinkedAt:      time.Now().UTC(),
+		Metadata:      metadata,
+	}
+	return item, s.report(item)
+}
+
+func (s *Server) report(item Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.options.Output != nil {
+		fmt.Fprintf(s.options.Output, "linked item %s: access token %s\n", item.ItemID, item.AccessToken)
+	}
+	if s.options.File != "" {
+		if err := appendItem(s.options.File, item); err != nil {
+			return err
+		}
+	}
+	if s.options.OnItem != nil {
+		return s.options.OnItem(item)
+	}
+	return nil
+}
+
+func appendItem(path string, item Item) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(item); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>plaid-go link server</title>
+<script src="https://cdn.plaid.com/link/v2/stable/link-initialize.js"></script>
+</head>
+<body>
+<p id="status">Creating a link token...</p>
+<script>
+const status = document.getElementById("status");
+
+async function post(path, body) {
+  const res = await fetch(path, {
+    method: "POST",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify(body || {}),
+  });
+  const json = await res.json();
+  if (!res.ok) {
+    throw new Error(json.error);
+  }
+  return json;
+}
+
+post("/link_token").then(({link_token}) => {
+  status.textContent = "Opening Link...";
+  Plaid.create({
+    token: link_token,
+    onSuccess: (public_token, metadata) => {
+      status.textContent = "Exchanging the public token...";
+      post("/callback", {public_token, metadata})
+        .then(({item_id}) => { status.textContent = "Linked item " + item_id + ", you can close this page."; })
+        .catch((err) => { status.textContent = err.message; });
+    },
+    onExit: (err) => {
+      status.textContent = err ? err.display_message || err.error_message : "Link was closed, reload to try again.";
+    },
+  }).open();
+}).catch((err) => { status.textContent = err.message; });
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,147 @@
+package linkserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+	assert "github.com/stretchr/testify/require"
+)
+
+// newPlaid returns a client for a fake Plaid API that serves the endpoints
+// the server calls.
+func newPlaid(t *testing.T) *plaid.Client {
+	responses := map[string]interface{}{
+		"/link/token/create":           map[string]string{"link_token": "link-sandbox-1", "expiration": "2021-03-02T00:00:00Z"},
+		"/sandbox/public_token/create": map[string]string{"public_token": "public-sandbox-1"},
+		"/item/public_token/exchange":  map[string]string{"access_token": "access-sandbox-1", "item_id": "item-1"},
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(plaid.Error{ErrorType: "INVALID_REQUEST", ErrorCode: "NOT_FOUND"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(api.Close)
+
+	client, err := plaid.NewClient(plaid.ClientOptions{
+		ClientID:    "client-id",
+		Secret:      "secret",
+		Environment: plaid.Environment(api.URL),
+		HTTPClient:  api.Client(),
+	})
+	assert.Nil(t, err)
+	return client
+}
+
+var linkToken = plaid.LinkTokenConfigs{
+	User:         &plaid.LinkTokenUser{ClientUserID: "user-1"},
+	ClientName:   "Plaid Test",
+	Products:     []string{"auth"},
+	CountryCodes: []string{"US"},
+	Language:     "en",
+}
+
+func TestSandbox(t *testing.T) {
+	var out bytes.Buffer
+	var stored []Item
+	file := filepath.Join(t.TempDir(), "items.jsonl")
+
+	s := New(newPlaid(t), Options{
+		LinkToken: linkToken,
+		Sandbox:   true,
+		Output:    &out,
+		File:      file,
+		OnItem: func(item Item) error {
+			stored = append(stored, item)
+			return nil
+		},
+	})
+
+	item, err := s.Run(context.Background(), "")
+	assert.Nil(t, err)
+	assert.Equal(t, "access-sandbox-1", item.AccessToken)
+	assert.Equal(t, "item-1", item.ItemID)
+	assert.Equal(t, DefaultSandboxInstitutionID, item.InstitutionID)
+	assert.Equal(t, []Item{item}, stored)
+	assert.Contains(t, out.String(), "linked item item-1: access token access-sandbox-1")
+
+	info, err := os.Stat(file)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	data, err := os.ReadFile(file)
+	assert.Nil(t, err)
+	var saved Item
+	assert.Nil(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, item.ItemID, saved.ItemID)
+	assert.Equal(t, item.AccessToken, saved.AccessToken)
+}
+
+func TestSandboxRequiresProducts(t *testing.T) {
+	s := New(newPlaid(t), Options{Sandbox: true})
+	_, err := s.LinkSandboxItem()
+	assert.NotNil(t, err)
+}
+
+func TestLinkPage(t *testing.T) {
+	var stored []Item
+	s := New(newPlaid(t), Options{
+		LinkToken: linkToken,
+		OnItem: func(item Item) error {
+			stored = append(stored, item)
+			return nil
+		},
+	})
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/")
+	assert.Nil(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res, err = http.Post(server.URL+"/link_token", "application/json", nil)
+	assert.Nil(t, err)
+	var token map[string]string
+	assert.Nil(t, json.NewDecoder(res.Body).Decode(&token))
+	res.Body.Close()
+	assert.Equal(t, "link-sandbox-1", token["link_token"])
+
+	res, err = http.Post(server.URL+"/callback", "application/json", strings.NewReader(`{
+		"public_token": "public-sandbox-1",
+		"metadata": {"institution": {"name": "First Platypus Bank", "institution_id": "ins_109508"}}
+	}`))
+	assert.Nil(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	assert.Len(t, stored, 1)
+	assert.Equal(t, "access-sandbox-1", stored[0].AccessToken)
+	assert.Equal(t, "ins_109508", stored[0].InstitutionID)
+	assert.Contains(t, string(stored[0].Metadata), "First Platypus Bank")
+
+	res, err = http.Post(server.URL+"/callback", "application/json", strings.NewReader(`{}`))
+	assert.Nil(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestRunStopsWithContext(t *testing.T) {
+	s := New(newPlaid(t), Options{LinkToken: linkToken})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := s.Run(ctx, "127.0.0.1:0")
+	assert.Equal(t, context.Canceled, err)
+}
@@ -2,7 +2,9 @@ package plaid
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -15,24 +17,91 @@ type PaymentRecipientAddress struct {
 	Country string `json:"country"`
 }
 
+// Validate checks that the address has one to four street lines, a city, a
+// postal code and an ISO 3166-1 alpha-2 country code.
+func (a PaymentRecipientAddress) Validate() error {
+	if len(a.Street) < 1 || len(a.Street) > 4 {
+		return fmt.Errorf("address - street must have between 1 and 4 lines, not %d", len(a.Street))
+	}
+	for _, line := range a.Street {
+		if strings.TrimSpace(line) == "" {
+			return errors.New("address - street lines must not be empty")
+		}
+	}
+	if a.City == "" {
+		return errors.New("address - city must be specified")
+	}
+	if a.PostalCode == "" {
+		return errors.New("address - postal code must be specified")
+	}
+	if err := ValidateCountryCode(a.Country); err != nil {
+		return errors.New("address - " + err.Error())
+	}
+	return nil
+}
+
+type PaymentRecipientBacs struct {
+	Account  string `json:"account"`
+	SortCode string `json:"sort_code"`
+}
+
+// PaymentRecipient is the name, account and address of a payment recipient.
+// Fields that are not set are left out of requests.
+type PaymentRecipient struct {
+	Name    string                   `json:"name"`
+	IBAN    *string                  `json:"iban,omitempty"`
+	BACS    *PaymentRecipientBacs    `json:"bacs,omitempty"`
+	Address *PaymentRecipientAddress `json:"address,omitempty"`
+}
+
+// NewPaymentRecipient validates a recipient before it is created with
+// CreatePaymentRecipientWithOptions. The recipient must have a name and an
+// IBAN, BACS account details or both; the account numbers and the address,
+// if any, must be valid.
+func NewPaymentRecipient(name string, iban *string, bacs *PaymentRecipientBacs, address *PaymentRecipientAddress) (PaymentRecipient, error) {
+	recipient := PaymentRecipient{
+		Name:    name,
+		IBAN:    iban,
+		BACS:    bacs,
+		Address: address,
+	}
+	if strings.TrimSpace(name) == "" {
+		return recipient, errors.New("payment recipient - name must be specified")
+	}
+	if iban == nil && bacs == nil {
+		return recipient, errors.New("payment recipient - IBAN or BACS must be specified")
+	}
+	if iban != nil {
+		if err := ValidateIBAN(*iban); err != nil {
+			return recipient, errors.New("payment recipient - " + err.Error())
+		}
+	}
+	if bacs != nil {
+		if err := bacs.Validate(); err != nil {
+			return recipient, errors.New("payment recipient - " + err.Error())
+		}
+	}
+	if address != nil {
+		if err := address.Validate(); err != nil {
+			return recipient, errors.New("payment recipient - " + err.Error())
+		}
+	}
+	return recipient, nil
+}
+
 type createPaymentRecipientRequest struct {
-	ClientID string                   `json:"client_id"`
-	Secret   string                   `json:"secret"`
-	Name     string                   `json:"name"`
-	IBAN     *string                  `json:"iban",omitempty`
-	Address  *PaymentRecipientAddress `json:"address",omitempty`
-	BACS     *PaymentRecipientBacs    `json:"bacs",omitempty`
+	ClientID string `json:"client_id"`
+	Secret   string `json:"secret"`
+	PaymentRecipient
 }
 
 type OptionalRecipientCreateParams struct {
 	Address *PaymentRecipientAddress
 	BACS    *PaymentRecipientBacs
 	IBAN    *string
-}
-
-type PaymentRecipientBacs struct {
-	Account  string `json:"account"`
-	SortCode string `json:"sort_code"`
+	// IdempotencyKey deduplicates replayed calls through the client's
+	// IdempotencyStore.
+	IdempotencyKey string
 }
 
 type CreatePaymentRecipientResponse struct {
@@ -43,21 +112,51 @@ type CreatePaymentRecipientResponse struct {
 func (c *Client) CreatePaymentRecipient(
 	name string,
 	params OptionalRecipientCreateParams,
+) (resp CreatePaymentRecipientResponse, err error) {
+	if c.validateAccountNumbers {
+		if err := validatePaymentRecipientNumbers(params); err != nil {
+			return resp, err
+		}
+	}
+
+	return c.createPaymentRecipient(PaymentRecipient{
+		Name:    name,
+		Address: params.Address,
+		IBAN:    params.IBAN,
+		BACS:    params.BACS,
+	}, params.IdempotencyKey)
+}
+
+type CreatePaymentRecipientOptions struct {
+	// IdempotencyKey deduplicates replayed calls through the client's
+	// IdempotencyStore.
+	IdempotencyKey string
+}
+
+// CreatePaymentRecipientWithOptions creates a recipient built with
+// NewPaymentRecipient.
+func (c *Client) CreatePaymentRecipientWithOptions(
+	recipient PaymentRecipient,
+	options CreatePaymentRecipientOptions,
+) (resp CreatePaymentRecipientResponse, err error) {
+	return c.createPaymentRecipient(recipient, options.IdempotencyKey)
+}
+
+func (c *Client) createPaymentRecipient(
+	recipient PaymentRecipient,
+	idempotencyKey string,
 ) (resp CreatePaymentRecipientResponse, err error) {
 	jsonBody, err := json.Marshal(createPaymentRecipientRequest{
-		ClientID: c.clientID,
-		Secret:   c.secret,
-		Name:     name,
-		Address:  params.Address,
-		IBAN:     params.IBAN,
-		BACS:     params.BACS,
+		ClientID:         c.clientID,
+		Secret:           c.secret,
+		PaymentRecipient: recipient,
 	})
 
 	if err != nil {
 		return resp, err
 	}
 
-	err = c.Call("/payment_initiation/recipient/create", jsonBody, &resp)
+	err = c.callIdempotent("/payment_initiation/recipient/create", idempotencyKey, jsonBody, &resp)
 	return resp, err
 }
 
@@ -68,11 +167,8 @@ type getPaymentRecipientRequest struct {
 }
 
 type Recipient struct {
-	RecipientID string                   `json:"recipient_id"`
-	Name        string                   `json:"name"`
-	IBAN        *string                  `json:"iban",omitempty`
-	Address     *PaymentRecipientAddress `json:"address"`
-	BACS        *PaymentRecipientBacs    `json:"bacs",omitempty`
+	RecipientID string `json:"recipient_id"`
+	PaymentRecipient
 }
 
 type GetPaymentRecipientResponse struct {
@@ -120,37 +216,126 @@ func (c *Client) ListPaymentRecipients() (resp ListPaymentRecipientsResponse, er
 }
 
 type PaymentAmount struct {
-	Currency string  `json:"currency"`
-	Value    float64 `json:"value"`
+	Currency Currency `json:"currency"`
+	Value    float64  `json:"value"`
+}
+
+// dateLayout is the layout of the dates Plaid formats as YYYY-MM-DD.
+const dateLayout = "2006-01-02"
+
+type PaymentScheduleInterval string
+
+const (
+	paymentScheduleIntervalWeekly  PaymentScheduleInterval = "WEEKLY"
+	paymentScheduleIntervalMonthly PaymentScheduleInterval = "MONTHLY"
+)
+
+type paymentScheduleIntervals struct {
+	Weekly  PaymentScheduleInterval
+	Monthly PaymentScheduleInterval
 }
 
+var PaymentScheduleIntervals paymentScheduleIntervals = paymentScheduleIntervals{
+	Weekly:  paymentScheduleIntervalWeekly,
+	Monthly: paymentScheduleIntervalMonthly,
+}
+
+// PaymentSchedule is the schedule of a standing order. Dates are formatted as
+// YYYY-MM-DD.
 type PaymentSchedule struct {
-	Interval             string `json:"interval"`
+	Interval PaymentScheduleInterval `json:"interval"`
+	// IntervalExecutionDay is the day payments are made on: 1 (Monday) to 7
+	// (Sunday) for weekly schedules, and 1 to 28 for monthly schedules, or -1
+	// to -5 to count back from the last day of the month.
 	IntervalExecutionDay int    `json:"interval_execution_day"`
 	StartDate            string `json:"start_date"`
+	// EndDate is the date of the last payment. Standing orders without one
+	// run until they are cancelled.
+	EndDate string `json:"end_date,omitempty"`
+	// AdjustedStartDate is returned by Plaid when the first payment cannot be
+	// made on StartDate, and is the date it will be made on instead.
+	AdjustedStartDate string `json:"adjusted_start_date,omitempty"`
+}
+
+// Validate checks the interval, execution day and dates of the schedule
+// before it is sent to Plaid.
+func (s PaymentSchedule) Validate() error {
+	switch s.Interval {
+	case paymentScheduleIntervalWeekly:
+		if s.IntervalExecutionDay < 1 || s.IntervalExecutionDay > 7 {
+			return fmt.Errorf("payment schedule - interval execution day %d must be between 1 and 7 for %s schedules", s.IntervalExecutionDay, s.Interval)
+		}
+	case paymentScheduleIntervalMonthly:
+		if s.IntervalExecutionDay == 0 || s.IntervalExecutionDay < -5 || s.IntervalExecutionDay > 28 {
+			return fmt.Errorf("payment schedule - interval execution day %d must be between 1 and 28, or -1 and -5, for %s schedules", s.IntervalExecutionDay, s.Interval)
+		}
+	default:
+		return fmt.Errorf("payment schedule - unknown interval %q", s.Interval)
+	}
+
+	start, err := time.Parse(dateLayout, s.StartDate)
+	if err != nil {
+		return fmt.Errorf("payment schedule - start date %q must be formatted as YYYY-MM-DD", s.StartDate)
+	}
+	if s.EndDate != "" {
+		end, err := time.Parse(dateLayout, s.EndDate)
+		if err != nil {
+			return fmt.Errorf("payment schedule - end date %q must be formatted as YYYY-MM-DD", s.EndDate)
+		}
+		if end.Before(start) {
+			return fmt.Errorf("payment schedule - end date %s is before start date %s", s.EndDate, s.StartDate)
+		}
+	}
+	return nil
+}
+
+// PaymentRefundDetails is the account a payment can be refunded to, as
+// returned when refund details were requested for the payment.
+type PaymentRefundDetails struct {
+	Name string                `json:"name"`
+	IBAN *string               `json:"iban"`
+	BACS *PaymentRecipientBacs `json:"bacs"`
+}
+
+type createPaymentRequestOptions struct {
+	RequestRefundDetails bool `json:"request_refund_details,omitempty"`
 }
 
 type createPaymentRequest struct {
-	ClientID    string        `json:"client_id"`
-	Secret      string        `json:"secret"`
-	RecipientID string        `json:"recipient_id"`
-	Reference   string        `json:"reference"`
-	Amount      PaymentAmount `json:"amount"`
+	ClientID    string                       `json:"client_id"`
+	Secret      string                       `json:"secret"`
+	RecipientID string                       `json:"recipient_id"`
+	Reference   string                       `json:"reference"`
+	Amount      PaymentAmount                `json:"amount"`
+	Options     *createPaymentRequestOptions `json:"options,omitempty"`
 }
 
 type createStandingOrderRequest struct {
-	ClientID    string           `json:"client_id"`
-	Secret      string           `json:"secret"`
-	RecipientID string           `json:"recipient_id"`
-	Reference   string           `json:"reference"`
-	Amount      PaymentAmount    `json:"amount"`
-	Schedule    *PaymentSchedule `json:"schedule"`
+	ClientID    string                       `json:"client_id"`
+	Secret      string                       `json:"secret"`
+	RecipientID string                       `json:"recipient_id"`
+	Reference   string                       `json:"reference"`
+	Amount      PaymentAmount                `json:"amount"`
+	Schedule    *PaymentSchedule             `json:"schedule"`
+	Options     *createPaymentRequestOptions `json:"options,omitempty"`
 }
 
 type CreatePaymentResponse struct {
 	APIResponse
-	PaymentID string `json:"payment_id"`
-	Status    string `json:"status"`
+	PaymentID string        `json:"payment_id"`
+	Status    PaymentStatus `json:"status"`
+}
+
+type CreatePaymentOptions struct {
+	// Schedule makes the payment a standing order. It is validated before
+	// the payment is created.
+	Schedule *PaymentSchedule
+	// RequestRefundDetails asks Plaid to return the details of the account
+	// the payment is made from, in Payment.RefundDetails.
+	RequestRefundDetails bool
+	// IdempotencyKey deduplicates replayed calls through the client's
+	// IdempotencyStore.
+	IdempotencyKey string
 }
 
 func (c *Client) CreatePayment(
@@ -159,30 +344,51 @@ func (c *Client) CreatePayment(
 	amount PaymentAmount,
 	schedule *PaymentSchedule,
 ) (resp CreatePaymentResponse, err error) {
+	return c.CreatePaymentWithOptions(recipientID, reference, amount, CreatePaymentOptions{
+		Schedule: schedule,
+	})
+}
+
+func (c *Client) CreatePaymentWithOptions(
+	recipientID string,
+	reference string,
+	amount PaymentAmount,
+	options CreatePaymentOptions,
+) (resp CreatePaymentResponse, err error) {
+	var requestOptions *createPaymentRequestOptions
+	if options.RequestRefundDetails {
+		requestOptions = &createPaymentRequestOptions{RequestRefundDetails: true}
+	}
+
 	var jsonBody []byte
-	if schedule == nil {
+	if options.Schedule == nil {
 		jsonBody, err = json.Marshal(createPaymentRequest{
 			ClientID:    c.clientID,
 			Secret:      c.secret,
 			RecipientID: recipientID,
 			Reference:   reference,
 			Amount:      amount,
+			Options:     requestOptions,
 		})
 	} else {
+		if err := options.Schedule.Validate(); err != nil {
+			return resp, err
+		}
 		jsonBody, err = json.Marshal(createStandingOrderRequest{
 			ClientID:    c.clientID,
 			Secret:      c.secret,
 			RecipientID: recipientID,
 			Reference:   reference,
 			Amount:      amount,
-			Schedule:    schedule,
+			Schedule:    options.Schedule,
+			Options:     requestOptions,
 		})
 	}
 	if err != nil {
 		return resp, err
 	}
 
-	err = c.Call("/payment_initiation/payment/create", jsonBody, &resp)
+	err = c.callIdempotent("/payment_initiation/payment/create", options.IdempotencyKey, jsonBody, &resp)
 	return resp, err
 }
 
@@ -198,6 +404,7 @@ type CreatePaymentTokenResponse struct {
 	PaymentTokenExpirationTime time.Time `json:"payment_token_expiration_time"`
 }
 
+// CreatePaymentToken creates a token for initialising Link for a payment.
 func (c *Client) CreatePaymentToken(
 	paymentID string,
 ) (resp CreatePaymentTokenResponse, err error) {
@@ -227,9 +434,12 @@ type Payment struct {
 	Reference        string           `json:"reference"`
 	Amount           PaymentAmount    `json:"amount"`
 	Schedule         *PaymentSchedule `json:"schedule"`
-	Status           string           `json:"status"`
+	Status           PaymentStatus    `json:"status"`
 	LastStatusUpdate time.Time        `json:"last_status_update"`
 	RecipientID      string           `json:"recipient_id"`
+	// ConsentID is set for payments executed under a payment consent.
+	ConsentID     string                `json:"consent_id,omitempty"`
+	RefundDetails *PaymentRefundDetails `json:"refund_details,omitempty"`
 }
 
 type GetPaymentResponse struct {
@@ -254,8 +464,8 @@ func (c *Client) GetPayment(paymentID string) (resp GetPaymentResponse, err erro
 type listPaymentsRequest struct {
 	ClientID string  `json:"client_id"`
 	Secret   string  `json:"secret"`
-	Count    *int    `json:"count"`
-	Cursor   *string `json:"cursor"`
+	Count    *int    `json:"count,omitempty"`
+	Cursor   *string `json:"cursor,omitempty"`
 }
 
 type ListPaymentsResponse struct {
@@ -0,0 +1,321 @@
+package plaid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type PaymentConsentStatus string
+
+const (
+	paymentConsentStatusUnauthorised PaymentConsentStatus = "UNAUTHORISED"
+	paymentConsentStatusAuthorised   PaymentConsentStatus = "AUTHORISED"
+	paymentConsentStatusRevoked      PaymentConsentStatus = "REVOKED"
+	paymentConsentStatusRejected     PaymentConsentStatus = "REJECTED"
+	paymentConsentStatusExpired      PaymentConsentStatus = "EXPIRED"
+)
+
+type paymentConsentStatuses struct {
+	// Unauthorised consents are waiting for the user to authorise them in
+	// Link.
+	Unauthorised PaymentConsentStatus
+	// Authorised consents can be used to execute payments.
+	Authorised PaymentConsentStatus
+	Revoked    PaymentConsentStatus
+	Rejected   PaymentConsentStatus
+	Expired    PaymentConsentStatus
+}
+
+var PaymentConsentStatuses paymentConsentStatuses = paymentConsentStatuses{
+	Unauthorised: paymentConsentStatusUnauthorised,
+	Authorised:   paymentConsentStatusAuthorised,
+	Revoked:      paymentConsentStatusRevoked,
+	Rejected:     paymentConsentStatusRejected,
+	Expired:      paymentConsentStatusExpired,
+}
+
+type PaymentConsentScope string
+
+const (
+	paymentConsentScopeMeToMe   PaymentConsentScope = "ME_TO_ME"
+	paymentConsentScopeExternal PaymentConsentScope = "EXTERNAL"
+)
+
+type paymentConsentScopes struct {
+	// MeToMe allows payments between accounts owned by the same user.
+	MeToMe PaymentConsentScope
+	// External allows payments to accounts owned by someone else.
+	External PaymentConsentScope
+}
+
+var PaymentConsentScopes paymentConsentScopes = paymentConsentScopes{
+	MeToMe:   paymentConsentScopeMeToMe,
+	External: paymentConsentScopeExternal,
+}
+
+type PaymentConsentPeriodicInterval string
+
+const (
+	paymentConsentPeriodicIntervalDay   PaymentConsentPeriodicInterval = "DAY"
+	paymentConsentPeriodicIntervalWeek  PaymentConsentPeriodicInterval = "WEEK"
+	paymentConsentPeriodicIntervalMonth PaymentConsentPeriodicInterval = "MONTH"
+	paymentConsentPeriodicIntervalYear  PaymentConsentPeriodicInterval = "YEAR"
+)
+
+type paymentConsentPeriodicIntervals struct {
+	Day   PaymentConsentPeriodicInterval
+	Week  PaymentConsentPeriodicInterval
+	Month PaymentConsentPeriodicInterval
+	Year  PaymentConsentPeriodicInterval
+}
+
+var PaymentConsentPeriodicIntervals paymentConsentPeriodicIntervals = paymentConsentPeriodicIntervals{
+	Day:   paymentConsentPeriodicIntervalDay,
+	Week:  paymentConsentPeriodicIntervalWeek,
+	Month: paymentConsentPeriodicIntervalMonth,
+	Year:  paymentConsentPeriodicIntervalYear,
+}
+
+type PaymentConsentPeriodicAlignment string
+
+const (
+	paymentConsentPeriodicAlignmentConsent  PaymentConsentPeriodicAlignment = "CONSENT"
+	paymentConsentPeriodicAlignmentCalendar PaymentConsentPeriodicAlignment = "CALENDAR"
+)
+
+type paymentConsentPeriodicAlignments struct {
+	// Consent periods start on the day the consent was created.
+	Consent PaymentConsentPeriodicAlignment
+	// Calendar periods start at the beginning of the calendar day, week,
+	// month or year.
+	Calendar PaymentConsentPeriodicAlignment
+}
+
+var PaymentConsentPeriodicAlignments paymentConsentPeriodicAlignments = paymentConsentPeriodicAlignments{
+	Consent:  paymentConsentPeriodicAlignmentConsent,
+	Calendar: paymentConsentPeriodicAlignmentCalendar,
+}
+
+// PaymentConsentPeriodicAmount limits the total amount of the payments made
+// under a consent within each period.
+type PaymentConsentPeriodicAmount struct {
+	Amount    PaymentAmount                   `json:"amount"`
+	Interval  PaymentConsentPeriodicInterval  `json:"interval"`
+	Alignment PaymentConsentPeriodicAlignment `json:"alignment"`
+}
+
+// PaymentConsentValidDateTime is the period within which payments can be made
+// under a consent. Either bound may be left unset.
+type PaymentConsentValidDateTime struct {
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// PaymentConsentConstraints limits the payments that can be made under a
+// consent.
+type PaymentConsentConstraints struct {
+	ValidDateTime *PaymentConsentValidDateTime `json:"valid_date_time,omitempty"`
+	// MaxPaymentAmount is the largest amount a single payment can be for.
+	MaxPaymentAmount PaymentAmount                  `json:"max_payment_amount"`
+	PeriodicAmounts  []PaymentConsentPeriodicAmount `json:"periodic_amounts"`
+}
+
+// Validate checks that the constraints are complete and consistent before
+// they are sent to Plaid.
+func (c PaymentConsentConstraints) Validate() error {
+	if c.MaxPaymentAmount.Currency == "" || c.MaxPaymentAmount.Value <= 0 {
+		return errors.New("payment consent constraints - max payment amount must be positive")
+	}
+	if len(c.PeriodicAmounts) == 0 {
+		return errors.New("payment consent constraints - at least one periodic amount must be specified")
+	}
+	for _, periodic := range c.PeriodicAmounts {
+		switch periodic.Interval {
+		case paymentConsentPeriodicIntervalDay, paymentConsentPeriodicIntervalWeek,
+			paymentConsentPeriodicIntervalMonth, paymentConsentPeriodicIntervalYear:
+		default:
+			return fmt.Errorf("payment consent constraints - unknown periodic interval %q", periodic.Interval)
+		}
+		switch periodic.Alignment {
+		case paymentConsentPeriodicAlignmentConsent, paymentConsentPeriodicAlignmentCalendar:
+		default:
+			return fmt.Errorf("payment consent constraints - unknown periodic alignment %q", periodic.Alignment)
+		}
+		if periodic.Amount.Value <= 0 {
+			return fmt.Errorf("payment consent constraints - %s amount must be positive", periodic.Interval)
+		}
+		if periodic.Amount.Currency != c.MaxPaymentAmount.Currency {
+			return fmt.Errorf("payment consent constraints - %s amount is in %s, not %s", periodic.Interval, periodic.Amount.Currency, c.MaxPaymentAmount.Currency)
+		}
+	}
+	if v := c.ValidDateTime; v != nil && v.From != nil && v.To != nil && !v.To.After(*v.From) {
+		return errors.New("payment consent constraints - valid date time must end after it starts")
+	}
+	return nil
+}
+
+type createPaymentConsentRequest struct {
+	ClientID    string                    `json:"client_id"`
+	Secret      string                    `json:"secret"`
+	RecipientID string                    `json:"recipient_id"`
+	Reference   string                    `json:"reference"`
+	Scopes      []PaymentConsentScope     `json:"scopes"`
+	Constraints PaymentConsentConstraints `json:"constraints"`
+}
+
+type CreatePaymentConsentResponse struct {
+	APIResponse
+	ConsentID string               `json:"consent_id"`
+	Status    PaymentConsentStatus `json:"status"`
+}
+
+// CreatePaymentConsent creates a consent for payments to a recipient within
+// the given constraints. The consent must be authorised by the user in Link,
+// using a link token created with its ID, before payments are executed under
+// it with ExecutePaymentConsent.
+// See https://plaid.com/docs/api/products/payment-initiation/#payment_initiationconsentcreate.
+func (c *Client) CreatePaymentConsent(
+	recipientID string,
+	reference string,
+	scopes []PaymentConsentScope,
+	constraints PaymentConsentConstraints,
+) (resp CreatePaymentConsentResponse, err error) {
+	if recipientID == "" {
+		return resp, errors.New("/payment_initiation/consent/create - recipient id must be specified")
+	}
+	if len(scopes) == 0 {
+		return resp, errors.New("/payment_initiation/consent/create - scopes must be specified")
+	}
+	if err := constraints.Validate(); err != nil {
+		return resp, err
+	}
+
+	jsonBody, err := json.Marshal(createPaymentConsentRequest{
+		ClientID:    c.clientID,
+		Secret:      c.secret,
+		RecipientID: recipientID,
+		Reference:   reference,
+		Scopes:      scopes,
+		Constraints: constraints,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("/payment_initiation/consent/create", jsonBody, &resp)
+	return resp, err
+}
+
+type paymentConsentRequest struct {
+	ClientID  string `json:"client_id"`
+	Secret    string `json:"secret"`
+	ConsentID string `json:"consent_id"`
+}
+
+type PaymentConsent struct {
+	ConsentID   string                    `json:"consent_id"`
+	Status      PaymentConsentStatus      `json:"status"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	RecipientID string                    `json:"recipient_id"`
+	Reference   string                    `json:"reference"`
+	Scopes      []PaymentConsentScope     `json:"scopes"`
+	Constraints PaymentConsentConstraints `json:"constraints"`
+}
+
+type GetPaymentConsentResponse struct {
+	APIResponse
+	PaymentConsent
+}
+
+// GetPaymentConsent retrieves a payment consent.
+func (c *Client) GetPaymentConsent(consentID string) (resp GetPaymentConsentResponse, err error) {
+	if consentID == "" {
+		return resp, errors.New("/payment_initiation/consent/get - consent id must be specified")
+	}
+
+	jsonBody, err := json.Marshal(paymentConsentRequest{
+		ClientID:  c.clientID,
+		Secret:    c.secret,
+		ConsentID: consentID,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("/payment_initiation/consent/get", jsonBody, &resp)
+	return resp, err
+}
+
+type RevokePaymentConsentResponse struct {
+	APIResponse
+}
+
+// RevokePaymentConsent revokes a payment consent, so that no more payments can
+// be executed under it.
+func (c *Client) RevokePaymentConsent(consentID string) (resp RevokePaymentConsentResponse, err error) {
+	if consentID == "" {
+		return resp, errors.New("/payment_initiation/consent/revoke - consent id must be specified")
+	}
+
+	jsonBody, err := json.Marshal(paymentConsentRequest{
+		ClientID:  c.clientID,
+		Secret:    c.secret,
+		ConsentID: consentID,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.Call("/payment_initiation/consent/revoke", jsonBody, &resp)
+	return resp, err
+}
+
+type executePaymentConsentRequest struct {
+	ClientID       string        `json:"client_id"`
+	Secret         string        `json:"secret"`
+	ConsentID      string        `json:"consent_id"`
+	Amount         PaymentAmount `json:"amount"`
+	IdempotencyKey string        `json:"idempotency_key"`
+}
+
+type ExecutePaymentConsentResponse struct {
+	APIResponse
+	PaymentID string        `json:"payment_id"`
+	Status    PaymentStatus `json:"status"`
+}
+
+// ExecutePaymentConsent makes a payment under an authorised consent. Plaid
+// requires an idempotency key, which it uses to make sure a replayed call does
+// not make a second payment; the key is also used with the client's
+// IdempotencyStore, if it has one.
+func (c *Client) ExecutePaymentConsent(
+	consentID string,
+	amount PaymentAmount,
+	idempotencyKey string,
+) (resp ExecutePaymentConsentResponse, err error) {
+	if consentID == "" {
+		return resp, errors.New("/payment_initiation/consent/payment/execute - consent id must be specified")
+	}
+	if idempotencyKey == "" {
+		return resp, errors.New("/payment_initiation/consent/payment/execute - idempotency key must be specified")
+	}
+	if amount.Currency == "" || amount.Value <= 0 {
+		return resp, errors.New("/payment_initiation/consent/payment/execute - amount must be positive")
+	}
+
+	jsonBody, err := json.Marshal(executePaymentConsentRequest{
+		ClientID:       c.clientID,
+		Secret:         c.secret,
+		ConsentID:      consentID,
+		Amount:         amount,
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	err = c.callIdempotent("/payment_initiation/consent/payment/execute", idempotencyKey, jsonBody, &resp)
+	return resp, err
+}
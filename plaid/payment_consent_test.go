@@ -0,0 +1,132 @@
+package plaid
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func testPaymentConsentConstraints() PaymentConsentConstraints {
+	from := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+	return PaymentConsentConstraints{
+		ValidDateTime:    &PaymentConsentValidDateTime{From: &from, To: &to},
+		MaxPaymentAmount: PaymentAmount{Currency: "GBP", Value: 100},
+		PeriodicAmounts: []PaymentConsentPeriodicAmount{{
+			Amount:    PaymentAmount{Currency: "GBP", Value: 500},
+			Interval:  PaymentConsentPeriodicIntervals.Month,
+			Alignment: PaymentConsentPeriodicAlignments.Calendar,
+		}},
+	}
+}
+
+func TestPaymentConsentConstraintsValidate(t *testing.T) {
+	assert.Nil(t, testPaymentConsentConstraints().Validate())
+
+	invalid := map[string]func(*PaymentConsentConstraints){
+		"no max amount": func(c *PaymentConsentConstraints) {
+			c.MaxPaymentAmount.Value = 0
+		},
+		"no periodic amounts": func(c *PaymentConsentConstraints) {
+			c.PeriodicAmounts = nil
+		},
+		"unknown interval": func(c *PaymentConsentConstraints) {
+			c.PeriodicAmounts[0].Interval = "FORTNIGHT"
+		},
+		"unknown alignment": func(c *PaymentConsentConstraints) {
+			c.PeriodicAmounts[0].Alignment = ""
+		},
+		"negative periodic amount": func(c *PaymentConsentConstraints) {
+			c.PeriodicAmounts[0].Amount.Value = -1
+		},
+		"mixed currencies": func(c *PaymentConsentConstraints) {
+			c.PeriodicAmounts[0].Amount.Currency = "EUR"
+		},
+		"valid date time ends before it starts": func(c *PaymentConsentConstraints) {
+			c.ValidDateTime.From, c.ValidDateTime.To = c.ValidDateTime.To, c.ValidDateTime.From
+		},
+	}
+	for name, modify := range invalid {
+		constraints := testPaymentConsentConstraints()
+		modify(&constraints)
+		assert.NotNil(t, constraints.Validate(), name)
+	}
+}
+
+func TestPaymentConsent(t *testing.T) {
+	server, client := newFakeServer(t)
+	server.handle("/payment_initiation/consent/create", func(body map[string]interface{}) (int, interface{}) {
+		assert.Equal(t, "recipient-1", body["recipient_id"])
+		assert.Equal(t, []interface{}{"ME_TO_ME"}, body["scopes"])
+		constraints := body["constraints"].(map[string]interface{})
+		assert.Equal(t, "2021-03-01T00:00:00Z", constraints["valid_date_time"].(map[string]interface{})["from"])
+		periodic := constraints["periodic_amounts"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "MONTH", periodic["interval"])
+		assert.Equal(t, "CALENDAR", periodic["alignment"])
+		return http.StatusOK, map[string]interface{}{"consent_id": "consent-1", "status": "UNAUTHORISED"}
+	})
+	server.handle("/payment_initiation/consent/get", func(body map[string]interface{}) (int, interface{}) {
+		assert.Equal(t, "consent-1", body["consent_id"])
+		return http.StatusOK, map[string]interface{}{
+			"consent_id":   "consent-1",
+			"status":       "AUTHORISED",
+			"recipient_id": "recipient-1",
+			"reference":    "ref",
+			"scopes":       []string{"ME_TO_ME"},
+			"constraints":  testPaymentConsentConstraints(),
+		}
+	})
+	server.handle("/payment_initiation/consent/payment/execute", func(body map[string]interface{}) (int, interface{}) {
+		assert.Equal(t, "consent-1", body["consent_id"])
+		assert.Equal(t, "payment-key", body["idempotency_key"])
+		return http.StatusOK, map[string]interface{}{"payment_id": "payment-1", "status": "PAYMENT_STATUS_INITIATED"}
+	})
+	server.handle("/payment_initiation/consent/revoke", func(body map[string]interface{}) (int, interface{}) {
+		assert.Equal(t, "consent-1", body["consent_id"])
+		return http.StatusOK, map[string]interface{}{"request_id": "request-1"}
+	})
+
+	created, err := client.CreatePaymentConsent(
+		"recipient-1",
+		"ref",
+		[]PaymentConsentScope{PaymentConsentScopes.MeToMe},
+		testPaymentConsentConstraints(),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "consent-1", created.ConsentID)
+	assert.Equal(t, PaymentConsentStatuses.Unauthorised, created.Status)
+
+	consent, err := client.GetPaymentConsent(created.ConsentID)
+	assert.Nil(t, err)
+	assert.Equal(t, PaymentConsentStatuses.Authorised, consent.Status)
+	assert.Equal(t, testPaymentConsentConstraints(), consent.Constraints)
+
+	payment, err := client.ExecutePaymentConsent(created.ConsentID, PaymentAmount{Currency: "GBP", Value: 50}, "payment-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "payment-1", payment.PaymentID)
+	assert.Equal(t, PaymentStatuses.Initiated, payment.Status)
+
+	revoked, err := client.RevokePaymentConsent(created.ConsentID)
+	assert.Nil(t, err)
+	assert.Equal(t, "request-1", revoked.RequestID)
+}
+
+func TestPaymentConsentValidation(t *testing.T) {
+	server, client := newFakeServer(t)
+
+	_, err := client.CreatePaymentConsent("recipient-1", "ref", nil, testPaymentConsentConstraints())
+	assert.NotNil(t, err)
+	_, err = client.CreatePaymentConsent("recipient-1", "ref", []PaymentConsentScope{PaymentConsentScopes.External}, PaymentConsentConstraints{})
+	assert.NotNil(t, err)
+	_, err = client.ExecutePaymentConsent("consent-1", PaymentAmount{Currency: "GBP", Value: 50}, "")
+	assert.NotNil(t, err)
+	_, err = client.GetPaymentConsent("")
+	assert.NotNil(t, err)
+	_, err = client.RevokePaymentConsent("")
+	assert.NotNil(t, err)
+
+	assert.Equal(t, 0, server.callCount("/payment_initiation/consent/create"))
+	assert.Equal(t, 0, server.callCount("/payment_initiation/consent/payment/execute"))
+}
@@ -0,0 +1,157 @@
+package plaid
+
+import (
+	"errors"
+	"fmt"
+)
+
+type PaymentStatus string
+
+const (
+	paymentStatusInputNeeded       PaymentStatus = "PAYMENT_STATUS_INPUT_NEEDED"
+	paymentStatusAuthorising       PaymentStatus = "PAYMENT_STATUS_AUTHORISING"
+	paymentStatusProcessing        PaymentStatus = "PAYMENT_STATUS_PROCESSING"
+	paymentStatusInitiated         PaymentStatus = "PAYMENT_STATUS_INITIATED"
+	paymentStatusCompleted         PaymentStatus = "PAYMENT_STATUS_COMPLETED"
+	paymentStatusExecuted          PaymentStatus = "PAYMENT_STATUS_EXECUTED"
+	paymentStatusEstablished       PaymentStatus = "PAYMENT_STATUS_ESTABLISHED"
+	paymentStatusInsufficientFunds PaymentStatus = "PAYMENT_STATUS_INSUFFICIENT_FUNDS"
+	paymentStatusFailed            PaymentStatus = "PAYMENT_STATUS_FAILED"
+	paymentStatusBlocked           PaymentStatus = "PAYMENT_STATUS_BLOCKED"
+	paymentStatusRejected          PaymentStatus = "PAYMENT_STATUS_REJECTED"
+	paymentStatusCancelled         PaymentStatus = "PAYMENT_STATUS_CANCELLED"
+	paymentStatusUnknown           PaymentStatus = "PAYMENT_STATUS_UNKNOWN"
+)
+
+type paymentStatuses struct {
+	// InputNeeded payments are waiting for the user to authorise them in
+	// Link.
+	InputNeeded PaymentStatus
+	Authorising PaymentStatus
+	Processing  PaymentStatus
+	// Initiated payments have been accepted by the user's bank. Under
+	// 2020-09-14 this is the final status of a successful UK payment.
+	Initiated PaymentStatus
+	Completed PaymentStatus
+	Executed  PaymentStatus
+	// Established standing orders have been set up at the user's bank.
+	Established       PaymentStatus
+	InsufficientFunds PaymentStatus
+	Failed            PaymentStatus
+	Blocked           PaymentStatus
+	Rejected          PaymentStatus
+	Cancelled         PaymentStatus
+	// Unknown payments could not be confirmed with the user's bank. Their
+	// status may still change to any other.
+	Unknown PaymentStatus
+}
+
+var PaymentStatuses paymentStatuses = paymentStatuses{
+	InputNeeded:       paymentStatusInputNeeded,
+	Authorising:       paymentStatusAuthorising,
+	Processing:        paymentStatusProcessing,
+	Initiated:         paymentStatusInitiated,
+	Completed:         paymentStatusCompleted,
+	Executed:          paymentStatusExecuted,
+	Established:       paymentStatusEstablished,
+	InsufficientFunds: paymentStatusInsufficientFunds,
+	Failed:            paymentStatusFailed,
+	Blocked:           paymentStatusBlocked,
+	Rejected:          paymentStatusRejected,
+	Cancelled:         paymentStatusCancelled,
+	Unknown:           paymentStatusUnknown,
+}
+
+// failedPaymentStatuses are the statuses a payment in progress can fail with.
+var failedPaymentStatuses = []PaymentStatus{
+	paymentStatusInsufficientFunds,
+	paymentStatusFailed,
+	paymentStatusBlocked,
+	paymentStatusRejected,
+	paymentStatusUnknown,
+}
+
+// paymentStatusTransitions lists the statuses each non-terminal status may
+// change to, besides the failure statuses.
+var paymentStatusTransitions = map[PaymentStatus][]PaymentStatus{
+	paymentStatusInputNeeded: {
+		paymentStatusAuthorising, paymentStatusProcessing, paymentStatusInitiated,
+		paymentStatusEstablished, paymentStatusCancelled,
+	},
+	paymentStatusAuthorising: {
+		paymentStatusInputNeeded, paymentStatusProcessing, paymentStatusInitiated,
+		paymentStatusEstablished, paymentStatusCancelled,
+	},
+	paymentStatusProcessing: {
+		paymentStatusInitiated, paymentStatusCompleted, paymentStatusExecuted,
+		paymentStatusEstablished,
+	},
+}
+
+// Valid reports whether s is a known payment status.
+func (s PaymentStatus) Valid() bool {
+	switch s {
+	case paymentStatusInputNeeded, paymentStatusAuthorising, paymentStatusProcessing,
+		paymentStatusInitiated, paymentStatusCompleted, paymentStatusExecuted,
+		paymentStatusEstablished, paymentStatusInsufficientFunds, paymentStatusFailed,
+		paymentStatusBlocked, paymentStatusRejected, paymentStatusCancelled,
+		paymentStatusUnknown:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether a payment with this status will not change
+// status again.
+func (s PaymentStatus) IsTerminal() bool {
+	switch s {
+	case paymentStatusInitiated, paymentStatusCompleted, paymentStatusExecuted,
+		paymentStatusEstablished, paymentStatusInsufficientFunds, paymentStatusFailed, paymentStatusBlocked,
+		paymentStatusRejected, paymentStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// IsSuccessful reports whether the status is a terminal status in which the
+// payment was made or the standing order set up.
+func (s PaymentStatus) IsSuccessful() bool {
+	switch s {
+	case paymentStatusInitiated, paymentStatusCompleted, paymentStatusExecuted, paymentStatusEstablished:
+		return true
+	}
+	return false
+}
+
+// ErrIllegalPaymentStatusTransition is returned, wrapped, by
+// ValidateTransition for a status change that cannot happen, such as a change
+// out of a terminal status.
+var ErrIllegalPaymentStatusTransition = errors.New("payment status - illegal transition")
+
+// ValidateTransition checks that a payment can change from status s to next.
+// Staying in the same status is always allowed.
+func (s PaymentStatus) ValidateTransition(next PaymentStatus) error {
+	if !s.Valid() || !next.Valid() {
+		return fmt.Errorf("%w from %q to %q: unknown status", ErrIllegalPaymentStatusTransition, s, next)
+	}
+	if s == next {
+		return nil
+	}
+	if s == paymentStatusUnknown {
+		return nil
+	}
+	if s.IsTerminal() {
+		return fmt.Errorf("%w from %s to %s: %s is terminal", ErrIllegalPaymentStatusTransition, s, next, s)
+	}
+	for _, allowed := range paymentStatusTransitions[s] {
+		if next == allowed {
+			return nil
+		}
+	}
+	for _, allowed := range failedPaymentStatuses {
+		if next == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w from %s to %s", ErrIllegalPaymentStatusTransition, s, next)
+}
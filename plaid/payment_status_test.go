@@ -0,0 +1,56 @@
+package plaid
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestPaymentStatusTransitions(t *testing.T) {
+	s := PaymentStatuses
+	tests := []struct {
+		from, to PaymentStatus
+		legal    bool
+	}{
+		{s.InputNeeded, s.InputNeeded, true},
+		{s.InputNeeded, s.Authorising, true},
+		{s.InputNeeded, s.Initiated, true},
+		{s.InputNeeded, s.Cancelled, true},
+		{s.Authorising, s.InputNeeded, true},
+		{s.Processing, s.Initiated, true},
+		{s.Processing, s.InsufficientFunds, true},
+		{s.Unknown, s.Executed, true},
+		{s.Unknown, s.InputNeeded, true},
+		{s.Initiated, s.InputNeeded, false},
+		{s.Initiated, s.Executed, false},
+		{s.Initiated, s.Failed, false},
+		{s.Processing, s.Cancelled, false},
+		{s.Completed, s.Failed, false},
+		{s.Failed, s.Initiated, false},
+		{s.Cancelled, s.InputNeeded, false},
+		{s.InputNeeded, "PAYMENT_STATUS_BOGUS", false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			err := tt.from.ValidateTransition(tt.to)
+			if tt.legal {
+				assert.Nil(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrIllegalPaymentStatusTransition), "%v", err)
+			}
+		})
+	}
+}
+
+func TestPaymentStatusClassification(t *testing.T) {
+	assert.True(t, PaymentStatuses.Executed.IsTerminal())
+	assert.True(t, PaymentStatuses.Executed.IsSuccessful())
+	assert.True(t, PaymentStatuses.Blocked.IsTerminal())
+	assert.False(t, PaymentStatuses.Blocked.IsSuccessful())
+	assert.True(t, PaymentStatuses.Initiated.IsTerminal())
+	assert.True(t, PaymentStatuses.Initiated.IsSuccessful())
+	assert.False(t, PaymentStatuses.Processing.IsTerminal())
+	assert.False(t, PaymentStatuses.Unknown.IsTerminal())
+	assert.False(t, PaymentStatus("PAYMENT_STATUS_BOGUS").Valid())
+}
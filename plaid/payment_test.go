@@ -1,6 +1,7 @@
 package plaid
 
 import (
+	"net/http"
 	"testing"
 	"time"
 
@@ -193,3 +194,158 @@ func commonPaymentTestFlows(t *testing.T, recipientID string, useLinkToken bool)
 		assert.Nil(t, err)
 	}
 }
+
+func TestPaymentScheduleValidate(t *testing.T) {
+	valid := []PaymentSchedule{
+		{Interval: PaymentScheduleIntervals.Weekly, IntervalExecutionDay: 1, StartDate: "2021-03-01"},
+		{Interval: PaymentScheduleIntervals.Weekly, IntervalExecutionDay: 7, StartDate: "2021-03-01", EndDate: "2021-03-01"},
+		{Interval: PaymentScheduleIntervals.Monthly, IntervalExecutionDay: 28, StartDate: "2021-03-01"},
+		{Interval: PaymentScheduleIntervals.Monthly, IntervalExecutionDay: -5, StartDate: "2021-03-01", EndDate: "2022-03-01"},
+	}
+	for _, schedule := range valid {
+		assert.Nil(t, schedule.Validate(), "%+v", schedule)
+	}
+
+	invalid := []PaymentSchedule{
+		{Interval: "DAILY", IntervalExecutionDay: 1, StartDate: "2021-03-01"},
+		{Interval: PaymentScheduleIntervals.Weekly, IntervalExecutionDay: 0, StartDate: "2021-03-01"},
+		{Interval: PaymentScheduleIntervals.Weekly, IntervalExecutionDay: 8, StartDate: "2021-03-01"},
+		{Interval: PaymentScheduleIntervals.Monthly, IntervalExecutionDay: 0, StartDate: "2021-03-01"},
+		{Interval: PaymentScheduleIntervals.Monthly, IntervalExecutionDay: 29, StartDate: "2021-03-01"},
+		{Interval: PaymentScheduleIntervals.Monthly, IntervalExecutionDay: -6, StartDate: "2021-03-01"},
+		{Interval: PaymentScheduleIntervals.Monthly, IntervalExecutionDay: 1, StartDate: "01/03/2021"},
+		{Interval: PaymentScheduleIntervals.Monthly, IntervalExecutionDay: 1, StartDate: "2021-03-01", EndDate: "2021-02-28"},
+		{Interval: PaymentScheduleIntervals.Monthly, IntervalExecutionDay: 1, StartDate: "2021-03-01", EndDate: "soon"},
+	}
+	for _, schedule := range invalid {
+		assert.NotNil(t, schedule.Validate(), "%+v", schedule)
+	}
+}
+
+func TestCreatePaymentWithOptions(t *testing.T) {
+	server, client := newFakeServer(t)
+	var bodies []map[string]interface{}
+	server.handle("/payment_initiation/payment/create", func(body map[string]interface{}) (int, interface{}) {
+		bodies = append(bodies, body)
+		return http.StatusOK, CreatePaymentResponse{PaymentID: "payment-1", Status: PaymentStatuses.InputNeeded}
+	})
+	server.handle("/payment_initiation/payment/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"payment_id": "payment-1",
+			"status":     "PAYMENT_STATUS_EXECUTED",
+			"schedule": map[string]interface{}{
+				"interval":               "MONTHLY",
+				"interval_execution_day": 1,
+				"start_date":             "2021-03-01",
+				"adjusted_start_date":    "2021-03-02",
+			},
+			"refund_details": map[string]interface{}{
+				"name": "John Doe",
+				"bacs": map[string]interface{}{"account": "26207729", "sort_code": "560029"},
+			},
+		}
+	})
+
+	amount := PaymentAmount{Currency: "GBP", Value: 100}
+	_, err := client.CreatePaymentWithOptions("recipient-1", "ref", amount, CreatePaymentOptions{
+		Schedule: &PaymentSchedule{
+			Interval:             PaymentScheduleIntervals.Monthly,
+			IntervalExecutionDay: 1,
+			StartDate:            "2021-03-01",
+			EndDate:              "2022-03-01",
+		},
+		RequestRefundDetails: true,
+	})
+	assert.Nil(t, err)
+	_, err = client.CreatePayment("recipient-1", "ref", amount, nil)
+	assert.Nil(t, err)
+	_, err = client.CreatePayment("recipient-1", "ref", amount, &PaymentSchedule{Interval: "YEARLY", StartDate: "2021-03-01"})
+	assert.NotNil(t, err)
+
+	assert.Len(t, bodies, 2)
+	schedule := bodies[0]["schedule"].(map[string]interface{})
+	assert.Equal(t, "2022-03-01", schedule["end_date"])
+	assert.NotContains(t, schedule, "adjusted_start_date")
+	assert.Equal(t, map[string]interface{}{"request_refund_details": true}, bodies[0]["options"])
+	assert.NotContains(t, bodies[1], "options")
+	assert.NotContains(t, bodies[1], "schedule")
+
+	payment, err := client.GetPayment("payment-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "2021-03-02", payment.Schedule.AdjustedStartDate)
+	assert.Equal(t, "John Doe", payment.RefundDetails.Name)
+	assert.Equal(t, "560029", payment.RefundDetails.BACS.SortCode)
+}
+
+func TestNewPaymentRecipient(t *testing.T) {
+	iban := "GB33BUKB20201555555555"
+	bacs := &PaymentRecipientBacs{Account: "26207729", SortCode: "560029"}
+	address := &PaymentRecipientAddress{
+		Street:     []string{"Street Name 999"},
+		City:       "City",
+		PostalCode: "99999",
+		Country:    "GB",
+	}
+
+	recipient, err := NewPaymentRecipient("John Doe", &iban, nil, address)
+	assert.Nil(t, err)
+	assert.Equal(t, PaymentRecipient{Name: "John Doe", IBAN: &iban, Address: address}, recipient)
+	_, err = NewPaymentRecipient("John Doe", nil, bacs, nil)
+	assert.Nil(t, err)
+	_, err = NewPaymentRecipient("John Doe", &iban, bacs, nil)
+	assert.Nil(t, err)
+
+	badIBAN := "GB00BUKB20201555555555"
+	_, err = NewPaymentRecipient("John Doe", nil, nil, address)
+	assert.NotNil(t, err)
+	_, err = NewPaymentRecipient(" ", &iban, nil, nil)
+	assert.NotNil(t, err)
+	_, err = NewPaymentRecipient("John Doe", &badIBAN, nil, nil)
+	assert.NotNil(t, err)
+	_, err = NewPaymentRecipient("John Doe", nil, &PaymentRecipientBacs{Account: "1", SortCode: "560029"}, nil)
+	assert.NotNil(t, err)
+
+	invalidAddresses := []PaymentRecipientAddress{
+		{Street: nil, City: "City", PostalCode: "99999", Country: "GB"},
+		{Street: []string{"1", "2", "3", "4", "5"}, City: "City", PostalCode: "99999", Country: "GB"},
+		{Street: []string{""}, City: "City", PostalCode: "99999", Country: "GB"},
+		{Street: []string{"Street Name 999"}, PostalCode: "99999", Country: "GB"},
+		{Street: []string{"Street Name 999"}, City: "City", Country: "GB"},
+		{Street: []string{"Street Name 999"}, City: "City", PostalCode: "99999", Country: "UK"},
+		{Street: []string{"Street Name 999"}, City: "City", PostalCode: "99999", Country: "gb"},
+	}
+	for _, invalid := range invalidAddresses {
+		invalid := invalid
+		_, err = NewPaymentRecipient("John Doe", &iban, nil, &invalid)
+		assert.NotNil(t, err, "%+v", invalid)
+	}
+}
+
+func TestCreatePaymentRecipientWithOptions(t *testing.T) {
+	server, client := newFakeServer(t)
+	server.handle("/payment_initiation/recipient/create", func(body map[string]interface{}) (int, interface{}) {
+		assert.Equal(t, "John Doe", body["name"])
+		assert.NotContains(t, body, "iban")
+		assert.NotContains(t, body, "address")
+		return http.StatusOK, CreatePaymentRecipientResponse{RecipientID: "recipient-1"}
+	})
+	server.handle("/payment_initiation/recipient/get", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"recipient_id": "recipient-1",
+			"name":         "John Doe",
+			"iban":         nil,
+			"bacs":         map[string]interface{}{"account": "26207729", "sort_code": "560029"},
+		}
+	})
+
+	recipient, err := NewPaymentRecipient("John Doe", nil, &PaymentRecipientBacs{Account: "26207729", SortCode: "560029"}, nil)
+	assert.Nil(t, err)
+	created, err := client.CreatePaymentRecipientWithOptions(recipient, CreatePaymentRecipientOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "recipient-1", created.RecipientID)
+
+	got, err := client.GetPaymentRecipient(created.RecipientID)
+	assert.Nil(t, err)
+	assert.Equal(t, "recipient-1", got.RecipientID)
+	assert.Equal(t, recipient, got.PaymentRecipient)
+}
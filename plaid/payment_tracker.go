@@ -0,0 +1,242 @@
+package plaid
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// PaymentStatusChange is an entry in the status history of a payment. From is
+// empty for the status a payment was first tracked with.
+type PaymentStatusChange struct {
+	PaymentID string
+	From      PaymentStatus
+	To        PaymentStatus
+	Time      time.Time
+}
+
+// PaymentTrackerOptions configures a PaymentTracker.
+type PaymentTrackerOptions struct {
+	// PollInterval is how often Run polls /payment_initiation/payment/get
+	// for every tracked payment that has not reached a terminal status.
+	// Leave it at zero to only track payments through webhooks.
+	PollInterval time.Duration
+
+	// OnChange is called for every status change recorded.
+	OnChange func(PaymentStatusChange)
+	// OnTerminal is called once a payment reaches a terminal status, with
+	// its full status history.
+	OnTerminal func(paymentID string, status PaymentStatus, history []PaymentStatusChange)
+	// OnError is called by Run when polling a payment fails.
+	OnError func(paymentID string, err error)
+}
+
+type trackedPayment struct {
+	status  PaymentStatus
+	history []PaymentStatusChange
+}
+
+// PaymentTracker follows the status of payments through
+// PAYMENT_STATUS_UPDATE webhooks and polling, and records their status
+// history. Updates that would be illegal transitions, such as webhooks
+// delivered out of order, are rejected.
+type PaymentTracker struct {
+	client  *Client
+	options PaymentTrackerOptions
+	now     func() time.Time
+
+	mu       sync.Mutex
+	payments map[string]*trackedPayment
+}
+
+// NewPaymentTracker creates a PaymentTracker.
+func NewPaymentTracker(client *Client, options PaymentTrackerOptions) *PaymentTracker {
+	return &PaymentTracker{
+		client:   client,
+		options:  options,
+		now:      time.Now,
+		payments: map[string]*trackedPayment{},
+	}
+}
+
+// Track starts tracking a payment, usually with the status returned by
+// CreatePayment, or with an empty status if it is not known. Tracking a
+// payment that is already tracked does nothing.
+func (t *PaymentTracker) Track(paymentID string, status PaymentStatus) {
+	t.mu.Lock()
+	if _, ok := t.payments[paymentID]; ok {
+		t.mu.Unlock()
+		return
+	}
+	p := &trackedPayment{}
+	t.payments[paymentID] = p
+	changes := t.apply(paymentID, p, status, t.now())
+	t.mu.Unlock()
+
+	t.notify(paymentID, changes)
+}
+
+// Untrack stops tracking a payment and forgets its history.
+func (t *PaymentTracker) Untrack(paymentID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.payments, paymentID)
+}
+
+// Status returns the last recorded status of a tracked payment.
+func (t *PaymentTracker) Status(paymentID string) (PaymentStatus, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.payments[paymentID]
+	if !ok {
+		return "", false
+	}
+	return p.status, true
+}
+
+// History returns the status changes recorded for a tracked payment, oldest
+// first.
+func (t *PaymentTracker) History(paymentID string) []PaymentStatusChange {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.payments[paymentID]
+	if !ok {
+		return nil
+	}
+	return append([]PaymentStatusChange(nil), p.history...)
+}
+
+// HandleWebhook records the status change carried by a PAYMENT_INITIATION
+// PAYMENT_STATUS_UPDATE webhook. Other webhooks and untracked payments are
+// ignored. When the webhook's old status shows a change was missed, that
+// change is recorded first.
+func (t *PaymentTracker) HandleWebhook(webhook Webhook) error {
+	if webhook.WebhookType != "PAYMENT_INITIATION" || webhook.WebhookCode != "PAYMENT_STATUS_UPDATE" {
+		return nil
+	}
+
+	at := webhook.Timestamp
+	if at.IsZero() {
+		at = t.now()
+	}
+	return t.update(webhook.PaymentID, webhook.OldPaymentStatus, webhook.NewPaymentStatus, at)
+}
+
+// Poll fetches the status of a tracked payment with GetPayment and records it.
+func (t *PaymentTracker) Poll(paymentID string) error {
+	t.mu.Lock()
+	_, ok := t.payments[paymentID]
+	t.mu.Unlock()
+	if !ok {
+		return errors.New("payment tracker - payment " + paymentID + " is not tracked")
+	}
+
+	resp, err := t.client.GetPayment(paymentID)
+	if err != nil {
+		return err
+	}
+	at := resp.LastStatusUpdate
+	if at.IsZero() {
+		at = t.now()
+	}
+	return t.update(paymentID, "", resp.Status, at)
+}
+
+func (t *PaymentTracker) update(paymentID string, old, status PaymentStatus, at time.Time) error {
+	t.mu.Lock()
+	p, ok := t.payments[paymentID]
+	if !ok {
+		t.mu.Unlock()
+		return nil
+	}
+
+	// A payment tracked without a status takes whichever status is seen
+	// first.
+	if p.status != "" {
+		if err := p.status.ValidateTransition(status); err != nil {
+			t.mu.Unlock()
+			return err
+		}
+	}
+	var changes []PaymentStatusChange
+	if old != "" && old != p.status && old != status &&
+		p.status.ValidateTransition(old) == nil && old.ValidateTransition(status) == nil {
+		changes = t.apply(paymentID, p, old, at)
+	}
+	changes = append(changes, t.apply(paymentID, p, status, at)...)
+	t.mu.Unlock()
+
+	t.notify(paymentID, changes)
+	return nil
+}
+
+// apply records a change to status, if it is one. t.mu must be held.
+func (t *PaymentTracker) apply(paymentID string, p *trackedPayment, status PaymentStatus, at time.Time) []PaymentStatusChange {
+	if status == p.status {
+		return nil
+	}
+	change := PaymentStatusChange{
+		PaymentID: paymentID,
+		From:      p.status,
+		To:        status,
+		Time:      at,
+	}
+	p.status = status
+	p.history = append(p.history, change)
+	return []PaymentStatusChange{change}
+}
+
+func (t *PaymentTracker) notify(paymentID string, changes []PaymentStatusChange) {
+	if len(changes) == 0 {
+		return
+	}
+	if t.options.OnChange != nil {
+		for _, change := range changes {
+			t.options.OnChange(change)
+		}
+	}
+	status := changes[len(changes)-1].To
+	if status.IsTerminal() && t.options.OnTerminal != nil {
+		t.options.OnTerminal(paymentID, status, t.History(paymentID))
+	}
+}
+
+// Run polls every tracked payment that has not reached a terminal status each
+// PollInterval until ctx is done. Failures are reported to OnError.
+func (t *PaymentTracker) Run(ctx context.Context) error {
+	if t.options.PollInterval <= 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ticker := time.NewTicker(t.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		t.pollAll()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *PaymentTracker) pollAll() {
+	t.mu.Lock()
+	paymentIDs := make([]string, 0, len(t.payments))
+	for paymentID, p := range t.payments {
+		if !p.status.IsTerminal() {
+			paymentIDs = append(paymentIDs, paymentID)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, paymentID := range paymentIDs {
+		if err := t.Poll(paymentID); err != nil && t.options.OnError != nil {
+			t.options.OnError(paymentID, err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package plaid
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func statusUpdate(paymentID string, old, new PaymentStatus) Webhook {
+	return Webhook{
+		WebhookType:      "PAYMENT_INITIATION",
+		WebhookCode:      "PAYMENT_STATUS_UPDATE",
+		PaymentID:        paymentID,
+		OldPaymentStatus: old,
+		NewPaymentStatus: new,
+	}
+}
+
+func statuses(history []PaymentStatusChange) []PaymentStatus {
+	var out []PaymentStatus
+	for _, change := range history {
+		out = append(out, change.To)
+	}
+	return out
+}
+
+func TestPaymentTrackerReplaysWebhooks(t *testing.T) {
+	s := PaymentStatuses
+	tests := []struct {
+		name     string
+		updates  [][2]PaymentStatus
+		history  []PaymentStatus
+		terminal PaymentStatus
+		rejected int
+	}{
+		{
+			name:     "happy path",
+			updates:  [][2]PaymentStatus{{s.InputNeeded, s.Processing}, {s.Processing, s.Executed}},
+			history:  []PaymentStatus{s.InputNeeded, s.Processing, s.Executed},
+			terminal: s.Executed,
+		},
+		{
+			name:     "initiated UK payment",
+			updates:  [][2]PaymentStatus{{s.InputNeeded, s.Processing}, {s.Processing, s.Initiated}},
+			history:  []PaymentStatus{s.InputNeeded, s.Processing, s.Initiated},
+			terminal: s.Initiated,
+		},
+		{
+			name:     "missed update",
+			updates:  [][2]PaymentStatus{{s.Processing, s.InsufficientFunds}},
+			history:  []PaymentStatus{s.InputNeeded, s.Processing, s.InsufficientFunds},
+			terminal: s.InsufficientFunds,
+		},
+		{
+			name:     "duplicate and out of order",
+			updates:  [][2]PaymentStatus{{s.InputNeeded, s.Processing}, {s.Processing, s.Executed}, {s.InputNeeded, s.Processing}, {s.Processing, s.Executed}},
+			history:  []PaymentStatus{s.InputNeeded, s.Processing, s.Executed},
+			terminal: s.Executed,
+			rejected: 1,
+		},
+		{
+			name:    "unknown then recovered",
+			updates: [][2]PaymentStatus{{s.InputNeeded, s.Unknown}, {s.Unknown, s.Processing}},
+			history: []PaymentStatus{s.InputNeeded, s.Unknown, s.Processing},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var changes []PaymentStatusChange
+			var terminal []PaymentStatus
+			tracker := NewPaymentTracker(nil, PaymentTrackerOptions{
+				OnChange: func(change PaymentStatusChange) { changes = append(changes, change) },
+				OnTerminal: func(paymentID string, status PaymentStatus, history []PaymentStatusChange) {
+					assert.Equal(t, "payment-1", paymentID)
+					assert.Equal(t, tt.history, statuses(history))
+					terminal = append(terminal, status)
+				},
+			})
+			tracker.Track("payment-1", s.InputNeeded)
+
+			rejected := 0
+			for _, update := range tt.updates {
+				err := tracker.HandleWebhook(statusUpdate("payment-1", update[0], update[1]))
+				if err != nil {
+					assert.True(t, errors.Is(err, ErrIllegalPaymentStatusTransition))
+					rejected++
+				}
+			}
+
+			assert.Equal(t, tt.rejected, rejected)
+			assert.Equal(t, tt.history, statuses(tracker.History("payment-1")))
+			assert.Equal(t, tt.history, statuses(changes))
+			if tt.terminal != "" {
+				assert.Equal(t, []PaymentStatus{tt.terminal}, terminal)
+			} else {
+				assert.Empty(t, terminal)
+			}
+		})
+	}
+}
+
+func TestPaymentTrackerIgnoresOtherWebhooks(t *testing.T) {
+	tracker := NewPaymentTracker(nil, PaymentTrackerOptions{})
+	tracker.Track("payment-1", PaymentStatuses.InputNeeded)
+
+	assert.Nil(t, tracker.HandleWebhook(Webhook{WebhookType: "TRANSACTIONS", WebhookCode: "DEFAULT_UPDATE"}))
+	assert.Nil(t, tracker.HandleWebhook(statusUpdate("payment-2", "", PaymentStatuses.Executed)))
+	_, ok := tracker.Status("payment-2")
+	assert.False(t, ok)
+
+	status, ok := tracker.Status("payment-1")
+	assert.True(t, ok)
+	assert.Equal(t, PaymentStatuses.InputNeeded, status)
+}
+
+func TestParsePaymentStatusWebhook(t *testing.T) {
+	webhook, err := ParseWebhook([]byte(`{
+		"webhook_type": "PAYMENT_INITIATION",
+		"webhook_code": "PAYMENT_STATUS_UPDATE",
+		"payment_id": "payment-id-production-2ba30780-d549-4335-b1fe-c2a938aa39d2",
+		"new_payment_status": "PAYMENT_STATUS_INITIATED",
+		"old_payment_status": "PAYMENT_STATUS_PROCESSING",
+		"timestamp": "2017-09-14T14:42:19.350Z"
+	}`))
+	assert.Nil(t, err)
+	assert.Equal(t, PaymentStatuses.Initiated, webhook.NewPaymentStatus)
+	assert.Equal(t, PaymentStatuses.Processing, webhook.OldPaymentStatus)
+	assert.Equal(t, 2017, webhook.Timestamp.Year())
+}
+
+func TestPaymentTrackerPolls(t *testing.T) {
+	server, client := newFakeServer(t)
+	sequence := []PaymentStatus{
+		PaymentStatuses.InputNeeded,
+		PaymentStatuses.Processing,
+		PaymentStatuses.Processing,
+		PaymentStatuses.Executed,
+	}
+	server.handle("/payment_initiation/payment/get", func(body map[string]interface{}) (int, interface{}) {
+		status := sequence[0]
+		if len(sequence) > 1 {
+			sequence = sequence[1:]
+		}
+		return http.StatusOK, GetPaymentResponse{Payment: Payment{
+			PaymentID: body["payment_id"].(string),
+			Status:    status,
+		}}
+	})
+
+	done := make(chan PaymentStatus, 1)
+	tracker := NewPaymentTracker(client, PaymentTrackerOptions{
+		PollInterval: time.Millisecond,
+		OnTerminal: func(paymentID string, status PaymentStatus, history []PaymentStatusChange) {
+			done <- status
+		},
+		OnError: func(paymentID string, err error) { t.Error(err) },
+	})
+	tracker.Track("payment-1", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = tracker.Run(ctx) }()
+
+	select {
+	case status := <-done:
+		assert.Equal(t, PaymentStatuses.Executed, status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("payment did not reach a terminal status")
+	}
+	cancel()
+
+	assert.Equal(t, []PaymentStatus{
+		PaymentStatuses.InputNeeded,
+		PaymentStatuses.Processing,
+		PaymentStatuses.Executed,
+	}, statuses(tracker.History("payment-1")))
+	assert.Equal(t, 4, server.callCount("/payment_initiation/payment/get"))
+
+	assert.NotNil(t, tracker.Poll("payment-2"))
+}
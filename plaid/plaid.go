@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 )
 
-// APIVersion holds the latest version of the Plaid API
+// APIVersion holds the latest version of the Plaid API, which clients call
+// unless ClientOptions.APIVersion says otherwise.
 const APIVersion = "2020-09-14"
 
 // Client holds information required to interact with the Plaid API.
@@ -22,6 +25,13 @@ type Client struct {
 	secret      string
 	environment Environment
 	httpClient  *http.Client
+	apiVersion  string
+
+	validateAccountNumbers bool
+
+	idempotencyStore IdempotencyStore
+	idempotencyTTL   time.Duration
+	idempotencyLocks *keyedLocks
 }
 
 type ClientOptions struct {
@@ -29,23 +39,58 @@ type ClientOptions struct {
 	Secret      string
 	Environment Environment
 	HTTPClient  *http.Client
+
+	// APIVersion is the version of the Plaid API to call, one of
+	// APIVersions. It defaults to APIVersion, and can be overridden for
+	// individual calls with WithAPIVersion.
+	APIVersion string
+
+	// ValidateAccountNumbers enables local validation of account numbers,
+	// IBANs and sort codes before they are sent to Plaid, so malformed
+	// values fail fast instead of costing a round trip.
+	ValidateAccountNumbers bool
+
+	// IdempotencyStore enables client side deduplication of mutating calls
+	// made with an idempotency key, such as CreatePaymentWithOptions. The
+	// response to the first call is returned for calls replaying its key
+	// within IdempotencyTTL, which defaults to DefaultIdempotencyTTL.
+	IdempotencyStore IdempotencyStore
+	IdempotencyTTL   time.Duration
 }
 
 // NewClient instantiates a Client associated with a client id, secret and environment.
 func NewClient(options ClientOptions) (client *Client, err error) {
 	if !options.Environment.Valid() {
-		fmt.Printf("WARNING: Invalid environment specified: %s, please use one of: plaid.Sandbox, plaid.Development or plaid.Production\n", string(options.Environment))
+		fmt.Fprintf(os.Stderr, "WARNING: Invalid environment specified: %s, please use one of: plaid.Sandbox, plaid.Development or plaid.Production\n", string(options.Environment))
 	}
 
 	if options.HTTPClient == nil {
 		options.HTTPClient = &http.Client{}
 	}
 
+	if options.APIVersion == "" {
+		options.APIVersion = APIVersion
+	}
+	if !isSupportedAPIVersion(options.APIVersion) {
+		return nil, fmt.Errorf("unsupported API version %q, must be one of %s", options.APIVersion, strings.Join(APIVersions, ", "))
+	}
+
+	if options.IdempotencyTTL <= 0 {
+		options.IdempotencyTTL = DefaultIdempotencyTTL
+	}
+
 	return &Client{
 		clientID:    options.ClientID,
 		secret:      options.Secret,
 		environment: options.Environment,
 		httpClient:  options.HTTPClient,
+		apiVersion:  options.APIVersion,
+
+		validateAccountNumbers: options.ValidateAccountNumbers,
+
+		idempotencyStore: options.IdempotencyStore,
+		idempotencyTTL:   options.IdempotencyTTL,
+		idempotencyLocks: &keyedLocks{},
 	}, nil
 }
 
@@ -58,12 +103,29 @@ func (c *Client) Call(endpoint string, body []byte, v interface{}) error {
 	return c.do(req, v)
 }
 
+// CallRaw is like Call for endpoints that respond with something other than
+// JSON, such as a PDF. A successful response body is streamed to w and the
+// number of bytes written is returned. Error responses are decoded as usual,
+// in which case nothing is written to w.
+func (c *Client) CallRaw(endpoint string, body []byte, w io.Writer) (int64, error) {
+	req, err := c.newRequest(endpoint, bytes.NewReader(body), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.doRaw(req, w)
+}
+
 // newRequest is used by Call to generate a http.Request with appropriate headers.
 func (c *Client) newRequest(endpoint string, body io.Reader, v interface{}) (*http.Request, error) {
 	if !strings.HasPrefix(endpoint, "/") {
 		endpoint = "/" + endpoint
 	}
 
+	if !EndpointSupportsAPIVersion(endpoint, c.apiVersion) {
+		return nil, UnsupportedAPIVersionError{Endpoint: endpoint, APIVersion: c.apiVersion}
+	}
+
 	req, err := http.NewRequest("POST", string(c.environment)+endpoint, body)
 	if err != nil {
 		return nil, err
@@ -73,7 +135,7 @@ func (c *Client) newRequest(endpoint string, body io.Reader, v interface{}) (*ht
 	req.Header.Add("User-Agent", "Perch Credit Plaid Go")
 
 	// Add header for Plaid API version
-	req.Header.Add("Plaid-Version", APIVersion)
+	req.Header.Add("Plaid-Version", c.apiVersion)
 
 	return req, nil
 }
@@ -92,11 +154,33 @@ func (c *Client) do(req *http.Request, v interface{}) error {
 
 	// Successful response
 	if res.StatusCode == 200 {
-		return json.NewDecoder(res.Body).Decode(v)
+		return decodeResponse(res.Body, req.Header.Get("Plaid-Version"), req.URL.Path, v)
+	}
+	return decodeError(res)
+}
+
+// doRaw is used by CallRaw to execute an http.Request and copy a successful
+// response body to w.
+func (c *Client) doRaw(req *http.Request, w io.Writer) (int64, error) {
+	res, err := c.httpClient.Do(req)
+
+	if err != nil {
+		return 0, err
 	}
-	// Attempt to unmarshal into Plaid error format
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode == 200 {
+		return io.Copy(w, res.Body)
+	}
+	return 0, decodeError(res)
+}
+
+// decodeError parses an unsuccessful response in the plaid error format.
+func decodeError(res *http.Response) error {
 	var plaidErr Error
-	if err = json.NewDecoder(res.Body).Decode(&plaidErr); err != nil {
+	if err := json.NewDecoder(res.Body).Decode(&plaidErr); err != nil {
 		return err
 	}
 	plaidErr.StatusCode = res.StatusCode
@@ -38,7 +38,13 @@ type CreateStripeTokenResponse struct {
 	StripeBankAccountToken string `json:"stripe_bank_account_token"`
 }
 
-func (c *Client) requestProcessorToken(apiEndpoint, accessToken, accountID string, processor string) (resp ProcessorTokenResponse, err error) {
+type ProcessorTokenOptions struct {
+	// IdempotencyKey deduplicates replayed calls through the client's
+	// IdempotencyStore.
+	IdempotencyKey string
+}
+
+func (c *Client) requestProcessorToken(apiEndpoint, accessToken, accountID string, processor string, options ProcessorTokenOptions) (resp ProcessorTokenResponse, err error) {
 	if accessToken == "" || accountID == "" {
 		return resp, errors.New(apiEndpoint + " - access token and account ID must be specified")
 	}
@@ -59,12 +65,17 @@ func (c *Client) requestProcessorToken(apiEndpoint, accessToken, accountID strin
 		return resp, err
 	}
 
-	err = c.Call(apiEndpoint, jsonBody, &resp)
+	err = c.callIdempotent(apiEndpoint, options.IdempotencyKey, jsonBody, &resp)
 	return resp, err
 }
 
 // CreateProcessorToken is used to create a new generic processor token.
 func (c *Client) CreateProcessorToken(accessToken, accountID string, processor string) (resp ProcessorTokenResponse, err error) {
+	return c.CreateProcessorTokenWithOptions(accessToken, accountID, processor, ProcessorTokenOptions{})
+}
+
+// CreateProcessorTokenWithOptions is used to create a new generic processor token.
+func (c *Client) CreateProcessorTokenWithOptions(accessToken, accountID string, processor string, options ProcessorTokenOptions) (resp ProcessorTokenResponse, err error) {
 	if processor == "" {
 		return resp, errors.New("you must specify a processor")
 	}
@@ -75,30 +86,50 @@ func (c *Client) CreateProcessorToken(accessToken, accountID string, processor s
 		return resp, errors.New("apex processor tokens are not compatible with this function, use CreateStripeToken instead")
 	}
 
-	response, err := c.requestProcessorToken("processor/token/create", accessToken, accountID, processor)
+	response, err := c.requestProcessorToken("/processor/token/create", accessToken, accountID, processor, options)
 	return ProcessorTokenResponse(response), err
 }
 
 // CreateApexToken is used to create a new Apex processor token.
 func (c *Client) CreateApexToken(accessToken, accountID string) (resp CreateApexTokenResponse, err error) {
-	response, err := c.requestProcessorToken("/processor/apex/processor_token/create", accessToken, accountID, "")
+	return c.CreateApexTokenWithOptions(accessToken, accountID, ProcessorTokenOptions{})
+}
+
+// CreateApexTokenWithOptions is used to create a new Apex processor token.
+func (c *Client) CreateApexTokenWithOptions(accessToken, accountID string, options ProcessorTokenOptions) (resp CreateApexTokenResponse, err error) {
+	response, err := c.requestProcessorToken("/processor/apex/processor_token/create", accessToken, accountID, "", options)
 	return CreateApexTokenResponse(response), err
 }
 
 // CreateDwollaToken is used to create a new Dwolla processor token.
 func (c *Client) CreateDwollaToken(accessToken, accountID string) (resp CreateDwollaTokenResponse, err error) {
-	response, err := c.requestProcessorToken("/processor/dwolla/processor_token/create", accessToken, accountID, "")
+	return c.CreateDwollaTokenWithOptions(accessToken, accountID, ProcessorTokenOptions{})
+}
+
+// CreateDwollaTokenWithOptions is used to create a new Dwolla processor token.
+func (c *Client) CreateDwollaTokenWithOptions(accessToken, accountID string, options ProcessorTokenOptions) (resp CreateDwollaTokenResponse, err error) {
+	response, err := c.requestProcessorToken("/processor/dwolla/processor_token/create", accessToken, accountID, "", options)
 	return CreateDwollaTokenResponse(response), err
 }
 
 // CreateOcrolusToken is used to create a new Ocrolus processor token.
 func (c *Client) CreateOcrolusToken(accessToken, accountID string) (resp CreateOcrolusTokenResponse, err error) {
-	response, err := c.requestProcessorToken("/processor/ocrolus/processor_token/create", accessToken, accountID, "")
+	return c.CreateOcrolusTokenWithOptions(accessToken, accountID, ProcessorTokenOptions{})
+}
+
+// CreateOcrolusTokenWithOptions is used to create a new Ocrolus processor token.
+func (c *Client) CreateOcrolusTokenWithOptions(accessToken, accountID string, options ProcessorTokenOptions) (resp CreateOcrolusTokenResponse, err error) {
+	response, err := c.requestProcessorToken("/processor/ocrolus/processor_token/create", accessToken, accountID, "", options)
 	return CreateOcrolusTokenResponse(response), err
 }
 
 // CreateStripeToken is used to create a new Stripe bank account token.
 func (c *Client) CreateStripeToken(accessToken, accountID string) (resp CreateStripeTokenResponse, err error) {
+	return c.CreateStripeTokenWithOptions(accessToken, accountID, ProcessorTokenOptions{})
+}
+
+// CreateStripeTokenWithOptions is used to create a new Stripe bank account token.
+func (c *Client) CreateStripeTokenWithOptions(accessToken, accountID string, options ProcessorTokenOptions) (resp CreateStripeTokenResponse, err error) {
 	if accessToken == "" || accountID == "" {
 		return resp, errors.New("/processor/stripe/bank_account_token/create - access token and account ID must be specified")
 	}
@@ -113,6 +144,6 @@ func (c *Client) CreateStripeToken(accessToken, accountID string) (resp CreateSt
 		return resp, err
 	}
 
-	err = c.Call("/processor/stripe/bank_account_token/create", jsonBody, &resp)
+	err = c.callIdempotent("/processor/stripe/bank_account_token/create", options.IdempotencyKey, jsonBody, &resp)
 	return resp, err
 }
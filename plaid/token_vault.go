@@ -0,0 +1,343 @@
+package plaid
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// TokenVault stores access tokens by item ID, so that the rest of an
+// application can refer to Items without handling their tokens.
+// Implementations must be safe for concurrent use.
+type TokenVault interface {
+	// AccessToken returns the access token of an Item, or
+	// ErrAccessTokenNotFound.
+	AccessToken(itemID string) (string, error)
+	// PutAccessToken stores the access token of an Item, replacing any
+	// token already stored for it.
+	PutAccessToken(itemID, accessToken string) error
+	// DeleteAccessToken forgets the access token of an Item.
+	DeleteAccessToken(itemID string) error
+	// UpdateAccessToken replaces the access token of an Item with the one
+	// returned by update, which is called with the current token. Updates
+	// to the same Item are serialized, and the stored token is left as it
+	// was if update fails.
+	UpdateAccessToken(itemID string, update func(accessToken string) (string, error)) error
+	// ItemIDs returns the IDs of the Items the vault has access tokens for.
+	ItemIDs() ([]string, error)
+}
+
+// ErrAccessTokenNotFound is returned by a TokenVault for an Item it has no
+// access token for.
+var ErrAccessTokenNotFound = errors.New("token vault - no access token stored for item")
+
+// TokenCipher encrypts the access tokens stored by an EncryptedTokenVault.
+// The item ID is authenticated along with each token, so that a token cannot
+// be moved to another Item's record.
+type TokenCipher interface {
+	Seal(itemID string, accessToken string) ([]byte, error)
+	Open(itemID string, sealed []byte) (string, error)
+}
+
+type aesGCMCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCMCipher returns a TokenCipher that encrypts tokens with AES-GCM
+// under a 16, 24 or 32 byte key.
+func NewAESGCMCipher(key []byte) (TokenCipher, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return aesGCMCipher{aead: aead}, nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal implements TokenCipher. The result is the nonce followed by the
+// ciphertext.
+func (c aesGCMCipher) Seal(itemID string, accessToken string) ([]byte, error) {
+	return seal(c.aead, itemID, accessToken)
+}
+
+// Open implements TokenCipher.
+func (c aesGCMCipher) Open(itemID string, sealed []byte) (string, error) {
+	return open(c.aead, itemID, sealed)
+}
+
+func seal(aead cipher.AEAD, itemID string, accessToken string) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(accessToken)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, []byte(accessToken), []byte(itemID)), nil
+}
+
+func open(aead cipher.AEAD, itemID string, sealed []byte) (string, error) {
+	if len(sealed) < aead.NonceSize() {
+		return "", errTokenCorrupt
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(itemID))
+	if err != nil {
+		return "", errTokenCorrupt
+	}
+	return string(plaintext), nil
+}
+
+var errTokenCorrupt = errors.New("token vault - access token could not be decrypted")
+
+// DataKeyEncrypter encrypts the data keys of an envelope cipher with a key
+// encryption key held elsewhere, usually in a key management service.
+type DataKeyEncrypter interface {
+	EncryptDataKey(dataKey []byte) ([]byte, error)
+	DecryptDataKey(encryptedDataKey []byte) ([]byte, error)
+}
+
+type envelopeCipher struct {
+	kek DataKeyEncrypter
+}
+
+// NewEnvelopeCipher returns a TokenCipher that encrypts every token with
+// AES-GCM under a new 256 bit data key, and stores the data key alongside it
+// encrypted by kek. The key encryption key never has to leave the key
+// management service, and rotating it only requires re-encrypting data keys.
+func NewEnvelopeCipher(kek DataKeyEncrypter) TokenCipher {
+	return envelopeCipher{kek: kek}
+}
+
+// Seal implements TokenCipher. The result is the length of the encrypted
+// data key as a big endian uint16, the encrypted data key, the nonce and the
+// ciphertext.
+func (c envelopeCipher) Seal(itemID string, accessToken string) ([]byte, error) {
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+	aead, err := newAESGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, err := c.kek.EncryptDataKey(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(encryptedKey) > 0xffff {
+		return nil, errors.New("token vault - encrypted data key is too long")
+	}
+
+	sealed, err := seal(aead, itemID, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 2, 2+len(encryptedKey)+len(sealed))
+	binary.BigEndian.PutUint16(out, uint16(len(encryptedKey)))
+	out = append(out, encryptedKey...)
+	return append(out, sealed...), nil
+}
+
+// Open implements TokenCipher.
+func (c envelopeCipher) Open(itemID string, sealed []byte) (string, error) {
+	if len(sealed) < 2 {
+		return "", errTokenCorrupt
+	}
+	n := int(binary.BigEndian.Uint16(sealed))
+	if len(sealed) < 2+n {
+		return "", errTokenCorrupt
+	}
+	dataKey, err := c.kek.DecryptDataKey(sealed[2 : 2+n])
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAESGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	return open(aead, itemID, sealed[2+n:])
+}
+
+// EncryptedTokenVault is a TokenVault that keeps access tokens encrypted by a
+// TokenCipher, either in memory or in a file.
+type EncryptedTokenVault struct {
+	cipher TokenCipher
+	path   string
+
+	mu     sync.Mutex
+	tokens map[string][]byte
+	// items serializes UpdateAccessToken calls for the same Item without
+	// holding mu while update calls Plaid.
+	items keyedLocks
+}
+
+// NewMemoryTokenVault returns an empty EncryptedTokenVault that keeps tokens
+// in memory.
+func NewMemoryTokenVault(cipher TokenCipher) *EncryptedTokenVault {
+	return &EncryptedTokenVault{cipher: cipher, tokens: map[string][]byte{}}
+}
+
+// tokenVaultFile is the format of the file of an EncryptedTokenVault.
+type tokenVaultFile struct {
+	Version int               `json:"version"`
+	Tokens  map[string][]byte `json:"tokens"`
+}
+
+// OpenFileTokenVault returns an EncryptedTokenVault that keeps tokens in the
+// file at path, loading the tokens already in it. The file is created when
+// the first token is stored, readable by its owner only, and is replaced
+// atomically on every change.
+func OpenFileTokenVault(path string, cipher TokenCipher) (*EncryptedTokenVault, error) {
+	v := &EncryptedTokenVault{cipher: cipher, path: path, tokens: map[string][]byte{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return v, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file tokenVaultFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.Version != 1 {
+		return nil, errors.New("token vault - unsupported file version")
+	}
+	if file.Tokens != nil {
+		v.tokens = file.Tokens
+	}
+	return v, nil
+}
+
+// AccessToken implements TokenVault.
+func (v *EncryptedTokenVault) AccessToken(itemID string) (string, error) {
+	v.mu.Lock()
+	sealed, ok := v.tokens[itemID]
+	v.mu.Unlock()
+	if !ok {
+		return "", ErrAccessTokenNotFound
+	}
+	return v.cipher.Open(itemID, sealed)
+}
+
+// PutAccessToken implements TokenVault.
+func (v *EncryptedTokenVault) PutAccessToken(itemID, accessToken string) error {
+	unlock := v.items.lock(itemID)
+	defer unlock()
+	return v.put(itemID, accessToken)
+}
+
+// put stores an access token. It must be called with the Item locked.
+func (v *EncryptedTokenVault) put(itemID, accessToken string) error {
+	sealed, err := v.cipher.Seal(itemID, accessToken)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	previous, existed := v.tokens[itemID]
+	v.tokens[itemID] = sealed
+	if err := v.save(); err != nil {
+		if existed {
+			v.tokens[itemID] = previous
+		} else {
+			delete(v.tokens, itemID)
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteAccessToken implements TokenVault.
+func (v *EncryptedTokenVault) DeleteAccessToken(itemID string) error {
+	unlock := v.items.lock(itemID)
+	defer unlock()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	previous, existed := v.tokens[itemID]
+	if !existed {
+		return nil
+	}
+	delete(v.tokens, itemID)
+	if err := v.save(); err != nil {
+		v.tokens[itemID] = previous
+		return err
+	}
+	return nil
+}
+
+// ItemIDs implements TokenVault. Item IDs are sorted.
+func (v *EncryptedTokenVault) ItemIDs() ([]string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	itemIDs := make([]string, 0, len(v.tokens))
+	for itemID := range v.tokens {
+		itemIDs = append(itemIDs, itemID)
+	}
+	sort.Strings(itemIDs)
+	return itemIDs, nil
+}
+
+// UpdateAccessToken implements TokenVault.
+func (v *EncryptedTokenVault) UpdateAccessToken(itemID string, update func(accessToken string) (string, error)) error {
+	unlock := v.items.lock(itemID)
+	defer unlock()
+
+	current, err := v.AccessToken(itemID)
+	if err != nil {
+		return err
+	}
+	next, err := update(current)
+	if err != nil {
+		return err
+	}
+	return v.put(itemID, next)
+}
+
+// save writes the tokens to the vault's file, if it has one. It must be
+// called with mu held.
+func (v *EncryptedTokenVault) save() error {
+	if v.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(tokenVaultFile{Version: 1, Tokens: v.tokens})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(v.path), filepath.Base(v.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), v.path)
+}
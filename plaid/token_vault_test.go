@@ -0,0 +1,228 @@
+package plaid
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+var testVaultKey = bytes.Repeat([]byte{7}, 32)
+
+func newTestCipher(t *testing.T) TokenCipher {
+	cipher, err := NewAESGCMCipher(testVaultKey)
+	assert.NoError(t, err)
+	return cipher
+}
+
+// xorKEK stands in for a key management service in tests.
+type xorKEK struct {
+	calls int
+}
+
+func (k *xorKEK) EncryptDataKey(dataKey []byte) ([]byte, error) {
+	k.calls++
+	return xorBytes(dataKey), nil
+}
+
+func (k *xorKEK) DecryptDataKey(encryptedDataKey []byte) ([]byte, error) {
+	k.calls++
+	return xorBytes(encryptedDataKey), nil
+}
+
+func xorBytes(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[i] = b[i] ^ 0x5a
+	}
+	return out
+}
+
+func TestTokenCiphers(t *testing.T) {
+	kek := &xorKEK{}
+	ciphers := map[string]TokenCipher{
+		"aes-gcm":  newTestCipher(t),
+		"envelope": NewEnvelopeCipher(kek),
+	}
+
+	for name, cipher := range ciphers {
+		t.Run(name, func(t *testing.T) {
+			sealed, err := cipher.Seal("item-1", "access-sandbox-1")
+			assert.NoError(t, err)
+			assert.NotContains(t, string(sealed), "access-sandbox-1")
+
+			token, err := cipher.Open("item-1", sealed)
+			assert.NoError(t, err)
+			assert.Equal(t, "access-sandbox-1", token)
+
+			// Tokens are bound to their Item.
+			_, err = cipher.Open("item-2", sealed)
+			assert.Error(t, err)
+
+			sealed[len(sealed)-1] ^= 1
+			_, err = cipher.Open("item-1", sealed)
+			assert.Error(t, err)
+
+			_, err = cipher.Open("item-1", nil)
+			assert.Error(t, err)
+		})
+	}
+	assert.NotZero(t, kek.calls)
+
+	_, err := NewAESGCMCipher([]byte("short"))
+	assert.Error(t, err)
+}
+
+func TestMemoryTokenVault(t *testing.T) {
+	vault := NewMemoryTokenVault(newTestCipher(t))
+
+	_, err := vault.AccessToken("item-1")
+	assert.True(t, errors.Is(err, ErrAccessTokenNotFound))
+
+	assert.NoError(t, vault.PutAccessToken("item-1", "access-1"))
+	token, err := vault.AccessToken("item-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", token)
+
+	assert.NoError(t, vault.UpdateAccessToken("item-1", func(current string) (string, error) {
+		assert.Equal(t, "access-1", current)
+		return "access-2", nil
+	}))
+	token, _ = vault.AccessToken("item-1")
+	assert.Equal(t, "access-2", token)
+
+	err = vault.UpdateAccessToken("item-1", func(current string) (string, error) {
+		return "", errors.New("plaid is down")
+	})
+	assert.EqualError(t, err, "plaid is down")
+	token, _ = vault.AccessToken("item-1")
+	assert.Equal(t, "access-2", token)
+
+	err = vault.UpdateAccessToken("item-2", func(current string) (string, error) {
+		t.Fatal("update called for a missing item")
+		return "", nil
+	})
+	assert.True(t, errors.Is(err, ErrAccessTokenNotFound))
+
+	assert.NoError(t, vault.DeleteAccessToken("item-1"))
+	assert.NoError(t, vault.DeleteAccessToken("item-1"))
+	_, err = vault.AccessToken("item-1")
+	assert.True(t, errors.Is(err, ErrAccessTokenNotFound))
+}
+
+func TestTokenVaultUpdatesAreSerialized(t *testing.T) {
+	vault := NewMemoryTokenVault(newTestCipher(t))
+	assert.NoError(t, vault.PutAccessToken("item-1", "0"))
+
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = vault.UpdateAccessToken("item-1", func(current string) (string, error) {
+				return current + "0", nil
+			})
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	token, err := vault.AccessToken("item-1")
+	assert.NoError(t, err)
+	assert.Len(t, token, 21)
+}
+
+// TestTokenVaultPutWaitsForUpdate checks that a token put while an update of
+// the same Item is in progress is not overwritten by the update.
+func TestTokenVaultPutWaitsForUpdate(t *testing.T) {
+	vault := NewMemoryTokenVault(newTestCipher(t))
+	assert.NoError(t, vault.PutAccessToken("item-1", "old"))
+
+	updating, release := make(chan struct{}), make(chan struct{})
+	var updateErr, putErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		updateErr = vault.UpdateAccessToken("item-1", func(string) (string, error) {
+			close(updating)
+			<-release
+			return "rotated", nil
+		})
+	}()
+	<-updating
+	go func() {
+		defer wg.Done()
+		putErr = vault.PutAccessToken("item-1", "exchanged")
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.NoError(t, updateErr)
+	assert.NoError(t, putErr)
+
+	token, err := vault.AccessToken("item-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "exchanged", token)
+}
+
+func TestFileTokenVault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+
+	vault, err := OpenFileTokenVault(path, newTestCipher(t))
+	assert.NoError(t, err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, vault.PutAccessToken("item-1", "access-1"))
+	assert.NoError(t, vault.PutAccessToken("item-2", "access-2"))
+	itemIDs, err := vault.ItemIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"item-1", "item-2"}, itemIDs)
+	assert.NoError(t, vault.DeleteAccessToken("item-2"))
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "access-1")
+
+	reopened, err := OpenFileTokenVault(path, newTestCipher(t))
+	assert.NoError(t, err)
+	token, err := reopened.AccessToken("item-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", token)
+	_, err = reopened.AccessToken("item-2")
+	assert.True(t, errors.Is(err, ErrAccessTokenNotFound))
+
+	// A different key cannot read the tokens.
+	other, err := NewAESGCMCipher(bytes.Repeat([]byte{8}, 32))
+	assert.NoError(t, err)
+	reopened, err = OpenFileTokenVault(path, other)
+	assert.NoError(t, err)
+	_, err = reopened.AccessToken("item-1")
+	assert.Error(t, err)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"version":2}`), 0600))
+	_, err = OpenFileTokenVault(path, newTestCipher(t))
+	assert.Error(t, err)
+}
+
+func TestFileTokenVaultKeepsTokensWhenSaveFails(t *testing.T) {
+	dir := t.TempDir()
+	vault, err := OpenFileTokenVault(filepath.Join(dir, "missing", "tokens.json"), newTestCipher(t))
+	assert.NoError(t, err)
+
+	assert.Error(t, vault.PutAccessToken("item-1", "access-1"))
+	_, err = vault.AccessToken("item-1")
+	assert.True(t, errors.Is(err, ErrAccessTokenNotFound))
+}
@@ -40,8 +40,8 @@ var PaymentChannels paymentChannels = paymentChannels{
 type Transaction struct {
 	AccountID              string   `json:"account_id"`
 	Amount                 float64  `json:"amount"`
-	ISOCurrencyCode        string   `json:"iso_currency_code"`
-	UnofficialCurrencyCode string   `json:"unofficial_currency_code"`
+	ISOCurrencyCode        Currency `json:"iso_currency_code"`
+	UnofficialCurrencyCode Currency `json:"unofficial_currency_code"`
 	Category               []string `json:"category"`
 	CategoryID             string   `json:"category_id"`
 	Date                   string   `json:"date"`
@@ -86,9 +86,9 @@ type PaymentMeta struct {
 }
 
 type getTransactionsRequestOptions struct {
-	AccountIDs []string `json:"account_ids"`
-	Count      int      `json:"count"`
-	Offset     int      `json:"offset"`
+	AccountIDs []string `json:"account_ids,omitempty"`
+	Count      int      `json:"count,omitempty"`
+	Offset     int      `json:"offset,omitempty"`
 }
 
 type getTransactionsRequest struct {
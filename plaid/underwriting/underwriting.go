@@ -0,0 +1,427 @@
+// Package underwriting derives the balance and cash flow metrics used to
+// underwrite loans from Asset Reports.
+//
+// Historical balances are reported once per day, but days are sometimes
+// missing. Metrics are computed on a daily series in which a missing day takes
+// the balance of the previous day, and the missing days are reported as gaps.
+package underwriting
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+)
+
+const dateLayout = "2006-01-02"
+
+// Options configures how a report is summarized.
+type Options struct {
+	// Currency is the currency report totals are expressed in. It defaults to
+	// the currency of the first depository account.
+	Currency plaid.Currency
+	// Converter converts the balances of accounts in other currencies. It is
+	// required when a report's accounts are in different currencies.
+	Converter plaid.Converter
+}
+
+// Gap is a run of days without a historical balance.
+type Gap struct {
+	// Start and End are the first and last days without a balance.
+	Start time.Time
+	End   time.Time
+}
+
+// Days returns the number of days in the gap.
+func (g Gap) Days() int {
+	return daysBetween(g.Start, g.End) + 1
+}
+
+// Metrics are the balance metrics shared by account and report summaries.
+// Averages are taken over the last 30, 60 or 90 days of history, or over the
+// whole history when it is shorter.
+type Metrics struct {
+	// DaysOfHistory is the number of days from the first historical balance to
+	// the report date, inclusive.
+	DaysOfHistory int
+	HistoryStart  time.Time
+	HistoryEnd    time.Time
+
+	AverageDailyBalance30 float64
+	AverageDailyBalance60 float64
+	AverageDailyBalance90 float64
+
+	MinimumBalance     float64
+	MinimumBalanceDate time.Time
+
+	// NegativeBalanceDays is the number of days ending with a negative
+	// balance.
+	NegativeBalanceDays int
+	// OverdraftEvents is the number of times the balance went from zero or
+	// more to negative, including a history starting negative.
+	OverdraftEvents int
+	// BalanceVolatility is the standard deviation of the daily balances.
+	BalanceVolatility float64
+}
+
+// AccountSummary holds the metrics of one account, in its own currency.
+type AccountSummary struct {
+	AccountID string
+	// Type is the Plaid account type, such as depository or credit.
+	Type           string
+	Currency       plaid.Currency
+	CurrentBalance float64
+	Metrics
+
+	// Gaps lists the days between two historical balances that have none of
+	// their own.
+	Gaps []Gap
+
+	// NSFCount is the number of insufficient funds fees and returned items.
+	NSFCount int
+	// IncomeDeposits and IncomeDepositTotal count the payroll and direct
+	// deposits into the account. Deposits are reported as positive amounts.
+	IncomeDeposits     int
+	IncomeDepositTotal float64
+}
+
+// ReportSummary holds the metrics of every account in a report and their
+// totals in a single currency. The balance metrics of the report are computed
+// on the combined daily balance of its accounts, while day and event counts
+// are summed over accounts. Only depository accounts count towards the totals:
+// the balance of a credit or loan account is owed rather than held.
+type ReportSummary struct {
+	AssetReportID  string
+	DateGenerated  time.Time
+	Currency       plaid.Currency
+	CurrentBalance float64
+	Metrics
+
+	NSFCount           int
+	IncomeDeposits     int
+	IncomeDepositTotal float64
+
+	Accounts []AccountSummary
+}
+
+// series holds one balance per day, starting at start.
+type series struct {
+	start    time.Time
+	balances []float64
+}
+
+func (s series) end() time.Time {
+	return s.start.AddDate(0, 0, len(s.balances)-1)
+}
+
+// average returns the average of the last days balances, or of all of them
+// if there are fewer.
+func (s series) average(days int) float64 {
+	if len(s.balances) == 0 {
+		return 0
+	}
+	if days > len(s.balances) {
+		days = len(s.balances)
+	}
+	total := 0.0
+	for _, b := range s.balances[len(s.balances)-days:] {
+		total += b
+	}
+	return total / float64(days)
+}
+
+func (s series) metrics() Metrics {
+	m := Metrics{DaysOfHistory: len(s.balances)}
+	if len(s.balances) == 0 {
+		return m
+	}
+	m.HistoryStart = s.start
+	m.HistoryEnd = s.end()
+	m.AverageDailyBalance30 = s.average(30)
+	m.AverageDailyBalance60 = s.average(60)
+	m.AverageDailyBalance90 = s.average(90)
+
+	mean := s.average(len(s.balances))
+	variance := 0.0
+	m.MinimumBalance = s.balances[0]
+	m.MinimumBalanceDate = s.start
+	for i, b := range s.balances {
+		if b < m.MinimumBalance {
+			m.MinimumBalance = b
+			m.MinimumBalanceDate = s.start.AddDate(0, 0, i)
+		}
+		if b < 0 {
+			m.NegativeBalanceDays++
+			if i == 0 || s.balances[i-1] >= 0 {
+				m.OverdraftEvents++
+			}
+		}
+		variance += (b - mean) * (b - mean)
+	}
+	m.BalanceVolatility = math.Sqrt(variance / float64(len(s.balances)))
+	return m
+}
+
+func daysBetween(a, b time.Time) int {
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
+
+func parseDate(s string) (time.Time, error) {
+	// Dates may be given as timestamps, e.g. the date a report was generated.
+	if len(s) > len(dateLayout) {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		s = t.Format(dateLayout)
+	}
+	return time.Parse(dateLayout, s)
+}
+
+// dailySeries builds the daily series of an account's historical balances up
+// to asOf, carrying balances forward over missing days.
+func dailySeries(balances []plaid.HistoricalBalance, asOf time.Time) (series, []Gap, error) {
+	byDate := map[time.Time]float64{}
+	for _, b := range balances {
+		date, err := parseDate(b.Date)
+		if err != nil {
+			return series{}, nil, fmt.Errorf("invalid historical balance date %q", b.Date)
+		}
+		byDate[date] = b.Current
+	}
+	if len(byDate) == 0 {
+		return series{}, nil, nil
+	}
+
+	dates := make([]time.Time, 0, len(byDate))
+	for date := range byDate {
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	end := dates[len(dates)-1]
+	if asOf.After(end) {
+		end = asOf
+	}
+
+	s := series{start: dates[0]}
+	var gaps []Gap
+	for i, date := range dates {
+		if i > 0 {
+			if missing := daysBetween(dates[i-1], date) - 1; missing > 0 {
+				gaps = append(gaps, Gap{Start: dates[i-1].AddDate(0, 0, 1), End: date.AddDate(0, 0, -1)})
+				last := s.balances[len(s.balances)-1]
+				for j := 0; j < missing; j++ {
+					s.balances = append(s.balances, last)
+				}
+			}
+		}
+		s.balances = append(s.balances, byDate[date])
+	}
+	for n := daysBetween(dates[len(dates)-1], end); n > 0; n-- {
+		s.balances = append(s.balances, s.balances[len(s.balances)-1])
+	}
+	return s, gaps, nil
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToUpper(s), func(r rune) bool {
+		return (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+	})
+}
+
+func containsPhrase(s string, phrases ...string) bool {
+	text := " " + strings.Join(words(s), " ") + " "
+	for _, phrase := range phrases {
+		if strings.Contains(text, " "+phrase+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCategory(t plaid.AssetReportTransaction, category string) bool {
+	for _, c := range t.Category {
+		if strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNSF reports whether a transaction is an insufficient funds fee or a
+// returned item.
+func isNSF(t plaid.AssetReportTransaction) bool {
+	if hasCategory(t, "Insufficient Funds") {
+		return true
+	}
+	description := t.Name + " " + t.OriginalDescription
+	return containsPhrase(description, "NSF", "INSUFFICIENT FUNDS", "RETURNED ITEM")
+}
+
+// isIncomeDeposit reports whether a transaction is a payroll or direct
+// deposit. Plaid reports money moving into an account as a negative amount.
+func isIncomeDeposit(t plaid.AssetReportTransaction) bool {
+	if t.Amount >= 0 || t.Pending {
+		return false
+	}
+	if hasCategory(t, "Payroll") {
+		return true
+	}
+	description := t.Name + " " + t.OriginalDescription
+	return containsPhrase(description, "PAYROLL", "DIRECT DEP", "DIRECT DEPOSIT", "DIR DEP")
+}
+
+// SummarizeAccount computes the metrics of an account as of the given day,
+// usually the date the report was generated. A zero asOf uses the date of the
+// account's latest historical balance.
+func SummarizeAccount(account plaid.AssetReportAccount, asOf time.Time) (AccountSummary, error) {
+	summary, _, err := summarizeAccount(account, asOf)
+	return summary, err
+}
+
+func summarizeAccount(account plaid.AssetReportAccount, asOf time.Time) (AccountSummary, series, error) {
+	if !asOf.IsZero() {
+		asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	s, gaps, err := dailySeries(account.HistoricalBalances, asOf)
+	if err != nil {
+		return AccountSummary{}, series{}, fmt.Errorf("underwriting: account %s: %w", account.AccountID, err)
+	}
+
+	summary := AccountSummary{
+		AccountID:      account.AccountID,
+		Type:           account.Type,
+		Currency:       account.Balances.Currency(),
+		CurrentBalance: account.Balances.Current,
+		Metrics:        s.metrics(),
+		Gaps:           gaps,
+	}
+	if summary.Currency == "" && len(account.HistoricalBalances) > 0 {
+		summary.Currency = account.HistoricalBalances[0].Currency()
+	}
+	for _, t := range account.Transactions {
+		if isNSF(t) {
+			summary.NSFCount++
+		}
+		if isIncomeDeposit(t) {
+			summary.IncomeDeposits++
+			summary.IncomeDepositTotal -= t.Amount
+		}
+	}
+	return summary, s, nil
+}
+
+// Summarize computes the metrics of every account in an Asset Report, and of
+// the report as a whole, as of the date the report was generated.
+func Summarize(report plaid.AssetReport, options Options) (ReportSummary, error) {
+	summary := ReportSummary{
+		AssetReportID: report.AssetReportID,
+		Currency:      options.Currency,
+	}
+	if report.DateGenerated != "" {
+		generated, err := parseDate(report.DateGenerated)
+		if err != nil {
+			return summary, fmt.Errorf("underwriting: invalid report date %q", report.DateGenerated)
+		}
+		summary.DateGenerated = generated
+	}
+
+	var all []series
+	for _, item := range report.Items {
+		for _, account := range item.Accounts {
+			accountSummary, s, err := summarizeAccount(account, summary.DateGenerated)
+			if err != nil {
+				return summary, err
+			}
+			summary.Accounts = append(summary.Accounts, accountSummary)
+			if accountSummary.Type != "depository" {
+				continue
+			}
+			if summary.Currency == "" {
+				summary.Currency = accountSummary.Currency
+			}
+
+			convert := func(amount float64) (float64, error) { return amount, nil }
+			if accountSummary.Currency != summary.Currency {
+				if options.Converter == nil {
+					return summary, fmt.Errorf("underwriting: account %s is in %s, not %s, and no converter was given",
+						account.AccountID, accountSummary.Currency, summary.Currency)
+				}
+				convert = func(amount float64) (float64, error) {
+					return options.Converter.Convert(amount, accountSummary.Currency, summary.Currency)
+				}
+			}
+
+			converted := series{start: s.start, balances: make([]float64, len(s.balances))}
+			for i, b := range s.balances {
+				if converted.balances[i], err = convert(b); err != nil {
+					return summary, fmt.Errorf("underwriting: account %s: %w", account.AccountID, err)
+				}
+			}
+			current, err := convert(accountSummary.CurrentBalance)
+			if err != nil {
+				return summary, fmt.Errorf("underwriting: account %s: %w", account.AccountID, err)
+			}
+			income, err := convert(accountSummary.IncomeDepositTotal)
+			if err != nil {
+				return summary, fmt.Errorf("underwriting: account %s: %w", account.AccountID, err)
+			}
+
+			summary.CurrentBalance += current
+			summary.IncomeDepositTotal += income
+			summary.NSFCount += accountSummary.NSFCount
+			summary.IncomeDeposits += accountSummary.IncomeDeposits
+			if len(converted.balances) > 0 {
+				all = append(all, converted)
+			}
+		}
+	}
+
+	summary.Metrics = combine(all).metrics()
+	negativeBalanceDays, overdraftEvents := 0, 0
+	for _, a := range summary.Accounts {
+		if a.Type != "depository" {
+			continue
+		}
+		negativeBalanceDays += a.NegativeBalanceDays
+		overdraftEvents += a.OverdraftEvents
+	}
+	summary.NegativeBalanceDays = negativeBalanceDays
+	summary.OverdraftEvents = overdraftEvents
+	return summary, nil
+}
+
+// combine adds up daily series. Series that start later contribute nothing to
+// the days before their start.
+func combine(all []series) series {
+	if len(all) == 0 {
+		return series{}
+	}
+	start, end := all[0].start, all[0].end()
+	for _, s := range all[1:] {
+		if s.start.Before(start) {
+			start = s.start
+		}
+		if s.end().After(end) {
+			end = s.end()
+		}
+	}
+
+	combined := series{start: start, balances: make([]float64, daysBetween(start, end)+1)}
+	for _, s := range all {
+		offset := daysBetween(start, s.start)
+		for i, b := range s.balances {
+			combined.balances[offset+i] += b
+		}
+		// Carry the last balance of a series ending early forward.
+		last := s.balances[len(s.balances)-1]
+		for i := offset + len(s.balances); i < len(combined.balances); i++ {
+			combined.balances[i] += last
+		}
+	}
+	return combined
+}
@@ -0,0 +1,159 @@
+package underwriting
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/perchcredit/plaid-go/v6/plaid"
+	assert "github.com/stretchr/testify/require"
+)
+
+func loadReport(t *testing.T, name string) plaid.AssetReport {
+	data, err := os.ReadFile("testdata/" + name)
+	assert.Nil(t, err)
+	var report plaid.AssetReport
+	assert.Nil(t, json.Unmarshal(data, &report))
+	return report
+}
+
+func date(s string) time.Time {
+	t, _ := time.Parse(dateLayout, s)
+	return t
+}
+
+func TestSummarizeAccount(t *testing.T) {
+	report := loadReport(t, "report.json")
+	checking := report.Items[0].Accounts[0]
+
+	summary, err := SummarizeAccount(checking, date("2021-03-31"))
+	assert.Nil(t, err)
+	assert.Equal(t, "checking", summary.AccountID)
+	assert.Equal(t, plaid.Currency("USD"), summary.Currency)
+	assert.Equal(t, 120.0, summary.CurrentBalance)
+
+	// Daily balances: 100, 50, -20, -20, -20, 30, -10, 200, 150, 120.
+	assert.Equal(t, 10, summary.DaysOfHistory)
+	assert.Equal(t, date("2021-03-22"), summary.HistoryStart)
+	assert.Equal(t, date("2021-03-31"), summary.HistoryEnd)
+	assert.InDelta(t, 58.0, summary.AverageDailyBalance30, 1e-9)
+	assert.InDelta(t, 58.0, summary.AverageDailyBalance90, 1e-9)
+	assert.Equal(t, -20.0, summary.MinimumBalance)
+	assert.Equal(t, date("2021-03-24"), summary.MinimumBalanceDate)
+	assert.Equal(t, 4, summary.NegativeBalanceDays)
+	assert.Equal(t, 2, summary.OverdraftEvents)
+	assert.InDelta(t, 76.1315, summary.BalanceVolatility, 1e-4)
+	assert.Equal(t, []Gap{{Start: date("2021-03-25"), End: date("2021-03-26")}}, summary.Gaps)
+	assert.Equal(t, 2, summary.Gaps[0].Days())
+
+	assert.Equal(t, 1, summary.NSFCount)
+	assert.Equal(t, 2, summary.IncomeDeposits)
+	assert.Equal(t, 2000.0, summary.IncomeDepositTotal)
+}
+
+func TestSummarizeAccountWindows(t *testing.T) {
+	report := loadReport(t, "report.json")
+	savings := report.Items[0].Accounts[1]
+
+	// 1000 from January 1st to March 1st, then 4000 until March 31st.
+	summary, err := SummarizeAccount(savings, date("2021-03-31"))
+	assert.Nil(t, err)
+	assert.Equal(t, 90, summary.DaysOfHistory)
+	assert.InDelta(t, 4000.0, summary.AverageDailyBalance30, 1e-9)
+	assert.InDelta(t, 2500.0, summary.AverageDailyBalance60, 1e-9)
+	assert.InDelta(t, 2000.0, summary.AverageDailyBalance90, 1e-9)
+	assert.InDelta(t, 1414.2136, summary.BalanceVolatility, 1e-4)
+	assert.Equal(t, 0, summary.OverdraftEvents)
+	assert.Len(t, summary.Gaps, 1)
+	assert.Equal(t, 59, summary.Gaps[0].Days())
+
+	// Balances are carried forward to the as of date.
+	summary, err = SummarizeAccount(savings, date("2021-04-09"))
+	assert.Nil(t, err)
+	assert.Equal(t, 99, summary.DaysOfHistory)
+	assert.InDelta(t, 4000.0, summary.AverageDailyBalance30, 1e-9)
+	assert.Len(t, summary.Gaps, 1)
+}
+
+func TestSummarize(t *testing.T) {
+	summary, err := Summarize(loadReport(t, "report.json"), Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, "report-1", summary.AssetReportID)
+	assert.Equal(t, date("2021-03-31"), summary.DateGenerated)
+	assert.Equal(t, plaid.Currency("USD"), summary.Currency)
+	assert.Len(t, summary.Accounts, 3)
+
+	// The credit card balance is owed, so only the checking and savings
+	// accounts count towards the totals.
+	assert.Equal(t, "credit", summary.Accounts[2].Type)
+	assert.Equal(t, 500.0, summary.Accounts[2].CurrentBalance)
+	assert.Equal(t, 4120.0, summary.CurrentBalance)
+	assert.Equal(t, 90, summary.DaysOfHistory)
+	assert.InDelta(t, (30*4000+580)/30.0, summary.AverageDailyBalance30, 1e-9)
+	assert.InDelta(t, (30*1000+30*4000+580)/60.0, summary.AverageDailyBalance60, 1e-9)
+	assert.InDelta(t, (60*1000+30*4000+580)/90.0, summary.AverageDailyBalance90, 1e-9)
+	assert.Equal(t, 1000.0, summary.MinimumBalance)
+	assert.Equal(t, date("2021-01-01"), summary.MinimumBalanceDate)
+	assert.Equal(t, 4, summary.NegativeBalanceDays)
+	assert.Equal(t, 2, summary.OverdraftEvents)
+	assert.Equal(t, 1, summary.NSFCount)
+	assert.Equal(t, 2, summary.IncomeDeposits)
+	assert.Equal(t, 2000.0, summary.IncomeDepositTotal)
+}
+
+func TestSummarizeCurrencies(t *testing.T) {
+	report := loadReport(t, "multi_currency_report.json")
+
+	_, err := Summarize(report, Options{})
+	assert.NotNil(t, err)
+
+	converter := plaid.StaticRateConverter{Base: "USD", Rates: map[plaid.Currency]float64{"GBP": 0.8}}
+	summary, err := Summarize(report, Options{Converter: converter})
+	assert.Nil(t, err)
+	assert.Equal(t, plaid.Currency("USD"), summary.Currency)
+	assert.InDelta(t, 162.5, summary.CurrentBalance, 1e-9)
+	assert.InDelta(t, 87.5, summary.MinimumBalance, 1e-9)
+	assert.InDelta(t, 125.0, summary.AverageDailyBalance30, 1e-9)
+	assert.Equal(t, 1, summary.NegativeBalanceDays)
+	assert.Equal(t, -10.0, summary.Accounts[1].MinimumBalance)
+
+	summary, err = Summarize(report, Options{Currency: "GBP", Converter: converter})
+	assert.Nil(t, err)
+	assert.InDelta(t, 130.0, summary.CurrentBalance, 1e-9)
+}
+
+func TestTransactionClassification(t *testing.T) {
+	tests := []struct {
+		name   string
+		txn    plaid.AssetReportTransaction
+		nsf    bool
+		income bool
+	}{
+		{"nsf category", plaid.AssetReportTransaction{Amount: 35, Category: []string{"Bank Fees", "Insufficient Funds"}}, true, false},
+		{"nsf description", plaid.AssetReportTransaction{Amount: 35, OriginalDescription: "NSF FEE"}, true, false},
+		{"transfer is not nsf", plaid.AssetReportTransaction{Amount: 35, Name: "TRANSFER TO SAVINGS"}, false, false},
+		{"payroll category", plaid.AssetReportTransaction{Amount: -1000, Category: []string{"Transfer", "Payroll"}}, false, true},
+		{"direct deposit", plaid.AssetReportTransaction{Amount: -1000, OriginalDescription: "ACME DIRECT DEP"}, false, true},
+		{"pending deposit", plaid.AssetReportTransaction{Amount: -1000, Pending: true, Name: "PAYROLL"}, false, false},
+		{"payroll debit", plaid.AssetReportTransaction{Amount: 1000, Name: "PAYROLL SERVICE FEE"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.nsf, isNSF(tt.txn))
+			assert.Equal(t, tt.income, isIncomeDeposit(tt.txn))
+		})
+	}
+}
+
+func TestInvalidDates(t *testing.T) {
+	_, err := SummarizeAccount(plaid.AssetReportAccount{
+		AccountID:          "a",
+		HistoricalBalances: []plaid.HistoricalBalance{{Date: "03/31/2021"}},
+	}, time.Time{})
+	assert.NotNil(t, err)
+
+	summary, err := SummarizeAccount(plaid.AssetReportAccount{AccountID: "a"}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, summary.DaysOfHistory)
+}
@@ -1,85 +1,58 @@
 package plaid
 
-import (
-	"fmt"
-	"io"
-	"strconv"
-
-	"github.com/99designs/gqlgen/graphql"
-	"github.com/fatih/structs"
-)
-
-type UnofficialCurrencyCode string
+// UnofficialCurrencyCode is a Currency that has no ISO 4217 code.
+//
+// Deprecated: use Currency, which UnofficialCurrencyCodes are typed as.
+type UnofficialCurrencyCode = Currency
 
 const (
-	unofficialCurrencyCodeADA  UnofficialCurrencyCode = "ADA"
-	unofficialCurrencyCodeBAT  UnofficialCurrencyCode = "BAT"
-	unofficialCurrencyCodeBCH  UnofficialCurrencyCode = "BCH"
-	unofficialCurrencyCodeBNB  UnofficialCurrencyCode = "BNB"
-	unofficialCurrencyCodeBTC  UnofficialCurrencyCode = "BTC"
-	unofficialCurrencyCodeBTG  UnofficialCurrencyCode = "BTG"
-	unofficialCurrencyCodeCNH  UnofficialCurrencyCode = "CNH"
-	unofficialCurrencyCodeDASH UnofficialCurrencyCode = "DASH"
-	unofficialCurrencyCodeDOGE UnofficialCurrencyCode = "DOGE"
-	unofficialCurrencyCodeETC  UnofficialCurrencyCode = "ETC"
-	unofficialCurrencyCodeETH  UnofficialCurrencyCode = "ETH"
-	unofficialCurrencyCodeGBX  UnofficialCurrencyCode = "GBX"
-	unofficialCurrencyCodeLSK  UnofficialCurrencyCode = "LSK"
-	unofficialCurrencyCodeNEO  UnofficialCurrencyCode = "NEO"
-	unofficialCurrencyCodeOMG  UnofficialCurrencyCode = "OMG"
-	unofficialCurrencyCodeQTUM UnofficialCurrencyCode = "QTUM"
-	unofficialCurrencyCodeUSDT UnofficialCurrencyCode = "USDT"
-	unofficialCurrencyCodeXLM  UnofficialCurrencyCode = "XLM"
-	unofficialCurrencyCodeXMR  UnofficialCurrencyCode = "XMR"
-	unofficialCurrencyCodeXRP  UnofficialCurrencyCode = "XRP"
-	unofficialCurrencyCodeZEC  UnofficialCurrencyCode = "ZEC"
-	unofficialCurrencyCodeZRX  UnofficialCurrencyCode = "ZRX"
+	unofficialCurrencyCodeADA  Currency = "ADA"
+	unofficialCurrencyCodeBAT  Currency = "BAT"
+	unofficialCurrencyCodeBCH  Currency = "BCH"
+	unofficialCurrencyCodeBNB  Currency = "BNB"
+	unofficialCurrencyCodeBTC  Currency = "BTC"
+	unofficialCurrencyCodeBTG  Currency = "BTG"
+	unofficialCurrencyCodeCNH  Currency = "CNH"
+	unofficialCurrencyCodeDASH Currency = "DASH"
+	unofficialCurrencyCodeDOGE Currency = "DOGE"
+	unofficialCurrencyCodeETC  Currency = "ETC"
+	unofficialCurrencyCodeETH  Currency = "ETH"
+	unofficialCurrencyCodeGBX  Currency = "GBX"
+	unofficialCurrencyCodeLSK  Currency = "LSK"
+	unofficialCurrencyCodeNEO  Currency = "NEO"
+	unofficialCurrencyCodeOMG  Currency = "OMG"
+	unofficialCurrencyCodeQTUM Currency = "QTUM"
+	unofficialCurrencyCodeUSDT Currency = "USDT"
+	unofficialCurrencyCodeXLM  Currency = "XLM"
+	unofficialCurrencyCodeXMR  Currency = "XMR"
+	unofficialCurrencyCodeXRP  Currency = "XRP"
+	unofficialCurrencyCodeZEC  Currency = "ZEC"
+	unofficialCurrencyCodeZRX  Currency = "ZRX"
 )
 
-// MarshalUUID converts a uuid.UUID to a graphql string
-func MarshalUnofficialCurrencyCode(s UnofficialCurrencyCode) graphql.Marshaler {
-	return graphql.WriterFunc(func(w io.Writer) {
-		io.WriteString(w, strconv.Quote(string(s)))
-	})
-}
-
-// UnmarshalUUID converts a graphql uuid string type into a uuid.UUID
-func UnmarshalUnofficialCurrencyCode(v interface{}) (UnofficialCurrencyCode, error) {
-	switch v := v.(type) {
-	case string:
-		if IsValidCode(v) {
-			return UnofficialCurrencyCode(v), nil
-		} else {
-			return "", fmt.Errorf("%T is not a valid unofficial currency code", v)
-		}
-	default:
-		return "", fmt.Errorf("%T is not a unofficial currency code", v)
-	}
-}
-
 type unofficialCurrencyCodes struct {
-	ADA  UnofficialCurrencyCode
-	BAT  UnofficialCurrencyCode
-	BCH  UnofficialCurrencyCode
-	BNB  UnofficialCurrencyCode
-	BTC  UnofficialCurrencyCode
-	BTG  UnofficialCurrencyCode
-	CNH  UnofficialCurrencyCode
-	DASH UnofficialCurrencyCode
-	DOGE UnofficialCurrencyCode
-	ETC  UnofficialCurrencyCode
-	ETH  UnofficialCurrencyCode
-	GBX  UnofficialCurrencyCode
-	LSK  UnofficialCurrencyCode
-	NEO  UnofficialCurrencyCode
-	OMG  UnofficialCurrencyCode
-	QTUM UnofficialCurrencyCode
-	USDT UnofficialCurrencyCode
-	XLM  UnofficialCurrencyCode
-	XMR  UnofficialCurrencyCode
-	XRP  UnofficialCurrencyCode
-	ZEC  UnofficialCurrencyCode
-	ZRX  UnofficialCurrencyCode
+	ADA  Currency
+	BAT  Currency
+	BCH  Currency
+	BNB  Currency
+	BTC  Currency
+	BTG  Currency
+	CNH  Currency
+	DASH Currency
+	DOGE Currency
+	ETC  Currency
+	ETH  Currency
+	GBX  Currency
+	LSK  Currency
+	NEO  Currency
+	OMG  Currency
+	QTUM Currency
+	USDT Currency
+	XLM  Currency
+	XMR  Currency
+	XRP  Currency
+	ZEC  Currency
+	ZRX  Currency
 }
 
 var UnofficialCurrencyCodes unofficialCurrencyCodes = unofficialCurrencyCodes{
@@ -107,11 +80,7 @@ var UnofficialCurrencyCodes unofficialCurrencyCodes = unofficialCurrencyCodes{
 	ZRX:  unofficialCurrencyCodeZRX,
 }
 
+// IsValidCode reports whether s is one of the unofficial currency codes above.
 func IsValidCode(s string) bool {
-	for _, value := range structs.Map(UnofficialCurrencyCodes) {
-		if string(value.(string)) == s {
-			return true
-		}
-	}
-	return false
+	return Currency(s).IsUnofficial()
 }
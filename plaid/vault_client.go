@@ -0,0 +1,173 @@
+package plaid
+
+import (
+	"errors"
+	"fmt"
+)
+
+// VaultClient calls Plaid on behalf of Items identified by item ID, looking up
+// their access tokens in a TokenVault. It lets an application keep access
+// tokens out of its own database and code paths.
+type VaultClient struct {
+	client *Client
+	vault  TokenVault
+}
+
+// NewVaultClient returns a VaultClient that calls Plaid with client and
+// resolves access tokens from vault.
+func NewVaultClient(client *Client, vault TokenVault) *VaultClient {
+	return &VaultClient{client: client, vault: vault}
+}
+
+// Client returns the underlying client, for calls the VaultClient does not
+// wrap.
+func (v *VaultClient) Client() *Client {
+	return v.client
+}
+
+// Vault returns the vault the VaultClient resolves access tokens from.
+func (v *VaultClient) Vault() TokenVault {
+	return v.vault
+}
+
+// WithAccessToken calls fn with the access token of an Item, for calls the
+// VaultClient does not wrap.
+func (v *VaultClient) WithAccessToken(itemID string, fn func(accessToken string) error) error {
+	accessToken, err := v.accessToken(itemID)
+	if err != nil {
+		return err
+	}
+	return fn(accessToken)
+}
+
+func (v *VaultClient) accessToken(itemID string) (string, error) {
+	if itemID == "" {
+		return "", errors.New("vault client - item id must be specified")
+	}
+	return v.vault.AccessToken(itemID)
+}
+
+// ExchangePublicToken exchanges a public token for an access token, and
+// stores the access token in the vault under the new Item's ID. The response
+// still holds the access token, for applications that need it.
+func (v *VaultClient) ExchangePublicToken(publicToken string) (resp ExchangePublicTokenResponse, err error) {
+	resp, err = v.client.ExchangePublicToken(publicToken)
+	if err != nil {
+		return resp, err
+	}
+	return resp, v.vault.PutAccessToken(resp.ItemID, resp.AccessToken)
+}
+
+func (v *VaultClient) GetItem(itemID string) (resp GetItemResponse, err error) {
+	accessToken, err := v.accessToken(itemID)
+	if err != nil {
+		return resp, err
+	}
+	return v.client.GetItem(accessToken)
+}
+
+func (v *VaultClient) GetAccounts(itemID string) (resp GetAccountsResponse, err error) {
+	accessToken, err := v.accessToken(itemID)
+	if err != nil {
+		return resp, err
+	}
+	return v.client.GetAccounts(accessToken)
+}
+
+func (v *VaultClient) GetBalances(itemID string) (resp GetBalancesResponse, err error) {
+	accessToken, err := v.accessToken(itemID)
+	if err != nil {
+		return resp, err
+	}
+	return v.client.GetBalances(accessToken)
+}
+
+func (v *VaultClient) GetAuth(itemID string) (resp GetAuthResponse, err error) {
+	accessToken, err := v.accessToken(itemID)
+	if err != nil {
+		return resp, err
+	}
+	return v.client.GetAuth(accessToken)
+}
+
+func (v *VaultClient) GetIdentity(itemID string) (resp GetIdentityResponse, err error) {
+	accessToken, err := v.accessToken(itemID)
+	if err != nil {
+		return resp, err
+	}
+	return v.client.GetIdentity(accessToken)
+}
+
+func (v *VaultClient) GetTransactions(itemID, startDate, endDate string) (resp GetTransactionsResponse, err error) {
+	accessToken, err := v.accessToken(itemID)
+	if err != nil {
+		return resp, err
+	}
+	return v.client.GetTransactions(accessToken, startDate, endDate)
+}
+
+func (v *VaultClient) GetTransactionsWithOptions(itemID string, options GetTransactionsOptions) (resp GetTransactionsResponse, err error) {
+	accessToken, err := v.accessToken(itemID)
+	if err != nil {
+		return resp, err
+	}
+	return v.client.GetTransactionsWithOptions(accessToken, options)
+}
+
+// RemoveItem removes an Item from Plaid and then deletes its access token from
+// the vault.
+func (v *VaultClient) RemoveItem(itemID string) (resp RemoveItemResponse, err error) {
+	accessToken, err := v.accessToken(itemID)
+	if err != nil {
+		return resp, err
+	}
+	resp, err = v.client.RemoveItem(accessToken)
+	if err != nil {
+		return resp, err
+	}
+	return resp, v.vault.DeleteAccessToken(itemID)
+}
+
+// AccessTokenRotationError is returned by RotateAccessToken when Plaid
+// invalidated an Item's access token but the vault failed to store the new
+// one. The old token no longer works, so NewAccessToken must be stored by
+// other means.
+type AccessTokenRotationError struct {
+	ItemID         string
+	NewAccessToken string
+	Err            error
+}
+
+// Error does not include the new access token, so that it does not end up in
+// logs.
+func (e *AccessTokenRotationError) Error() string {
+	return fmt.Sprintf("vault client - access token of item %s was rotated but could not be stored: %v", e.ItemID, e.Err)
+}
+
+func (e *AccessTokenRotationError) Unwrap() error {
+	return e.Err
+}
+
+// RotateAccessToken replaces the access token of an Item with a new one from
+// InvalidateAccessToken. The vault is only updated once Plaid has issued the
+// new token, and concurrent rotations of the same Item are serialized so that
+// each invalidates the token stored by the previous one.
+func (v *VaultClient) RotateAccessToken(itemID string) (resp InvalidateAccessTokenResponse, err error) {
+	if itemID == "" {
+		return resp, errors.New("vault client - item id must be specified")
+	}
+
+	var rotated bool
+	err = v.vault.UpdateAccessToken(itemID, func(accessToken string) (string, error) {
+		resp, err = v.client.InvalidateAccessToken(accessToken)
+		if err != nil {
+			return "", err
+		}
+		rotated = true
+		return resp.NewAccessToken, nil
+	})
+	if err != nil && rotated {
+		return resp, &AccessTokenRotationError{ItemID: itemID, NewAccessToken: resp.NewAccessToken, Err: err}
+	}
+	return resp, err
+}
@@ -0,0 +1,129 @@
+package plaid
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+// failingVault fails to store tokens once they have been read.
+type failingVault struct {
+	TokenVault
+}
+
+func (v failingVault) PutAccessToken(itemID, accessToken string) error {
+	return errors.New("disk full")
+}
+
+func (v failingVault) UpdateAccessToken(itemID string, update func(string) (string, error)) error {
+	current, err := v.AccessToken(itemID)
+	if err != nil {
+		return err
+	}
+	if _, err := update(current); err != nil {
+		return err
+	}
+	return errors.New("disk full")
+}
+
+func newTestVaultClient(t *testing.T) (*fakeServer, *VaultClient) {
+	server, client := newFakeServer(t)
+	vault := NewMemoryTokenVault(newTestCipher(t))
+	assert.NoError(t, vault.PutAccessToken("item-1", "access-1"))
+	return server, NewVaultClient(client, vault)
+}
+
+func TestVaultClientResolvesAccessTokens(t *testing.T) {
+	server, client := newTestVaultClient(t)
+	server.handle("/accounts/get", func(body map[string]interface{}) (int, interface{}) {
+		assert.Equal(t, "access-1", body["access_token"])
+		return http.StatusOK, map[string]interface{}{
+			"accounts": []interface{}{map[string]interface{}{"account_id": "acc-1"}},
+		}
+	})
+
+	resp, err := client.GetAccounts("item-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "acc-1", resp.Accounts[0].AccountID)
+
+	_, err = client.GetAccounts("item-2")
+	assert.True(t, errors.Is(err, ErrAccessTokenNotFound))
+	_, err = client.GetAccounts("")
+	assert.Error(t, err)
+	assert.Equal(t, 1, server.callCount("/accounts/get"))
+
+	var seen string
+	assert.NoError(t, client.WithAccessToken("item-1", func(accessToken string) error {
+		seen = accessToken
+		return nil
+	}))
+	assert.Equal(t, "access-1", seen)
+}
+
+func TestVaultClientExchangeAndRemove(t *testing.T) {
+	server, client := newTestVaultClient(t)
+	server.handle("/item/public_token/exchange", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"access_token": "access-2", "item_id": "item-2"}
+	})
+	server.handle("/item/remove", func(body map[string]interface{}) (int, interface{}) {
+		assert.Equal(t, "access-2", body["access_token"])
+		return http.StatusOK, map[string]interface{}{}
+	})
+
+	_, err := client.ExchangePublicToken("public-1")
+	assert.NoError(t, err)
+	token, err := client.Vault().AccessToken("item-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "access-2", token)
+
+	_, err = client.RemoveItem("item-2")
+	assert.NoError(t, err)
+	_, err = client.Vault().AccessToken("item-2")
+	assert.True(t, errors.Is(err, ErrAccessTokenNotFound))
+}
+
+func TestRotateAccessToken(t *testing.T) {
+	server, client := newTestVaultClient(t)
+	server.handle("/item/access_token/invalidate", func(body map[string]interface{}) (int, interface{}) {
+		assert.Equal(t, "access-1", body["access_token"])
+		return http.StatusOK, map[string]interface{}{"new_access_token": "access-1b"}
+	})
+
+	resp, err := client.RotateAccessToken("item-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1b", resp.NewAccessToken)
+	token, err := client.Vault().AccessToken("item-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1b", token)
+}
+
+func TestRotateAccessTokenFailures(t *testing.T) {
+	server, client := newTestVaultClient(t)
+	server.handle("/item/access_token/invalidate", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusBadRequest, map[string]interface{}{
+			"error_type": "INVALID_INPUT",
+			"error_code": "INVALID_ACCESS_TOKEN",
+		}
+	})
+
+	// The stored token is kept when Plaid fails to rotate it.
+	_, err := client.RotateAccessToken("item-1")
+	assert.Error(t, err)
+	var rotationErr *AccessTokenRotationError
+	assert.False(t, errors.As(err, &rotationErr))
+	token, _ := client.Vault().AccessToken("item-1")
+	assert.Equal(t, "access-1", token)
+
+	// The new token is returned when the vault fails to store it.
+	server.handle("/item/access_token/invalidate", func(body map[string]interface{}) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"new_access_token": "access-1b"}
+	})
+	failing := NewVaultClient(client.Client(), failingVault{client.Vault()})
+	_, err = failing.RotateAccessToken("item-1")
+	assert.True(t, errors.As(err, &rotationErr))
+	assert.Equal(t, "item-1", rotationErr.ItemID)
+	assert.Equal(t, "access-1b", rotationErr.NewAccessToken)
+	assert.NotContains(t, err.Error(), "access-1b")
+}
@@ -3,8 +3,45 @@ package plaid
 import (
 	"encoding/json"
 	"errors"
+	"time"
 )
 
+// Webhook holds the fields shared by the webhooks Plaid sends. Fields that
+// only apply to some webhook types are left at their zero value otherwise.
+// See https://plaid.com/docs/api/webhooks/.
+type Webhook struct {
+	WebhookType string `json:"webhook_type"`
+	WebhookCode string `json:"webhook_code"`
+	ItemID      string `json:"item_id"`
+	Error       *Error `json:"error"`
+
+	// Sent with TRANSACTIONS webhooks.
+	NewTransactions     int      `json:"new_transactions"`
+	RemovedTransactions []string `json:"removed_transactions"`
+
+	// Sent with ASSETS webhooks.
+	AssetReportID string `json:"asset_report_id"`
+	ReportType    string `json:"report_type"`
+
+	// Sent with ITEM PENDING_EXPIRATION webhooks.
+	ConsentExpirationTime time.Time `json:"consent_expiration_time"`
+
+	// Sent with PAYMENT_INITIATION webhooks.
+	PaymentID        string        `json:"payment_id"`
+	NewPaymentStatus PaymentStatus `json:"new_payment_status"`
+	OldPaymentStatus PaymentStatus `json:"old_payment_status"`
+	Timestamp        time.Time     `json:"timestamp"`
+}
+
+// ParseWebhook decodes the JSON body of a webhook request.
+func ParseWebhook(body []byte) (webhook Webhook, err error) {
+	err = json.Unmarshal(body, &webhook)
+	if err == nil && (webhook.WebhookType == "" || webhook.WebhookCode == "") {
+		err = errors.New("webhook - webhook_type and webhook_code must be present")
+	}
+	return webhook, err
+}
+
 type WebhookVerificationKey struct {
 	Alg       string `json:"alg"`
 	CreatedAt int64  `json:"created_at"`
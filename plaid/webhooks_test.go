@@ -20,3 +20,25 @@ func TestGetWebhookVerificationKey(t *testing.T) {
 	assert.NotNil(t, webhookResp.Key.X)
 	assert.NotNil(t, webhookResp.Key.Y)
 }
+
+func TestParseWebhook(t *testing.T) {
+	webhook, err := ParseWebhook([]byte(`{
+		"webhook_type": "TRANSACTIONS",
+		"webhook_code": "DEFAULT_UPDATE",
+		"item_id": "item-1",
+		"error": null,
+		"new_transactions": 3
+	}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "TRANSACTIONS", webhook.WebhookType)
+	assert.Equal(t, "DEFAULT_UPDATE", webhook.WebhookCode)
+	assert.Equal(t, "item-1", webhook.ItemID)
+	assert.Nil(t, webhook.Error)
+	assert.Equal(t, 3, webhook.NewTransactions)
+
+	_, err = ParseWebhook([]byte(`{"item_id": "item-1"}`))
+	assert.NotNil(t, err)
+
+	_, err = ParseWebhook([]byte(`not json`))
+	assert.NotNil(t, err)
+}